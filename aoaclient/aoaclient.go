@@ -2,6 +2,7 @@ package aoaclient
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/common/hexutil"
 	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/rlp"
 	"github.com/Aurorachain/go-Aurora/rpc"
 )
@@ -261,6 +263,89 @@ func (ec *Client) GetDelegateList(ctx context.Context, blockNumber *big.Int) ([]
 	return result, err
 }
 
+// TopDelegateList returns the delegates with the highest weight at the given block,
+// at most as many as an election picks. Jailed delegates are left out.
+func (ec *Client) TopDelegateList(ctx context.Context, blockNumber *big.Int) ([]types.Candidate, error) {
+	var result []types.Candidate
+	err := ec.c.CallContext(ctx, &result, "aoa_getTopDelegateList", toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// Delegate returns the registered delegate with the given address as of the latest block.
+func (ec *Client) Delegate(ctx context.Context, account common.Address) (*types.Candidate, error) {
+	var result *types.Candidate
+	err := ec.c.CallContext(ctx, &result, "aoa_getDelegate", account)
+	if err == nil && result == nil {
+		return nil, aurora.NotFound
+	}
+	return result, err
+}
+
+// VotesNumberAt returns the whole AOA the account has locked in votes at the given
+// block. The node returns it as a plain JSON number.
+func (ec *Client) VotesNumberAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result big.Int
+	err := ec.c.CallContext(ctx, &result, "aoa_getVotesNumber", account, toBlockNumArg(blockNumber))
+	return &result, err
+}
+
+// CandidateVotesAt returns the weight and the voter count of the candidate at the
+// given block, both zero if the account is not a candidate.
+func (ec *Client) CandidateVotesAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*aurora.VotesNumber, error) {
+	var result aurora.VotesNumber
+	err := ec.c.CallContext(ctx, &result, "aoa_getCandidateVotes", account, toBlockNumArg(blockNumber))
+	return &result, err
+}
+
+// AssetBalanceAt returns the balance of the asset held by the account at the given
+// block. The node returns it as a plain JSON number.
+func (ec *Client) AssetBalanceAt(ctx context.Context, account common.Address, asset common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var result big.Int
+	err := ec.c.CallContext(ctx, &result, "aoa_getAssetBalance", account, asset, toBlockNumArg(blockNumber))
+	return &result, err
+}
+
+// DetailBalanceAt returns the AOA balances of the account keyed by AOA_balance,
+// AOA_lockBalance and AOA_totalBalance, plus one entry per held asset.
+func (ec *Client) DetailBalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (map[string]string, error) {
+	var result map[string]string
+	err := ec.c.CallContext(ctx, &result, "aoa_getDetailBalance", account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// AssetInfo returns the name, symbol, supply and issuer of the asset.
+func (ec *Client) AssetInfo(ctx context.Context, asset common.Address) (*types.AssetInfo, error) {
+	var result *types.AssetInfo
+	err := ec.c.CallContext(ctx, &result, "aoa_getAssetInfo", asset)
+	if err == nil && result == nil {
+		return nil, aurora.NotFound
+	}
+	return result, err
+}
+
+// AbiAt returns the ABI the contract account was deployed with at the given block.
+func (ec *Client) AbiAt(ctx context.Context, account common.Address, blockNumber *big.Int) (string, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "aoa_getAbi", account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// InnerTransactions returns the inner transactions recorded for the transaction. The
+// node only records them when inner transaction watching is enabled.
+func (ec *Client) InnerTransactions(ctx context.Context, txHash common.Hash) ([]*types.InnerTx, error) {
+	var r *struct {
+		InnerTxs []*types.InnerTx `json:"innerTxs"`
+	}
+	err := ec.c.CallContext(ctx, &r, "aoa_getTransactionReceipt", txHash)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, aurora.NotFound
+	}
+	return r.InnerTxs, nil
+}
+
 func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
 	var result hexutil.Uint64
 	err := ec.c.CallContext(ctx, &result, "aoa_getTransactionCount", account, toBlockNumArg(blockNumber))
@@ -346,6 +431,48 @@ func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	return (*big.Int)(&hex), nil
 }
 
+// TxOptions returns the options for building the next pending transaction of
+// account with the constructors in core/types, priced at the suggested gas price.
+// The gas limit is left at zero and must be set for contract transactions.
+func (ec *Client) TxOptions(ctx context.Context, account common.Address) (types.TxOptions, error) {
+	nonce, err := ec.PendingNonceAt(ctx, account)
+	if err != nil {
+		return types.TxOptions{}, err
+	}
+	price, err := ec.SuggestGasPrice(ctx)
+	if err != nil {
+		return types.TxOptions{}, err
+	}
+	return types.TxOptions{Nonce: nonce, GasPrice: price}, nil
+}
+
+// TxBuilder builds a transaction with the given options, typically with one of
+// the constructors in core/types.
+type TxBuilder func(opts types.TxOptions) (*types.Transaction, error)
+
+// SendNewTransaction builds the next pending transaction of the account of key
+// with build, signs it for the chain with chainID and sends it. The gas limit
+// of opts is left at zero, build sets it for contract transactions.
+//
+//	tx, err := client.SendNewTransaction(ctx, key, chainID, func(opts types.TxOptions) (*types.Transaction, error) {
+//		return types.NewRegisterTx(opts, from, "node-1")
+//	})
+func (ec *Client) SendNewTransaction(ctx context.Context, key *ecdsa.PrivateKey, chainID *big.Int, build TxBuilder) (*types.Transaction, error) {
+	opts, err := ec.TxOptions(ctx, crypto.PubkeyToAddress(key.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	tx, err := build(opts)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := types.SignTx(tx, types.NewAuroraSigner(chainID), key)
+	if err != nil {
+		return nil, err
+	}
+	return signed, ec.SendTransaction(ctx, signed)
+}
+
 func (ec *Client) EstimateGas(ctx context.Context, msg aurora.CallMsg) (uint64, error) {
 	var hex hexutil.Uint64
 	err := ec.c.CallContext(ctx, &hex, "aoa_estimateGas", toCallArg(msg))
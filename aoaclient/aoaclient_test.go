@@ -1,6 +1,20 @@
 package aoaclient
 
-import "github.com/Aurorachain/go-Aurora"
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rlp"
+	"github.com/Aurorachain/go-Aurora/rpc"
+)
 
 var (
 	_ = aurora.ChainReader(&Client{})
@@ -14,4 +28,208 @@ var (
 	_ = aurora.PendingStateReader(&Client{})
 
 	_ = aurora.PendingContractCaller(&Client{})
+
+	_ = aurora.AssetReader(&Client{})
+	_ = aurora.DelegateReader(&Client{})
+	_ = aurora.ContractAbiReader(&Client{})
+	_ = aurora.InnerTransactionReader(&Client{})
+)
+
+var (
+	testAccount  = common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	testAsset    = common.HexToAddress("0x8a8eafb1cf62bfbeb1741769dae1a9dd47996192")
+	testTxHash   = common.HexToHash("0x1111111111111111111111111111111111111111111111111111111111111111")
+	testDelegate = types.Candidate{Address: testAccount.Hex(), Vote: 12, Nickname: "node-1", RegisterTime: 1530000000}
 )
+
+// FakeAoaAPI mimics the aoa namespace served by a full node.
+type FakeAoaAPI struct{}
+
+func (api *FakeAoaAPI) GetAssetBalance(address common.Address, asset common.Address, blockNr rpc.BlockNumber) (*big.Int, error) {
+	if address != testAccount || asset != testAsset {
+		return big.NewInt(0), nil
+	}
+	return big.NewInt(4200), nil
+}
+
+func (api *FakeAoaAPI) GetDetailBalance(address common.Address, blockNr rpc.BlockNumber) (map[string]string, error) {
+	return map[string]string{"AOA_balance": "10", "AOA_lockBalance": "2", "AOA_totalBalance": "12", testAsset.String(): "4200"}, nil
+}
+
+func (api *FakeAoaAPI) GetAssetInfo(asset common.Address) (*types.AssetInfo, error) {
+	if asset != testAsset {
+		return nil, nil
+	}
+	return &types.AssetInfo{Issuer: &testAccount, Name: "Test", Symbol: "TST", Supply: big.NewInt(1000000), Desc: "test asset"}, nil
+}
+
+func (api *FakeAoaAPI) GetTopDelegateList(blockNr rpc.BlockNumber) (interface{}, error) {
+	return []types.Candidate{testDelegate}, nil
+}
+
+func (api *FakeAoaAPI) GetDelegate(address common.Address) interface{} {
+	if address == testAccount {
+		return testDelegate
+	}
+	return nil
+}
+
+func (api *FakeAoaAPI) GetVotesNumber(address common.Address, blockNr rpc.BlockNumber) (*big.Int, error) {
+	return big.NewInt(3), nil
+}
+
+func (api *FakeAoaAPI) GetCandidateVotes(address common.Address, blockNr rpc.BlockNumber) (*aurora.VotesNumber, error) {
+	return &aurora.VotesNumber{Weight: 12, Voters: 3}, nil
+}
+
+func (api *FakeAoaAPI) GetAbi(address common.Address, blockNr rpc.BlockNumber) (string, error) {
+	return `[{"type":"function","name":"f"}]`, nil
+}
+
+func (api *FakeAoaAPI) GetTransactionReceipt(hash common.Hash) (map[string]interface{}, error) {
+	if hash != testTxHash {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		"transactionHash": hash,
+		"innerTxs":        []*types.InnerTx{{From: testAccount, To: testAsset, Value: big.NewInt(7)}},
+	}, nil
+}
+
+func (api *FakeAoaAPI) GetTransactionCount(address common.Address, blockNr rpc.BlockNumber) (*hexutil.Uint64, error) {
+	nonce := hexutil.Uint64(5)
+	return &nonce, nil
+}
+
+func (api *FakeAoaAPI) GasPrice() *big.Int {
+	return big.NewInt(18000000000)
+}
+
+// sentRawTx is the last transaction sent to FakeAoaAPI.
+var sentRawTx hexutil.Bytes
+
+func (api *FakeAoaAPI) SendRawTransaction(encodedTx hexutil.Bytes) (common.Hash, error) {
+	sentRawTx = encodedTx
+	return common.Hash{}, nil
+}
+
+func newTestClient(t *testing.T) *Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("aoa", new(FakeAoaAPI)); err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(rpc.DialInProc(server))
+}
+
+func TestAssetReader(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	balance, err := client.AssetBalanceAt(ctx, testAccount, testAsset, nil)
+	if err != nil || balance.Cmp(big.NewInt(4200)) != 0 {
+		t.Errorf("AssetBalanceAt mismatch: have %v (%v), want 4200", balance, err)
+	}
+	detail, err := client.DetailBalanceAt(ctx, testAccount, big.NewInt(1))
+	if err != nil || detail["AOA_totalBalance"] != "12" || detail[testAsset.String()] != "4200" {
+		t.Errorf("DetailBalanceAt mismatch: have %v (%v)", detail, err)
+	}
+	info, err := client.AssetInfo(ctx, testAsset)
+	if err != nil || info.Symbol != "TST" || info.Supply.Cmp(big.NewInt(1000000)) != 0 || *info.Issuer != testAccount {
+		t.Errorf("AssetInfo mismatch: have %+v (%v)", info, err)
+	}
+	if _, err := client.AssetInfo(ctx, common.Address{}); err != aurora.NotFound {
+		t.Errorf("AssetInfo of unknown asset: have %v, want %v", err, aurora.NotFound)
+	}
+	abi, err := client.AbiAt(ctx, testAccount, nil)
+	if err != nil || abi != `[{"type":"function","name":"f"}]` {
+		t.Errorf("AbiAt mismatch: have %q (%v)", abi, err)
+	}
+}
+
+func TestDelegateReader(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	top, err := client.TopDelegateList(ctx, nil)
+	if err != nil || !reflect.DeepEqual(top, []types.Candidate{testDelegate}) {
+		t.Errorf("TopDelegateList mismatch: have %v (%v)", top, err)
+	}
+	delegate, err := client.Delegate(ctx, testAccount)
+	if err != nil || !reflect.DeepEqual(*delegate, testDelegate) {
+		t.Errorf("Delegate mismatch: have %v (%v)", delegate, err)
+	}
+	if _, err := client.Delegate(ctx, testAsset); err != aurora.NotFound {
+		t.Errorf("Delegate of unknown account: have %v, want %v", err, aurora.NotFound)
+	}
+	votes, err := client.VotesNumberAt(ctx, testAccount, nil)
+	if err != nil || votes.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("VotesNumberAt mismatch: have %v (%v), want 3", votes, err)
+	}
+	candidate, err := client.CandidateVotesAt(ctx, testAccount, nil)
+	if err != nil || *candidate != (aurora.VotesNumber{Weight: 12, Voters: 3}) {
+		t.Errorf("CandidateVotesAt mismatch: have %+v (%v), want weight 12, 3 voters", candidate, err)
+	}
+}
+
+func TestInnerTransactions(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	itxs, err := client.InnerTransactions(ctx, testTxHash)
+	if err != nil || len(itxs) != 1 || itxs[0].From != testAccount || itxs[0].Value.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("InnerTransactions mismatch: have %v (%v)", itxs, err)
+	}
+	if _, err := client.InnerTransactions(ctx, common.Hash{}); err != aurora.NotFound {
+		t.Errorf("InnerTransactions of unknown tx: have %v, want %v", err, aurora.NotFound)
+	}
+}
+
+func TestTxOptions(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	opts, err := client.TxOptions(ctx, testAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Nonce != 5 || opts.GasPrice.Cmp(big.NewInt(18000000000)) != 0 || opts.GasLimit != 0 {
+		t.Errorf("TxOptions mismatch: have %+v", opts)
+	}
+	tx, err := types.NewTransferTx(opts, testAsset, big.NewInt(1), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 5 || tx.Gas() != params.TxGas {
+		t.Errorf("transfer built from TxOptions mismatch: nonce %d gas %d", tx.Nonce(), tx.Gas())
+	}
+}
+
+func TestSendNewTransaction(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(60)
+	tx, err := client.SendNewTransaction(ctx, key, chainID, func(opts types.TxOptions) (*types.Transaction, error) {
+		return types.NewRegisterTx(opts, from, "node-1")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := new(types.Transaction)
+	if err := rlp.DecodeBytes(sentRawTx, sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Hash() != tx.Hash() || sent.Nonce() != 5 || sent.TxDataAction() != types.ActionRegister {
+		t.Errorf("sent transaction mismatch: have %x nonce %d action %d", sent.Hash(), sent.Nonce(), sent.TxDataAction())
+	}
+	if sender, err := types.Sender(types.NewAuroraSigner(chainID), sent); err != nil || sender != from {
+		t.Errorf("sender mismatch: have %x (%v), want %x", sender, err, from)
+	}
+	if _, err := client.SendNewTransaction(ctx, key, chainID, func(opts types.TxOptions) (*types.Transaction, error) {
+		return types.NewRegisterTx(opts, from, "")
+	}); err != types.ErrTxNickname {
+		t.Errorf("build error mismatch: have %v, want %v", err, types.ErrTxNickname)
+	}
+}
@@ -115,6 +115,24 @@ Remove blockchain and state databases`,
 The arguments are interpreted as block numbers or hashes.
 Use "aurora dump 0" to dump the genesis block.`,
 	}
+	hermesAssetsCommand = cli.Command{
+		Action:    utils.MigrateFlags(hermesAssets),
+		Name:      "hermesassets",
+		Usage:     "Print the assets the Hermes fork has to register",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The hermesassets command prints the assets of the state of a block as the
+hermesAssets table of the chain config, in JSON. The argument is interpreted as
+a block number or hash and defaults to the current head, which has to be before
+the Hermes fork block when the table is scheduled.
+
+If the chain config already lists the table, the command fails unless it matches.`,
+	}
 )
 
 func initGenesis(ctx *cli.Context) error {
@@ -361,6 +379,51 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func hermesAssets(ctx *cli.Context) error {
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	block := chain.CurrentBlock()
+	if arg := ctx.Args().First(); arg != "" {
+		if hashish(arg) {
+			block = chain.GetBlockByHash(common.HexToHash(arg))
+		} else {
+			num, _ := strconv.Atoi(arg)
+			block = chain.GetBlockByNumber(uint64(num))
+		}
+	}
+	if block == nil {
+		utils.Fatalf("block not found")
+	}
+	config := chain.Config()
+	if config.IsHermes(block.Number()) {
+		utils.Fatalf("block %d is not before the Hermes fork block %v", block.NumberU64(), config.HermesBlock)
+	}
+	stateDB, err := state.New(block.Root(), state.NewDatabase(chainDb))
+	if err != nil {
+		utils.Fatalf("could not create new stateDB: %v", err)
+	}
+	assets, err := stateDB.LegacyAssets()
+	if err != nil {
+		utils.Fatalf("could not list the assets: %v", err)
+	}
+	out, _ := json.MarshalIndent(assets, "", "  ")
+	fmt.Printf("%s\n", out)
+
+	if len(config.HermesAssets) > 0 {
+		if len(config.HermesAssets) != len(assets) {
+			utils.Fatalf("chain config lists %d assets, the state has %d", len(config.HermesAssets), len(assets))
+		}
+		for i, asset := range assets {
+			if config.HermesAssets[i] != asset {
+				utils.Fatalf("chain config lists asset %s at %d, the state has %s", config.HermesAssets[i].Hex(), i, asset.Hex())
+			}
+		}
+	}
+	return nil
+}
+
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
 	return err != nil
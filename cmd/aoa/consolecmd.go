@@ -59,7 +59,7 @@ JavaScript API. See https://github.com/Aurorachain/go-Aurorachain/wiki/Javascipt
 func localConsole(ctx *cli.Context) error {
 
 	node := makeFullNode(ctx)
-	startNode(ctx, node)
+	defer startNode(ctx, node)()
 	defer node.Stop()
 
 	client, err := node.Attach()
@@ -148,7 +148,7 @@ func dialRPC(endpoint string) (*rpc.Client, error) {
 func ephemeralConsole(ctx *cli.Context) error {
 
 	node := makeFullNode(ctx)
-	startNode(ctx, node)
+	defer startNode(ctx, node)()
 	defer node.Stop()
 
 	client, err := node.Attach()
@@ -10,14 +10,15 @@ import (
 
 	"github.com/Aurorachain/go-Aurora/accounts"
 	"github.com/Aurorachain/go-Aurora/accounts/keystore"
-	"github.com/Aurorachain/go-Aurora/cmd/utils"
-	"github.com/Aurorachain/go-Aurora/console"
 	"github.com/Aurorachain/go-Aurora/aoa"
 	"github.com/Aurorachain/go-Aurora/aoaclient"
+	"github.com/Aurorachain/go-Aurora/cmd/utils"
+	"github.com/Aurorachain/go-Aurora/console"
 	"github.com/Aurorachain/go-Aurora/internal/debug"
 	"github.com/Aurorachain/go-Aurora/log"
 	"github.com/Aurorachain/go-Aurora/metrics"
 	"github.com/Aurorachain/go-Aurora/node"
+	"github.com/Aurorachain/go-Aurora/signer"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -52,6 +53,8 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolScheduleFlag,
+		utils.TxPoolTypeCapFlag,
 		utils.FastSyncFlag,
 		utils.SyncModeFlag,
 		utils.LightServFlag,
@@ -87,6 +90,7 @@ var (
 		utils.GpoBlocksFlag,
 		utils.GpoPercentileFlag,
 		utils.ExtraDataFlag,
+		utils.SignerFlag,
 		configFileFlag,
 		utils.WatchInnerTxFlag,
 	}
@@ -123,11 +127,14 @@ func init() {
 		copydbCommand,
 		removedbCommand,
 		dumpCommand,
+		hermesAssetsCommand,
 
 		monitorCommand,
 
 		accountCommand,
 		walletCommand,
+		protectionCommand,
+		scheduleCommand,
 
 		consoleCommand,
 		attachCommand,
@@ -175,12 +182,16 @@ func main() {
 
 func gAoa(ctx *cli.Context) error {
 	fullNode := makeFullNode(ctx)
-	startNode(ctx, fullNode)
+	release := startNode(ctx, fullNode)
 	fullNode.Wait()
+	release()
 	return nil
 }
 
-func startNode(ctx *cli.Context, stack *node.Node) {
+// startNode starts the node, unlocks the requested accounts and sets up the
+// block producer. The returned function releases what it opened besides the
+// node, it has to be called once the node stopped.
+func startNode(ctx *cli.Context, stack *node.Node) func() {
 	numCPU := runtime.NumCPU()
 	runtime.GOMAXPROCS(numCPU)
 	log.Info("start with multiple CPU", "cpu number", numCPU)
@@ -237,7 +248,49 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		}
 	}()
 
-	if ctx.GlobalBool(utils.MiningEnabledFlag.Name) || ctx.GlobalBool(utils.DeveloperFlag.Name) {
+	release := func() {}
+	mining := ctx.GlobalBool(utils.MiningEnabledFlag.Name) || ctx.GlobalBool(utils.DeveloperFlag.Name)
+	endpoint := ctx.GlobalString(utils.SignerFlag.Name)
+	if mining || endpoint != "" {
+		// a producing node signs blocks only behind the slashing protection
+		var aurora *aoa.Aurora
+		if err := stack.Service(&aurora); err != nil {
+			utils.Fatalf("Aurora service not running: %v", err)
+		}
+		miner := aurora.DposMiner()
+		if miner == nil {
+			utils.Fatalf("Aurora service has no block producer")
+		}
+		path := stack.ResolvePath(protectionFile)
+		if path == "" {
+			log.Warn("Slashing protection database kept in memory, no data directory")
+		}
+		protection, err := signer.OpenProtectionDB(path)
+		if err != nil {
+			utils.Fatalf("Failed to open slashing protection database: %v", err)
+		}
+		miner.SetProtectionDB(protection)
+		release = func() {
+			if err := protection.Close(); err != nil {
+				log.Error("Failed to close slashing protection database", "err", err)
+			}
+		}
+
+		if endpoint != "" {
+			remote, err := signer.DialRemoteSigner(endpoint)
+			if err != nil {
+				utils.Fatalf("Failed to dial signer %s: %v", endpoint, err)
+			}
+			keys, err := remote.Accounts()
+			if err != nil {
+				utils.Fatalf("Failed to list signer accounts: %v", err)
+			}
+			miner.SetBlockSigner(remote, keys)
+			log.Info("Signing blocks with external signer", "endpoint", endpoint, "keys", len(keys))
+		}
+	}
+
+	if mining {
 
 		var aurora *aoa.Aurora
 		if err := stack.Service(&aurora); err != nil {
@@ -255,4 +308,5 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 
 		aurora.TxPool().SetGasPrice(utils.GlobalBig(ctx, utils.GasPriceFlag.Name))
 	}
+	return release
 }
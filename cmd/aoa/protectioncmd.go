@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Aurorachain/go-Aurora/cmd/utils"
+	"github.com/Aurorachain/go-Aurora/signer"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// protectionFile is the slashing protection database of the block producer,
+// kept in the instance directory.
+const protectionFile = "slashprotection.jsonl"
+
+var (
+	protectionCommand = cli.Command{
+		Name:     "protection",
+		Usage:    "Manage the slashing protection database",
+		Category: "ACCOUNT COMMANDS",
+		Description: `
+
+The slashing protection database records every block signed by the delegates of
+this node. A block conflicting with a recorded one, at the same height or slot,
+is never signed.
+
+When moving a delegate key to another machine, export the database together with
+the key and import it on the new machine before producing blocks there. The
+database is locked while the node runs, stop the node before importing.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the signed blocks as interchange JSON",
+				ArgsUsage: "[<file>]",
+				Action:    utils.MigrateFlags(protectionExport),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    aoa protection export protection.json
+
+Writes the signed blocks to the file, or to standard output if none is given.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import signed blocks from interchange JSON",
+				ArgsUsage: "<file>",
+				Action:    utils.MigrateFlags(protectionImport),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+    aoa protection import protection.json
+
+Adds the signed blocks in the file to the database.`,
+			},
+		},
+	}
+)
+
+func openProtectionDB(ctx *cli.Context) *signer.ProtectionDB {
+	stack, _ := makeConfigNode(ctx)
+	path := stack.ResolvePath(protectionFile)
+	if path == "" {
+		utils.Fatalf("Slashing protection needs a data directory (--%s)", utils.DataDirFlag.Name)
+	}
+	db, err := signer.OpenProtectionDB(path)
+	if err != nil {
+		utils.Fatalf("Failed to open slashing protection database: %v", err)
+	}
+	return db
+}
+
+func protectionExport(ctx *cli.Context) error {
+	db := openProtectionDB(ctx)
+	defer db.Close()
+
+	var out io.Writer = os.Stdout
+	if file := ctx.Args().First(); file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			utils.Fatalf("Failed to create %s: %v", file, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := db.Export(out); err != nil {
+		utils.Fatalf("Export error: %v", err)
+	}
+	return nil
+}
+
+func protectionImport(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	db := openProtectionDB(ctx)
+	defer db.Close()
+
+	file := ctx.Args().First()
+	f, err := os.Open(file)
+	if err != nil {
+		utils.Fatalf("Failed to open %s: %v", file, err)
+	}
+	defer f.Close()
+	added, err := db.Import(f)
+	if err != nil {
+		utils.Fatalf("Import error: %v", err)
+	}
+	fmt.Printf("Imported %d signed blocks\n", added)
+	return nil
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aurorachain/go-Aurora/cmd/utils"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/util"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	scheduleAttachFlag = cli.StringFlag{
+		Name:  "attach",
+		Usage: "API endpoint of the node to read the delegates from",
+	}
+	scheduleCandidatesFlag = cli.StringFlag{
+		Name:  "candidates",
+		Usage: "JSON file with the candidate list to plan with",
+	}
+	scheduleRoundTimeFlag = cli.Int64Flag{
+		Name:  "roundtime",
+		Usage: "Begin time of any round as unix time (default: genesis time of the chain)",
+	}
+	scheduleIntervalFlag = cli.Int64Flag{
+		Name:  "interval",
+		Value: params.MainnetChainConfig.BlockInterval.Int64(),
+		Usage: "Block interval in seconds, read from the chain config of a data directory",
+	}
+	scheduleMaxElectFlag = cli.IntFlag{
+		Name:  "maxelect",
+		Value: int(params.MainnetChainConfig.MaxElectDelegate.Int64()),
+		Usage: "Delegates elected per round, read from the chain config of a data directory",
+	}
+	scheduleRoundsFlag = cli.IntFlag{
+		Name:  "rounds",
+		Value: 1,
+		Usage: "Number of rounds to plan",
+	}
+	scheduleFromFlag = cli.Int64Flag{
+		Name:  "from",
+		Usage: "Plan from this unix time instead of now",
+	}
+	scheduleDelegateFlag = cli.StringFlag{
+		Name:  "delegate",
+		Usage: "Only show the slots of this delegate",
+	}
+	scheduleVoteFlag = cli.StringSliceFlag{
+		Name:  "vote",
+		Usage: "What-if change of the votes of a candidate as <address>=<+/-AOA>, may be repeated",
+	}
+	scheduleSeededFlag = cli.BoolFlag{
+		Name:  "seeded",
+		Usage: "Rounds are ordered by the revealed seed (past the Poseidon fork)",
+	}
+	scheduleCommand = cli.Command{
+		Action:    utils.MigrateFlags(schedule),
+		Name:      "schedule",
+		Usage:     "Plan the upcoming block production slots of the delegates",
+		ArgsUsage: " ",
+		Category:  "MISCELLANEOUS COMMANDS",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			scheduleAttachFlag,
+			scheduleCandidatesFlag,
+			scheduleRoundTimeFlag,
+			scheduleIntervalFlag,
+			scheduleMaxElectFlag,
+			scheduleRoundsFlag,
+			scheduleFromFlag,
+			scheduleDelegateFlag,
+			scheduleVoteFlag,
+			scheduleSeededFlag,
+		},
+		Description: `
+    aoa schedule [--attach <endpoint> | --candidates <file>] [--delegate <address>]
+
+Prints the production slots of the next rounds with their wall-clock times. The
+delegates are read from the node at --attach, from the JSON candidate list in
+--candidates or else from the chain in the data directory.
+
+A candidate list is an array of {"address", "vote", "nickname", "registerTime"}
+objects, votes in whole AOA. It needs --roundtime.
+
+What-if vote changes are given with --vote, e.g.
+    --vote AOA1234...=+5000 --vote AOAabcd...=-300
+
+Past the Poseidon fork the order of a round is drawn from the seeds the blocks
+before it reveal, so only the producers of a round are known in advance.`,
+	}
+)
+
+// isScheduleAddress reports whether s is an address in hex or AOA form.
+func isScheduleAddress(s string) bool {
+	return common.IsHexAddress(s) || common.IsAOAAddress(s)
+}
+
+// voteChange is a what-if change of the votes of a candidate in whole AOA.
+type voteChange struct {
+	address common.Address
+	delta   int64
+}
+
+func parseVoteChange(s string) (voteChange, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || !isScheduleAddress(parts[0]) {
+		return voteChange{}, fmt.Errorf("invalid vote change %q, want <address>=<+/-AOA>", s)
+	}
+	delta, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return voteChange{}, fmt.Errorf("invalid vote change %q: %v", s, err)
+	}
+	return voteChange{address: common.HexToAddress(parts[0]), delta: delta}, nil
+}
+
+// applyVoteChanges returns the candidates with changes applied, ranked as the
+// delegate state ranks them.
+func applyVoteChanges(candidates []types.Candidate, changes []voteChange) ([]types.Candidate, error) {
+	changed := make([]types.Candidate, len(candidates))
+	copy(changed, candidates)
+	for _, change := range changes {
+		i := 0
+		for ; i < len(changed); i++ {
+			if common.HexToAddress(changed[i].Address) == change.address {
+				break
+			}
+		}
+		if i == len(changed) {
+			return nil, fmt.Errorf("%s is not a candidate", change.address.Hex())
+		}
+		vote := int64(changed[i].Vote) + change.delta
+		if vote < 0 {
+			return nil, fmt.Errorf("votes of %s would drop below zero", change.address.Hex())
+		}
+		changed[i].Vote = uint64(vote)
+	}
+	sort.Sort(types.CandidateSlice(changed))
+	return changed, nil
+}
+
+// plannedRound is a round of production slots. The slots of a seeded round are
+// not known in advance, only its producers.
+type plannedRound struct {
+	begin     int64
+	end       int64
+	producers []types.Candidate
+	slots     []types.ShuffleDel
+}
+
+// planRounds lays out rounds rounds from the one time from falls in. The rounds
+// are aligned to roundTime, the begin time of any round, and shuffled as the
+// delegates elected at block number are.
+func planRounds(config *params.ChainConfig, number *big.Int, candidates []types.Candidate, roundTime, from int64, rounds int) ([]plannedRound, error) {
+	var (
+		maxElect = int(config.MaxElectDelegate.Int64())
+		interval = config.BlockInterval.Int64()
+		seeded   = config.IsPoseidon(number)
+	)
+	top := candidates
+	if len(top) > maxElect {
+		top = top[:maxElect]
+	}
+	if len(top) == 0 {
+		return nil, errors.New("no delegates to plan with")
+	}
+	length := int64(len(top)) * interval
+	// CalShuffleTimeByHeaderTime gives up past the current time, step forward
+	// from there to a later from
+	anchor := from
+	if now := time.Now().Unix(); anchor > now {
+		anchor = now
+	}
+	begin := util.CalShuffleTimeByHeaderTime(roundTime, anchor, interval, int64(len(top)))
+	if begin == 0 {
+		return nil, fmt.Errorf("time %d is not in a round aligned to %d", from, roundTime)
+	}
+	for begin+length <= from {
+		begin += length
+	}
+	planned := make([]plannedRound, 0, rounds)
+	for i := 0; i < rounds; i++ {
+		round := plannedRound{begin: begin, end: begin + length, producers: top}
+		// the seed of a seeded round is mixed by the blocks before it
+		if !seeded {
+			round.slots = core.ShuffleRound(config, number, common.Hash{}, begin, top)
+		}
+		planned = append(planned, round)
+		begin += length
+	}
+	return planned, nil
+}
+
+// scheduleSource is what the rounds are planned from. The rounds of a data
+// directory are shuffled by its chain config, the others by one with the block
+// interval, the elected delegates and the Poseidon fork of the flags.
+type scheduleSource struct {
+	candidates []types.Candidate
+	roundTime  int64
+	config     *params.ChainConfig
+	number     *big.Int
+}
+
+func loadScheduleSource(ctx *cli.Context) (*scheduleSource, error) {
+	src := &scheduleSource{
+		config: &params.ChainConfig{
+			BlockInterval:    big.NewInt(ctx.Int64(scheduleIntervalFlag.Name)),
+			MaxElectDelegate: big.NewInt(int64(ctx.Int(scheduleMaxElectFlag.Name))),
+		},
+		number: new(big.Int),
+	}
+	if ctx.Bool(scheduleSeededFlag.Name) {
+		src.config.PoseidonBlock = new(big.Int)
+	}
+	switch {
+	case ctx.IsSet(scheduleCandidatesFlag.Name):
+		data, err := ioutil.ReadFile(ctx.String(scheduleCandidatesFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &src.candidates); err != nil {
+			return nil, fmt.Errorf("invalid candidate list: %v", err)
+		}
+		if !ctx.IsSet(scheduleRoundTimeFlag.Name) {
+			return nil, fmt.Errorf("a candidate list needs --%s", scheduleRoundTimeFlag.Name)
+		}
+
+	case ctx.IsSet(scheduleAttachFlag.Name):
+		client, err := dialRPC(ctx.String(scheduleAttachFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+
+		var delegates []struct {
+			types.Candidate
+			JailedUntil uint64 `json:"jailedUntil"`
+		}
+		if err := client.Call(&delegates, "aoa_getDelegateList", "latest"); err != nil {
+			return nil, err
+		}
+		// jailed delegates are not elected unless every delegate is jailed
+		var all []types.Candidate
+		for _, d := range delegates {
+			all = append(all, d.Candidate)
+			if d.JailedUntil == 0 {
+				src.candidates = append(src.candidates, d.Candidate)
+			}
+		}
+		if len(src.candidates) == 0 {
+			src.candidates = all
+		}
+		var genesis struct {
+			Timestamp *hexutil.Big `json:"timestamp"`
+		}
+		if err := client.Call(&genesis, "aoa_getBlockByNumber", "0x0", false); err != nil {
+			return nil, err
+		}
+		if genesis.Timestamp == nil {
+			return nil, errors.New("genesis block not found")
+		}
+		src.roundTime = genesis.Timestamp.ToInt().Int64()
+
+	default:
+		stack := makeFullNode(ctx)
+		chain, chainDb := utils.MakeChain(ctx, stack)
+		defer chainDb.Close()
+
+		delegates, err := chain.GetDelegatePoll()
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range *delegates {
+			src.candidates = append(src.candidates, d)
+		}
+		sort.Sort(types.CandidateSlice(src.candidates))
+		delegatedb, err := chain.DelegateState()
+		if err != nil {
+			return nil, err
+		}
+		config, head := chain.Config(), chain.CurrentBlock().Number()
+		src.candidates = core.ElectDelegates(config, delegatedb, head, src.candidates)
+		src.roundTime = chain.Genesis().Time().Int64()
+		src.config, src.number = config, head
+	}
+	if ctx.IsSet(scheduleRoundTimeFlag.Name) {
+		src.roundTime = ctx.Int64(scheduleRoundTimeFlag.Name)
+	}
+	if src.config.BlockInterval.Sign() <= 0 || src.config.MaxElectDelegate.Sign() <= 0 {
+		return nil, errors.New("block interval and elected delegates must be positive")
+	}
+	return src, nil
+}
+
+func schedule(ctx *cli.Context) error {
+	var changes []voteChange
+	for _, s := range ctx.StringSlice(scheduleVoteFlag.Name) {
+		change, err := parseVoteChange(s)
+		if err != nil {
+			utils.Fatalf("%v", err)
+		}
+		changes = append(changes, change)
+	}
+	var delegate common.Address
+	if s := ctx.String(scheduleDelegateFlag.Name); s != "" {
+		if !isScheduleAddress(s) {
+			utils.Fatalf("Invalid delegate address %q", s)
+		}
+		delegate = common.HexToAddress(s)
+	}
+	src, err := loadScheduleSource(ctx)
+	if err != nil {
+		utils.Fatalf("Failed to load the delegates: %v", err)
+	}
+	candidates, err := applyVoteChanges(src.candidates, changes)
+	if err != nil {
+		utils.Fatalf("Invalid vote change: %v", err)
+	}
+	from := time.Now().Unix()
+	if ctx.IsSet(scheduleFromFlag.Name) {
+		from = ctx.Int64(scheduleFromFlag.Name)
+	}
+	rounds, err := planRounds(src.config, src.number, candidates, src.roundTime, from, ctx.Int(scheduleRoundsFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to plan: %v", err)
+	}
+	if delegate != (common.Address{}) {
+		printDelegateSchedule(rounds, delegate, from)
+	} else {
+		printSchedule(rounds, from)
+	}
+	return nil
+}
+
+func slotTime(t int64) string {
+	return time.Unix(t, 0).Format("2006-01-02 15:04:05 MST")
+}
+
+func printSchedule(rounds []plannedRound, from int64) {
+	for i, round := range rounds {
+		fmt.Printf("Round %d: %s - %s\n", i+1, slotTime(round.begin), slotTime(round.end))
+		if round.slots == nil {
+			fmt.Println("  order drawn from the seeds revealed before the round, producers:")
+			for _, c := range round.producers {
+				fmt.Printf("  %s %-20s %d\n", common.HexToAddress(c.Address).Hex(), c.Nickname, c.Vote)
+			}
+			continue
+		}
+		for _, slot := range round.slots {
+			if int64(slot.WorkTime) < from {
+				continue
+			}
+			fmt.Printf("  %s %s %-20s %d\n", slotTime(int64(slot.WorkTime)), common.HexToAddress(slot.Address).Hex(), slot.Nickname, slot.Vote)
+		}
+	}
+}
+
+func printDelegateSchedule(rounds []plannedRound, delegate common.Address, from int64) {
+	for i, round := range rounds {
+		elected := false
+		for _, c := range round.producers {
+			if common.HexToAddress(c.Address) == delegate {
+				elected = true
+			}
+		}
+		switch {
+		case !elected:
+			fmt.Printf("Round %d: %s is not elected\n", i+1, delegate.Hex())
+		case round.slots == nil:
+			fmt.Printf("Round %d: one slot between %s and %s\n", i+1, slotTime(round.begin), slotTime(round.end))
+		default:
+			for _, slot := range round.slots {
+				if common.HexToAddress(slot.Address) != delegate {
+					continue
+				}
+				if int64(slot.WorkTime) < from {
+					fmt.Printf("Round %d: slot at %s has passed\n", i+1, slotTime(int64(slot.WorkTime)))
+				} else {
+					fmt.Printf("Round %d: slot at %s, in %v\n", i+1, slotTime(int64(slot.WorkTime)), time.Duration(int64(slot.WorkTime)-from)*time.Second)
+				}
+			}
+		}
+	}
+}
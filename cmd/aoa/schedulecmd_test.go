@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+func scheduleCandidates() []types.Candidate {
+	return []types.Candidate{
+		{Address: common.BytesToAddress([]byte{1}).Hex(), Vote: 300, Nickname: "a"},
+		{Address: common.BytesToAddress([]byte{2}).Hex(), Vote: 200, Nickname: "b"},
+		{Address: common.BytesToAddress([]byte{3}).Hex(), Vote: 100, Nickname: "c"},
+	}
+}
+
+func TestScheduleVoteChanges(t *testing.T) {
+	change, err := parseVoteChange(common.BytesToAddress([]byte{3}).Hex() + "=+250")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := applyVoteChanges(scheduleCandidates(), []voteChange{change})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed[0].Nickname != "c" || changed[0].Vote != 350 {
+		t.Errorf("top candidate = %s with %d votes, want c with 350", changed[0].Nickname, changed[0].Vote)
+	}
+	if _, err := applyVoteChanges(scheduleCandidates(), []voteChange{{address: common.BytesToAddress([]byte{2}), delta: -201}}); err == nil {
+		t.Error("votes dropped below zero")
+	}
+	if _, err := applyVoteChanges(scheduleCandidates(), []voteChange{{address: common.BytesToAddress([]byte{4}), delta: 1}}); err == nil {
+		t.Error("changed votes of an unknown candidate")
+	}
+	for _, s := range []string{"0x01", common.BytesToAddress([]byte{1}).Hex(), common.BytesToAddress([]byte{1}).Hex() + "=x"} {
+		if _, err := parseVoteChange(s); err == nil {
+			t.Errorf("parsed invalid vote change %q", s)
+		}
+	}
+}
+
+func TestScheduleRounds(t *testing.T) {
+	config := &params.ChainConfig{BlockInterval: big.NewInt(10), MaxElectDelegate: big.NewInt(2), PoseidonBlock: big.NewInt(5)}
+	rounds, err := planRounds(config, big.NewInt(4), scheduleCandidates(), 1000, 1035, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rounds) != 2 {
+		t.Fatalf("planned %d rounds, want 2", len(rounds))
+	}
+	for i, round := range rounds {
+		if want := int64(1020 + 20*i); round.begin != want || round.end != want+20 {
+			t.Errorf("round %d = %d - %d, want %d - %d", i, round.begin, round.end, want, want+20)
+		}
+		if len(round.slots) != 2 {
+			t.Fatalf("round %d has %d slots, want 2", i, len(round.slots))
+		}
+		for j, slot := range round.slots {
+			if slot.WorkTime != uint64(round.begin)+uint64(10*j) {
+				t.Errorf("round %d slot %d at %d", i, j, slot.WorkTime)
+			}
+			if slot.Nickname == "c" {
+				t.Errorf("round %d has a slot of the unelected candidate", i)
+			}
+		}
+	}
+	seeded, err := planRounds(config, big.NewInt(5), scheduleCandidates(), 1000, 1035, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seeded[0].slots != nil || len(seeded[0].producers) != 2 {
+		t.Error("seeded round has a known order")
+	}
+	if _, err := planRounds(config, big.NewInt(4), nil, 1000, 1035, 1); err == nil {
+		t.Error("planned without delegates")
+	}
+}
@@ -81,6 +81,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolScheduleFlag,
+			utils.TxPoolTypeCapFlag,
 		},
 	},
 	{
@@ -143,6 +145,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TargetGasLimitFlag,
 			utils.GasPriceFlag,
 			utils.ExtraDataFlag,
+			utils.SignerFlag,
 		},
 	},
 	{
@@ -0,0 +1,147 @@
+// aoasigner holds delegate keys outside of the block producing node and signs
+// blocks for it over IPC, see package signer. The signer API is not
+// authenticated, so it is only served on the IPC endpoint, which the file
+// permissions of its path restrict to the local user.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/Aurorachain/go-Aurora/accounts"
+	"github.com/Aurorachain/go-Aurora/accounts/keystore"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/internal/debug"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/rpc"
+	"github.com/Aurorachain/go-Aurora/signer"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "Directory of the delegate key files",
+	}
+	unlockFlag = cli.StringFlag{
+		Name:  "unlock",
+		Usage: "Comma separated list of delegate accounts to sign for",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "Password file, one line per unlocked account",
+	}
+	ipcPathFlag = cli.StringFlag{
+		Name:  "ipcpath",
+		Usage: "Path of the IPC endpoint to serve on",
+		Value: "aoasigner.ipc",
+	}
+	watermarkFlag = cli.StringFlag{
+		Name:  "watermarks",
+		Usage: "File keeping the highest block signed per delegate",
+		Value: "watermarks.json",
+	}
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "aoasigner"
+	app.Usage = "external block signer for aoa delegates"
+	app.Flags = []cli.Flag{keystoreFlag, unlockFlag, passwordFlag, ipcPathFlag, watermarkFlag}
+	app.Flags = append(app.Flags, debug.Flags...)
+	app.Before = debug.Setup
+	app.Action = run
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	keys, err := loadKeys(ctx)
+	if err != nil {
+		return err
+	}
+	policy, err := signer.NewPolicy(ctx.String(watermarkFlag.Name))
+	if err != nil {
+		return err
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName(signer.Namespace, signer.NewService(keys, policy)); err != nil {
+		return err
+	}
+
+	endpoint := ctx.String(ipcPathFlag.Name)
+	if endpoint == "" {
+		return fmt.Errorf("--%s is required", ipcPathFlag.Name)
+	}
+	listener, err := rpc.CreateIPCListener(endpoint)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	go server.ServeListener(listener)
+	log.Info("IPC endpoint opened", "url", endpoint)
+	log.Info("Signer started", "delegates", len(keys.Accounts()))
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	<-sigc
+	log.Info("Signer stopped")
+	return nil
+}
+
+// loadKeys decrypts the key files of the unlocked accounts.
+func loadKeys(ctx *cli.Context) (*signer.KeySigner, error) {
+	dir := ctx.String(keystoreFlag.Name)
+	if dir == "" {
+		return nil, fmt.Errorf("--%s is required", keystoreFlag.Name)
+	}
+	var passwords []string
+	if path := ctx.String(passwordFlag.Name); path != "" {
+		text, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read password file: %v", err)
+		}
+		passwords = strings.Split(strings.Replace(string(text), "\r", "", -1), "\n")
+	}
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	keys := signer.NewKeySigner()
+	for i, address := range strings.Split(ctx.String(unlockFlag.Name), ",") {
+		if address = strings.TrimSpace(address); address == "" {
+			continue
+		}
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid account %s", address)
+		}
+		account, err := ks.Find(accounts.Account{Address: common.HexToAddress(address)})
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %v", address, err)
+		}
+		keyjson, err := ioutil.ReadFile(account.URL.Path)
+		if err != nil {
+			return nil, err
+		}
+		password := ""
+		if len(passwords) > 0 {
+			password = passwords[len(passwords)-1]
+			if i < len(passwords) {
+				password = passwords[i]
+			}
+		}
+		key, err := keystore.DecryptKey(keyjson, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock %s: %v", address, err)
+		}
+		keys.Add(key.PrivateKey)
+		log.Info("Unlocked delegate", "address", key.Address)
+	}
+	if len(keys.Accounts()) == 0 {
+		return nil, fmt.Errorf("no accounts to sign for, see --%s", unlockFlag.Name)
+	}
+	return keys, nil
+}
@@ -202,6 +202,16 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: aoa.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolScheduleFlag = cli.StringFlag{
+		Name:  "txpool.schedule",
+		Usage: "Policy splitting block space between transaction types (proportional, price, fair)",
+		Value: core.DefaultTxPoolConfig.Schedule,
+	}
+	TxPoolTypeCapFlag = cli.Uint64Flag{
+		Name:  "txpool.typecap",
+		Usage: "Maximum number of transactions of a single asset or contract offered per block (0 = no cap)",
+		Value: core.DefaultTxPoolConfig.TypeCap,
+	}
 
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -242,6 +252,11 @@ var (
 		Name:  "extradata",
 		Usage: "Block extra data set by the miner (default = client version)",
 	}
+	SignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "IPC path of the external signer for delegate blocks, replaces unlocked delegate keys",
+		Value: "",
+	}
 
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -734,6 +749,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolScheduleFlag.Name) {
+		cfg.Schedule = ctx.GlobalString(TxPoolScheduleFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolTypeCapFlag.Name) {
+		cfg.TypeCap = ctx.GlobalUint64(TxPoolTypeCapFlag.Name)
+	}
 }
 
 func checkExclusive(ctx *cli.Context, args ...interface{}) {
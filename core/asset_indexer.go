@@ -0,0 +1,342 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/rlp"
+	"github.com/Aurorachain/go-Aurora/trie"
+)
+
+var (
+	ErrAssetHolderIndexEmpty    = errors.New("asset holder index has no processed block")
+	ErrAssetHolderIndexDisabled = errors.New("asset holder index is not available")
+	ErrAssetHolderIndexPartial  = errors.New("asset holder index does not cover the holdings before its first block")
+
+	assetHolderRootPrefix    = []byte("assetHolderRoot-")
+	assetHolderStartKey      = []byte("assetHolderStart")
+	assetChangesPrefix       = []byte("assetChanges-") // assetChangesPrefix + num (uint64 big endian) + hash -> asset changes of the block
+	AssetHolderTablePrefix   = "ah-"
+	assetHolderBalancePrefix = []byte("h")
+	assetHolderCountPrefix   = []byte("c")
+)
+
+type AssetHolder struct {
+	Address common.Address `json:"address"`
+	Balance *big.Int       `json:"balance"`
+}
+
+func GetAssetHolderRoot(db aoadb.Database, number uint64, hash common.Hash) common.Hash {
+	var encNumber [8]byte
+	binary.BigEndian.PutUint64(encNumber[:], number)
+	data, _ := db.Get(append(append(assetHolderRootPrefix, encNumber[:]...), hash.Bytes()...))
+	return common.BytesToHash(data)
+}
+
+func StoreAssetHolderRoot(db aoadb.Database, number uint64, hash, root common.Hash) {
+	var encNumber [8]byte
+	binary.BigEndian.PutUint64(encNumber[:], number)
+	db.Put(append(append(assetHolderRootPrefix, encNumber[:]...), hash.Bytes()...), root.Bytes())
+}
+
+// GetAssetHolderStart returns the first block the asset holder index applied
+// recorded asset changes to, and whether the holdings before it were backfilled.
+// ok is false if no block with recorded asset changes was indexed yet.
+func GetAssetHolderStart(db DatabaseReader) (number uint64, complete bool, ok bool) {
+	data, _ := db.Get(assetHolderStartKey)
+	if len(data) != 9 {
+		return 0, false, false
+	}
+	return binary.BigEndian.Uint64(data), data[8] == 1, true
+}
+
+func storeAssetHolderStart(db aoadb.Putter, number uint64, complete bool) error {
+	data := encodeBlockNumber(number)
+	if complete {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	return db.Put(assetHolderStartKey, data)
+}
+
+func assetChangesKey(hash common.Hash, number uint64) []byte {
+	return append(append(append([]byte{}, assetChangesPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// GetAssetChanges returns the asset balances the block changed, ok is false if
+// they were not recorded because the block was not executed by this node.
+func GetAssetChanges(db DatabaseReader, hash common.Hash, number uint64) (changes []state.AssetChange, ok bool) {
+	data, _ := db.Get(assetChangesKey(hash, number))
+	if len(data) == 0 {
+		return nil, false
+	}
+	if err := rlp.DecodeBytes(data, &changes); err != nil {
+		log.Error("Invalid asset changes RLP", "hash", hash, "err", err)
+		return nil, false
+	}
+	return changes, true
+}
+
+// WriteAssetChanges stores the asset balances changed by the block, it is
+// written with the state of every block that is executed.
+func WriteAssetChanges(db aoadb.Putter, hash common.Hash, number uint64, changes []state.AssetChange) error {
+	data, err := rlp.EncodeToBytes(changes)
+	if err != nil {
+		return err
+	}
+	return db.Put(assetChangesKey(hash, number), data)
+}
+
+func assetHolderBalanceKey(asset, holder common.Address) []byte {
+	return append(append(append([]byte{}, assetHolderBalancePrefix...), asset.Bytes()...), holder.Bytes()...)
+}
+
+func assetHolderCountKey(asset common.Address) []byte {
+	return append(append([]byte{}, assetHolderCountPrefix...), asset.Bytes()...)
+}
+
+// AssetHolderIndexerBackend keeps a trie of asset -> holder -> balance. It runs
+// with sections of one block: every block applies the asset changes recorded
+// when it was executed on top of the trie committed for its parent, so the
+// index follows the head and a reorg only rolls back to the common ancestor.
+//
+// Blocks imported before this node recorded asset changes, the blocks of a
+// fast sync or those of a node upgraded in place, are skipped: the index starts
+// at the first block with a record, backfilled with the holdings of its parent
+// state. A fast-synced node lacks the preimages to resolve the holders of that
+// state, its index is not backfilled and readers refuse to answer from it.
+type AssetHolderIndexerBackend struct {
+	db, cdb  aoadb.Database
+	number   uint64
+	header   *types.Header
+	trie     *trie.Trie
+	skip     bool // the block precedes the start of the index
+	start    bool // the block starts the index
+	complete bool // the index was backfilled with the holdings before the start
+	err      error
+}
+
+// NewAssetHolderIndexer creates the asset holder index of the chain in db, every
+// BlockChain runs one.
+func NewAssetHolderIndexer(db aoadb.Database) *ChainIndexer {
+	cdb := aoadb.NewTable(db, AssetHolderTablePrefix)
+	idb := aoadb.NewTable(db, "ahIndex-")
+	backend := &AssetHolderIndexerBackend{db: db, cdb: cdb}
+	return NewChainIndexer(db, idb, backend, 1, 0, 0, "assetholder")
+}
+
+func (b *AssetHolderIndexerBackend) Reset(number uint64, parent common.Hash) error {
+	var root common.Hash
+	if number > 0 {
+		root = GetAssetHolderRoot(b.db, number-1, parent)
+	}
+	var err error
+	b.trie, err = trie.New(root, b.cdb)
+	b.number = number
+	b.header = nil
+	b.skip, b.start, b.complete = false, false, false
+	b.err = nil
+	return err
+}
+
+func (b *AssetHolderIndexerBackend) Process(header *types.Header) {
+	if b.err != nil {
+		return
+	}
+	b.header = header
+	number := header.Number.Uint64()
+	start, _, started := GetAssetHolderStart(b.db)
+	changes, ok := GetAssetChanges(b.db, header.Hash(), number)
+	switch {
+	case !ok && (!started || number < start):
+		b.skip = true
+		return
+	case !ok:
+		b.err = fmt.Errorf("asset changes of block %d [%x…] not recorded", header.Number, header.Hash().Bytes()[:4])
+		return
+	case !started || number < start:
+		// a block below the start can only have a record if it was executed
+		// on a reorg, its parent is not indexed so the trie is empty
+		b.start = true
+		if b.complete, b.err = b.backfill(header); b.err != nil {
+			return
+		}
+	}
+	for _, c := range changes {
+		if b.err = b.updateHolder(c.Holder, c.Asset, c.Balance); b.err != nil {
+			return
+		}
+	}
+}
+
+func (b *AssetHolderIndexerBackend) Commit() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.header == nil {
+		return errors.New("no header processed")
+	}
+	if b.skip {
+		return nil
+	}
+	batch := b.cdb.NewBatch()
+	root, err := b.trie.CommitTo(batch)
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	StoreAssetHolderRoot(b.db, b.number, b.header.Hash(), root)
+	if b.start {
+		return storeAssetHolderStart(b.db, b.number, b.complete)
+	}
+	return nil
+}
+
+// backfill adds the holdings of the state the block was executed on to the
+// empty trie. It reports false if the state or its preimages are missing.
+func (b *AssetHolderIndexerBackend) backfill(header *types.Header) (bool, error) {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return true, nil
+	}
+	parent := GetHeader(b.db, header.ParentHash, number-1)
+	if parent == nil {
+		return false, nil
+	}
+	statedb, err := state.New(parent.Root, state.NewDatabase(b.db))
+	if err != nil {
+		log.Warn("Asset holder index not backfilled", "number", number-1, "err", err)
+		return false, nil
+	}
+	holdings, err := statedb.AssetHoldings()
+	if err != nil {
+		log.Warn("Asset holder index not backfilled", "number", number-1, "err", err)
+		return false, nil
+	}
+	for _, h := range holdings {
+		if err := b.updateHolder(h.Holder, h.Asset, h.Balance); err != nil {
+			return false, err
+		}
+	}
+	log.Info("Backfilled asset holder index", "number", number-1, "holdings", len(holdings))
+	return true, nil
+}
+
+func (b *AssetHolderIndexerBackend) updateHolder(holder, asset common.Address, balance *big.Int) error {
+	key := assetHolderBalanceKey(asset, holder)
+	prev, err := b.trie.TryGet(key)
+	if err != nil {
+		return err
+	}
+	held := len(prev) > 0
+	switch {
+	case balance.Sign() > 0:
+		enc, _ := rlp.EncodeToBytes(balance)
+		if err := b.trie.TryUpdate(key, enc); err != nil {
+			return err
+		}
+		if !held {
+			return b.addHolderCount(asset, 1)
+		}
+	case held:
+		if err := b.trie.TryDelete(key); err != nil {
+			return err
+		}
+		return b.addHolderCount(asset, -1)
+	}
+	return nil
+}
+
+func (b *AssetHolderIndexerBackend) addHolderCount(asset common.Address, delta int64) error {
+	key := assetHolderCountKey(asset)
+	enc, err := b.trie.TryGet(key)
+	if err != nil {
+		return err
+	}
+	var count uint64
+	if len(enc) > 0 {
+		if err := rlp.DecodeBytes(enc, &count); err != nil {
+			return err
+		}
+	}
+	count = uint64(int64(count) + delta)
+	if count == 0 {
+		return b.trie.TryDelete(key)
+	}
+	enc, _ = rlp.EncodeToBytes(count)
+	return b.trie.TryUpdate(key, enc)
+}
+
+// AssetHolderReader answers holder queries against the latest block processed by
+// the asset holder index.
+type AssetHolderReader struct {
+	trie   *trie.Trie
+	Number uint64
+}
+
+func NewAssetHolderReader(db aoadb.Database, indexer *ChainIndexer) (*AssetHolderReader, error) {
+	if indexer == nil {
+		return nil, ErrAssetHolderIndexDisabled
+	}
+	sections, _, head := indexer.Sections()
+	start, complete, ok := GetAssetHolderStart(db)
+	if sections == 0 || !ok || sections-1 < start {
+		return nil, ErrAssetHolderIndexEmpty
+	}
+	if !complete {
+		return nil, ErrAssetHolderIndexPartial
+	}
+	return newAssetHolderReader(db, GetAssetHolderRoot(db, sections-1, head), sections-1)
+}
+
+func newAssetHolderReader(db aoadb.Database, root common.Hash, number uint64) (*AssetHolderReader, error) {
+	t, err := trie.New(root, aoadb.NewTable(db, AssetHolderTablePrefix))
+	if err != nil {
+		return nil, err
+	}
+	return &AssetHolderReader{trie: t, Number: number}, nil
+}
+
+func (r *AssetHolderReader) HolderCount(asset common.Address) (uint64, error) {
+	enc, err := r.trie.TryGet(assetHolderCountKey(asset))
+	if err != nil || len(enc) == 0 {
+		return 0, err
+	}
+	var count uint64
+	err = rlp.DecodeBytes(enc, &count)
+	return count, err
+}
+
+// Holders returns at most limit holders of the asset ordered by address, skipping
+// the first offset ones.
+func (r *AssetHolderReader) Holders(asset common.Address, offset, limit uint64) ([]AssetHolder, error) {
+	prefix := append(append([]byte{}, assetHolderBalancePrefix...), asset.Bytes()...)
+	holders := make([]AssetHolder, 0)
+	it := trie.NewIterator(r.trie.NodeIterator(prefix))
+	for it.Next() && uint64(len(holders)) < limit {
+		if !bytes.HasPrefix(it.Key, prefix) {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		balance := new(big.Int)
+		if err := rlp.DecodeBytes(it.Value, balance); err != nil {
+			return nil, err
+		}
+		holders = append(holders, AssetHolder{Address: common.BytesToAddress(it.Key[len(prefix):]), Balance: balance})
+	}
+	return holders, it.Err
+}
@@ -0,0 +1,191 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+func TestAssetHolderIndexer(t *testing.T) {
+	db, _ := aoadb.NewMemDatabase()
+	defer db.Close()
+
+	var (
+		issuer = common.Address{1}
+		alice  = common.Address{2}
+		bob    = common.Address{3}
+		asset  = crypto.CreateAddress(issuer, 0)
+	)
+	commit := func(statedb *state.StateDB, number uint64, parent common.Hash) *types.Header {
+		root, err := statedb.CommitTo(db, true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), ParentHash: parent, Root: root}
+		WriteHeader(db, header)
+		if err := WriteAssetChanges(db, header.Hash(), number, statedb.AssetChanges()); err != nil {
+			t.Fatalf("failed to write asset changes: %v", err)
+		}
+		return header
+	}
+	index := func(backend *AssetHolderIndexerBackend, header *types.Header) *AssetHolderReader {
+		number := header.Number.Uint64()
+		if err := backend.Reset(number, header.ParentHash); err != nil {
+			t.Fatalf("failed to reset block %d: %v", number, err)
+		}
+		backend.Process(header)
+		if err := backend.Commit(); err != nil {
+			t.Fatalf("failed to commit block %d: %v", number, err)
+		}
+		reader, err := newAssetHolderReader(db, GetAssetHolderRoot(db, number, header.Hash()), number)
+		if err != nil {
+			t.Fatalf("failed to open reader: %v", err)
+		}
+		return reader
+	}
+	check := func(reader *AssetHolderReader, want map[common.Address]int64) {
+		count, err := reader.HolderCount(asset)
+		if err != nil || count != uint64(len(want)) {
+			t.Fatalf("holder count mismatch: have %d (%v), want %d", count, err, len(want))
+		}
+		holders, err := reader.Holders(asset, 0, 100)
+		if err != nil || len(holders) != len(want) {
+			t.Fatalf("holder list mismatch: have %v (%v), want %v", holders, err, want)
+		}
+		for _, h := range holders {
+			if b, ok := want[h.Address]; !ok || h.Balance.Cmp(big.NewInt(b)) != 0 {
+				t.Errorf("holder %x balance mismatch: have %v, want %d", h.Address, h.Balance, b)
+			}
+		}
+	}
+	backend := &AssetHolderIndexerBackend{db: db, cdb: aoadb.NewTable(db, AssetHolderTablePrefix)}
+
+	// Block 0: the issuer publishes the asset and pays alice.
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	statedb.PublishAsset(issuer, types.AssetInfo{Name: "Test", Symbol: "TST", Supply: big.NewInt(1000), Desc: "test"}, true)
+	statedb.SubAssetBalance(issuer, asset, big.NewInt(100))
+	statedb.AddAssetBalance(alice, asset, big.NewInt(100))
+	head0 := commit(statedb, 0, common.Hash{})
+	check(index(backend, head0), map[common.Address]int64{issuer: 900, alice: 100})
+
+	// Block 1: alice sends everything to bob.
+	statedb, _ = state.New(head0.Root, state.NewDatabase(db))
+	statedb.SubAssetBalance(alice, asset, big.NewInt(100))
+	statedb.AddAssetBalance(bob, asset, big.NewInt(100))
+	head1 := commit(statedb, 1, head0.Hash())
+	check(index(backend, head1), map[common.Address]int64{issuer: 900, bob: 100})
+
+	// Reorged block 1: the issuer pays bob instead, alice keeps her balance.
+	statedb, _ = state.New(head0.Root, state.NewDatabase(db))
+	statedb.SubAssetBalance(issuer, asset, big.NewInt(50))
+	statedb.AddAssetBalance(bob, asset, big.NewInt(50))
+	fork1 := commit(statedb, 1, head0.Hash())
+	reader := index(backend, fork1)
+	check(reader, map[common.Address]int64{issuer: 850, alice: 100, bob: 50})
+
+	if holders, _ := reader.Holders(asset, 1, 1); len(holders) != 1 || holders[0].Address != alice {
+		t.Errorf("paginated holders mismatch: have %v", holders)
+	}
+
+	// A block imported without being executed has no recorded changes.
+	unknown := &types.Header{Number: big.NewInt(2), ParentHash: fork1.Hash(), Root: fork1.Root}
+	WriteHeader(db, unknown)
+	backend.Reset(2, fork1.Hash())
+	backend.Process(unknown)
+	if err := backend.Commit(); err == nil {
+		t.Errorf("block without recorded asset changes indexed")
+	}
+}
+
+// Tests that the indexer skips the blocks a fast-synced or upgraded node never
+// recorded asset changes for, and backfills the holdings before the first block
+// that has them from its parent state.
+func TestAssetHolderIndexerStart(t *testing.T) {
+	testAssetHolderIndexerStart(t, true)
+	testAssetHolderIndexerStart(t, false)
+}
+
+func testAssetHolderIndexerStart(t *testing.T, preimages bool) {
+	db, _ := aoadb.NewMemDatabase()
+	defer db.Close()
+
+	var (
+		issuer = common.Address{1}
+		alice  = common.Address{2}
+		asset  = crypto.CreateAddress(issuer, 0)
+		parent common.Hash
+	)
+	// Blocks 0-2 were imported without asset change records, the issuer
+	// published the asset in one of them.
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
+	statedb.PublishAsset(issuer, types.AssetInfo{Name: "Test", Symbol: "TST", Supply: big.NewInt(1000), Desc: "test"}, true)
+	root, _ := statedb.CommitTo(db, true)
+	if !preimages {
+		for _, key := range db.Keys() {
+			if bytes.HasPrefix(key, []byte("secure-key-")) {
+				db.Delete(key)
+			}
+		}
+	}
+	for i := uint64(0); i < 3; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), ParentHash: parent, Root: root}
+		WriteHeader(db, header)
+		WriteCanonicalHash(db, header.Hash(), i)
+		parent = header.Hash()
+	}
+	// Blocks 3-4 were executed, the issuer pays alice in block 4.
+	for i := uint64(3); i < 5; i++ {
+		statedb, _ = state.New(root, state.NewDatabase(db))
+		if i == 4 {
+			statedb.SubAssetBalance(issuer, asset, big.NewInt(100))
+			statedb.AddAssetBalance(alice, asset, big.NewInt(100))
+		}
+		root, _ = statedb.CommitTo(db, true)
+		header := &types.Header{Number: new(big.Int).SetUint64(i), ParentHash: parent, Root: root}
+		WriteHeader(db, header)
+		WriteCanonicalHash(db, header.Hash(), i)
+		if err := WriteAssetChanges(db, header.Hash(), i, statedb.AssetChanges()); err != nil {
+			t.Fatalf("failed to write asset changes: %v", err)
+		}
+		parent = header.Hash()
+	}
+	indexer := NewAssetHolderIndexer(db)
+	defer indexer.Close()
+
+	indexer.newHead(4, false)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := indexer.Sections(); sections == 5 {
+			break
+		}
+		if time.Now().After(deadline) {
+			sections, _, _ := indexer.Sections()
+			t.Fatalf("indexer stalled: have %d sections, want 5", sections)
+		}
+	}
+	reader, err := NewAssetHolderReader(db, indexer)
+	if !preimages {
+		if err != ErrAssetHolderIndexPartial {
+			t.Errorf("error mismatch without preimages: have %v, want %v", err, ErrAssetHolderIndexPartial)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("failed to open reader: %v", err)
+	}
+	if reader.Number != 4 {
+		t.Errorf("indexed block mismatch: have %d, want 4", reader.Number)
+	}
+	if count, err := reader.HolderCount(asset); err != nil || count != 2 {
+		t.Errorf("holder count mismatch: have %d (%v), want 2", count, err)
+	}
+	if holders, err := reader.Holders(asset, 0, 10); err != nil || len(holders) != 2 {
+		t.Errorf("holder list mismatch: have %v (%v)", holders, err)
+	}
+}
@@ -75,6 +75,8 @@ type BlockChain struct {
 	futureBlocks  *lru.Cache
 	innerTxDb     watch.InnerTxDb
 
+	assetHolderIndexer *ChainIndexer
+
 	quit    chan struct{}
 	running int32
 
@@ -87,6 +89,7 @@ type BlockChain struct {
 	vmConfig  vm.Config
 
 	badBlocks            *lru.Cache
+	shuffleCache         *lru.Cache
 	candidateWrapperChan chan *types.CandidateWrapper
 	delegateList         *map[string]types.Candidate
 }
@@ -97,6 +100,7 @@ func NewBlockChain(chainDb aoadb.Database, config *params.ChainConfig, aoaEngine
 	blockCache, _ := lru.New(blockCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	shuffleCache, _ := lru.New(shuffleCacheLimit)
 
 	bc := &BlockChain{
 		config:               config,
@@ -109,6 +113,7 @@ func NewBlockChain(chainDb aoadb.Database, config *params.ChainConfig, aoaEngine
 		futureBlocks:         futureBlocks,
 		vmConfig:             vmConfig,
 		badBlocks:            badBlocks,
+		shuffleCache:         shuffleCache,
 		candidateWrapperChan: make(chan *types.CandidateWrapper),
 		delegateCache:        delegatestate.NewDatabase(chainDb),
 		aoaEngine:            aoaEngine,
@@ -143,6 +148,9 @@ func NewBlockChain(chainDb aoadb.Database, config *params.ChainConfig, aoaEngine
 		}
 	}
 
+	bc.assetHolderIndexer = NewAssetHolderIndexer(chainDb)
+	bc.assetHolderIndexer.Start(bc)
+
 	go bc.update()
 	return bc, nil
 }
@@ -551,6 +559,9 @@ func (bc *BlockChain) Stop() {
 	atomic.StoreInt32(&bc.procInterrupt, 1)
 
 	bc.wg.Wait()
+	if err := bc.assetHolderIndexer.Close(); err != nil {
+		log.Error("Failed to close asset holder indexer", "err", err)
+	}
 	log.Info("Blockchain manager stopped")
 }
 
@@ -743,6 +754,9 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 	if _, err := state.CommitTo(batch, false); err != nil {
 		return NonStatTy, err
 	}
+	if err := WriteAssetChanges(batch, block.Hash(), block.NumberU64(), state.AssetChanges()); err != nil {
+		return NonStatTy, err
+	}
 
 	if _, err := delegatedb.CommitTo(batch, false); err != nil {
 		return NonStatTy, err
@@ -773,6 +787,11 @@ func (bc *BlockChain) WriteBlockAndState(block *types.Block, receipts []*types.R
 		if err := WritePreimages(bc.chainDb, block.NumberU64(), state.Preimages()); err != nil {
 			return NonStatTy, err
 		}
+		// the statistics are only written with the block, a block is not
+		// rejected for them
+		if err := bc.recordDelegateStats(batch, block, state); err != nil {
+			log.Error("Failed to record delegate stats", "number", block.Number(), "hash", block.Hash(), "err", err)
+		}
 		status = CanonStatTy
 	} else {
 		status = SideStatTy
@@ -993,6 +1012,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks, syncCallback ...func()) (i
 			log.Debug("Blockchain stateDB", "err", err)
 			return i, events, coalescedLogs, err
 		}
+		// past the Hephaestus fork a delegate may sign with a bound producer key
+		if bc.config.IsHephaestus(block.Number()) {
+			if err := VerifyBlockSignature(stateDB, block.Header(), block.Signature); err != nil {
+				bc.reportBlock(block, nil, err)
+				return i, events, coalescedLogs, err
+			}
+		}
 		delegateDB, err := delegatestate.New(parent.DelegateRoot(), bc.delegateCache)
 		if err != nil {
 			return i, events, coalescedLogs, err
@@ -1396,3 +1422,9 @@ func (bc *BlockChain) GetStateDB() state.Database {
 func (bc *BlockChain) GetInnerTxDb() watch.InnerTxDb {
 	return bc.innerTxDb
 }
+
+// AssetHolderIndexer returns the asset holder index of the chain, the API backend
+// serves the holder queries from it.
+func (bc *BlockChain) AssetHolderIndexer() *ChainIndexer {
+	return bc.assetHolderIndexer
+}
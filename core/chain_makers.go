@@ -10,7 +10,9 @@ import (
 	"github.com/Aurorachain/go-Aurora/core/state"
 	"github.com/Aurorachain/go-Aurora/core/types"
 	"github.com/Aurorachain/go-Aurora/core/vm"
+	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/util"
 	"github.com/Aurorachain/go-Aurora/consensus/delegatestate"
 )
 
@@ -113,13 +115,30 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, aoaEngine co
 
 		b := &BlockGen{i: i, parent: parent, chain: blocks, chainReader: blockchain, statedb: statedb, config: config, engine: aoaEngine, delegatedb: delegatedb}
 		b.header = makeHeader(b.chainReader, parent, statedb, delegatedb)
+		if err := ApplyHermesFork(config, statedb, b.header); err != nil {
+			panic(fmt.Sprintf("hermes fork error: %v", err))
+		}
 
 		if gen != nil {
 			gen(i, b)
 		}
 
 		if b.engine != nil {
+			ReleaseUnbonding(config, statedb, b.header)
+			ReleaseDepartedVotes(config, statedb, b.header)
+			if err := UpdateJail(config, generatedChain{blockchain, blocks}, statedb, delegatedb, b.header); err != nil {
+				panic(fmt.Sprintf("jail update error: %v", err))
+			}
+			if config.IsPoseidon(b.header.Number) {
+				key := statedb.ProducerAt(b.header.Coinbase, b.header.Number.Uint64())
+				epoch, index := NextSeedReveal(statedb, b.header.Coinbase, key)
+				b.header.Extra = util.SeedReveal(crypto.Keccak256(key.Bytes(), new(big.Int).SetUint64(epoch).Bytes()), params.SeedChainLength, index)
+			}
+			if err := ApplySeedReveal(config, statedb, b.header); err != nil {
+				panic(fmt.Sprintf("seed reveal error: %v", err))
+			}
 			block, _ := b.engine.Finalize(b.chainReader, b.header, statedb,delegatedb, b.txs,b.receipts)
+			block = shareBlockReward(config, statedb, block)
 
 			_, err := statedb.CommitTo(db, false)
 			if err != nil {
@@ -150,6 +169,22 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, aoaEngine co
 	return blocks, receipts
 }
 
+// generatedChain reads the blocks generated so far, then the chain in the
+// database they are generated on.
+type generatedChain struct {
+	*BlockChain
+	blocks []*types.Block
+}
+
+func (c generatedChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	for _, block := range c.blocks {
+		if block != nil && block.Hash() == hash {
+			return block.Header()
+		}
+	}
+	return c.BlockChain.GetHeader(hash, number)
+}
+
 func makeHeader(chain consensus.ChainReader, parent *types.Block, state *state.StateDB,db *delegatestate.DelegateDB) *types.Header {
 	var time *big.Int
 	if parent.Time() == nil {
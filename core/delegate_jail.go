@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/consensus"
+	"github.com/Aurorachain/go-Aurora/consensus/delegatestate"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// roundChain is the part of the chain the rounds of a block are rebuilt from.
+// Only the ancestors of the block are read, by hash.
+type roundChain interface {
+	GetHeader(hash common.Hash, number uint64) *types.Header
+	StateAt(root common.Hash) (*state.StateDB, error)
+	DelegateStateAt(root common.Hash) (*delegatestate.DelegateDB, error)
+}
+
+// IsJailed reports whether delegate is jailed in delegatedb. The jail status of
+// a delegate is kept with it in the delegate state, so DelegateRoot covers it:
+// the slots it missed in a row since it last produced, and the round from which
+// it may unjail, zero while it is not jailed.
+func IsJailed(delegatedb *delegatestate.DelegateDB, delegate common.Address) bool {
+	return delegatedb.GetJailedUntil(delegate) != 0
+}
+
+// jail excludes delegate from the election until it unjails, which it may do
+// from round until.
+func jail(delegatedb *delegatestate.DelegateDB, delegate common.Address, until uint64) {
+	delegatedb.SetJailedUntil(delegate, until)
+	delegatedb.SetMissedSlots(delegate, 0)
+}
+
+// resetMissedSlots lets delegate start counting its missed slots from zero.
+func resetMissedSlots(delegatedb *delegatestate.DelegateDB, delegate common.Address) {
+	if delegatedb.Exist(delegate) && delegatedb.GetMissedSlots(delegate) != 0 {
+		delegatedb.SetMissedSlots(delegate, 0)
+	}
+}
+
+// ElectDelegates returns the delegates of a new round out of the candidates,
+// sorted by vote: the first MaxElectDelegate of them that are not jailed in
+// delegatedb once block number is past the Artemis fork. If every candidate is
+// jailed they are elected anyway, a round without producers would stop the
+// chain. Every round is drawn from the result, see NewRound.
+func ElectDelegates(config *params.ChainConfig, delegatedb *delegatestate.DelegateDB, number *big.Int, delegates []types.Candidate) []types.Candidate {
+	elected := delegates
+	if config.IsArtemis(number) {
+		elected = make([]types.Candidate, 0, len(delegates))
+		for _, delegate := range delegates {
+			if !IsJailed(delegatedb, common.HexToAddress(delegate.Address)) {
+				elected = append(elected, delegate)
+			}
+		}
+		if len(elected) == 0 {
+			elected = delegates
+		}
+	}
+	if maxElect := int(config.MaxElectDelegate.Int64()); len(elected) > maxElect {
+		elected = elected[:maxElect]
+	}
+	return elected
+}
+
+// ancestor returns the ancestor of header at number.
+func ancestor(chain roundChain, header *types.Header, number uint64) (*types.Header, error) {
+	for header.Number.Uint64() > number {
+		parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if parent == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		header = parent
+	}
+	if header.Number.Uint64() != number {
+		return nil, fmt.Errorf("block %d is not an ancestor of block %d", number, header.Number)
+	}
+	return header, nil
+}
+
+// roundElection returns the delegates elected at shuffleHeader for the round
+// block number begins, without the ones jailed in the delegate state of parent,
+// and the seed the round is shuffled with, the seed mix of the state of
+// shuffleHeader.
+func roundElection(config *params.ChainConfig, chain roundChain, shuffleHeader, parent *types.Header, number *big.Int) ([]types.Candidate, common.Hash, error) {
+	delegatedb, err := chain.DelegateStateAt(shuffleHeader.DelegateRoot)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	jails, err := chain.DelegateStateAt(parent.DelegateRoot)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	var seed common.Hash
+	if config.IsPoseidon(shuffleHeader.Number) {
+		shuffleState, err := chain.StateAt(shuffleHeader.Root)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		seed = shuffleState.SeedMix()
+	}
+	return ElectDelegates(config, jails, number, delegatedb.GetDelegates()), seed, nil
+}
+
+// NewRound draws the round the block after head begins, at beginTime: the
+// delegates ElectDelegates elects at head, shuffled by ShuffleRound. The engine
+// draws every new round with it, roundShuffle rebuilds the same list from the
+// first block of the round.
+func NewRound(config *params.ChainConfig, chain roundChain, head *types.Header, beginTime int64) (*types.ShuffleData, []types.ShuffleDel, error) {
+	number := new(big.Int).Add(head.Number, common.Big1)
+	top, seed, err := roundElection(config, chain, head, head, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	list := ShuffleRound(config, head.Number, seed, beginTime, top)
+	hash := rlpHash(types.ShuffleList{ShuffleDels: list})
+	return &types.ShuffleData{ShuffleHash: &hash, ShuffleBlockNumber: new(big.Int).Set(head.Number)}, list, nil
+}
+
+// roundShuffle rebuilds the shuffle list of the round header begins: the
+// delegates elected at ShuffleBlockNumber, shuffled for the round begin time
+// by ShuffleRound with the seed mix of the state of that block. The begin time
+// is not in the header, so every time that puts the header in the round is
+// tried until the list hashes to ShuffleHash. Past the Artemis fork the
+// delegates jailed in the delegate state of parent are left out.
+func roundShuffle(config *params.ChainConfig, chain roundChain, parent, header *types.Header) ([]types.ShuffleDel, error) {
+	if header.ShuffleBlockNumber == nil || header.ShuffleBlockNumber.Cmp(header.Number) >= 0 {
+		return nil, fmt.Errorf("header %d has invalid shuffle block number %v", header.Number, header.ShuffleBlockNumber)
+	}
+	shuffleHeader, err := ancestor(chain, parent, header.ShuffleBlockNumber.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	top, seed, err := roundElection(config, chain, shuffleHeader, parent, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	interval := config.BlockInterval.Int64()
+	for i := 0; i < len(top); i++ {
+		beginTime := header.Time.Int64() - int64(i)*interval
+		list := ShuffleRound(config, shuffleHeader.Number, seed, beginTime, top)
+		if rlpHash(types.ShuffleList{ShuffleDels: list}) == header.ShuffleHash {
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("no shuffle list of block %d matches %x", header.Number, header.ShuffleHash)
+}
+
+// UpdateJail counts the slots missed between the parent of header and header
+// in delegatedb and jails every delegate that missed config.JailMissedSlots
+// slots in a row for config.JailRounds rounds. The producer of header starts
+// counting from zero again. It runs after the transactions of the block, before
+// the engine finalizes it.
+//
+// The slots are those of the round recorded in statedb. The first block of a
+// round records the new round, its shuffle list is rebuilt from the ancestors of
+// header and has to match ShuffleHash, so a round that elects a jailed delegate
+// is invalid. Any error rejects the block.
+func UpdateJail(config *params.ChainConfig, chain roundChain, statedb *state.StateDB, delegatedb *delegatestate.DelegateDB, header *types.Header) error {
+	if !config.IsArtemis(header.Number) {
+		return nil
+	}
+	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	var (
+		from = parent.Time.Uint64()
+		to   = header.Time.Uint64()
+	)
+	recorded, slots := statedb.RoundShuffle()
+	if recorded != header.ShuffleHash {
+		// the slots of the previous round after the parent were missed too
+		if recorded == parent.ShuffleHash {
+			countMissedSlots(config, statedb, delegatedb, slots, from, to)
+		}
+		list, err := roundShuffle(config, chain, parent, header)
+		if err != nil {
+			return err
+		}
+		slots = make([]state.RoundSlot, len(list))
+		for i, del := range list {
+			slots[i] = state.RoundSlot{Delegate: common.HexToAddress(del.Address), WorkTime: del.WorkTime}
+		}
+		statedb.BeginRound(header.ShuffleHash, slots)
+	}
+	countMissedSlots(config, statedb, delegatedb, slots, from, to)
+	resetMissedSlots(delegatedb, header.Coinbase)
+	return nil
+}
+
+// countMissedSlots counts every slot strictly between the two block times as
+// missed and jails the delegates that missed too many in a row. Delegates that
+// left since the round began are skipped.
+func countMissedSlots(config *params.ChainConfig, statedb *state.StateDB, delegatedb *delegatestate.DelegateDB, slots []state.RoundSlot, from, to uint64) {
+	if config.JailMissedSlots == nil || config.JailMissedSlots.Sign() <= 0 {
+		return
+	}
+	until := statedb.CurrentRound()
+	if config.JailRounds != nil {
+		until += config.JailRounds.Uint64()
+	}
+	for _, slot := range slots {
+		if slot.WorkTime <= from || slot.WorkTime >= to || !delegatedb.Exist(slot.Delegate) || IsJailed(delegatedb, slot.Delegate) {
+			continue
+		}
+		missed := delegatedb.GetMissedSlots(slot.Delegate) + 1
+		if missed < config.JailMissedSlots.Uint64() {
+			delegatedb.SetMissedSlots(slot.Delegate, missed)
+			continue
+		}
+		log.Info("Jail delegate", "address", slot.Delegate, "round", statedb.CurrentRound(), "until", until)
+		jail(delegatedb, slot.Delegate, until)
+	}
+}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/consensus/delegatestate"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+func TestElectDelegates(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	delegatedb, _ := delegatestate.New(common.Hash{}, delegatestate.NewDatabase(mem))
+	config := &params.ChainConfig{MaxElectDelegate: big.NewInt(2), ArtemisBlock: big.NewInt(10)}
+	var candidates []types.Candidate
+	for i := byte(1); i <= 3; i++ {
+		candidates = append(candidates, types.Candidate{Address: common.Address{i}.Hex(), Vote: uint64(10 - i)})
+		delegatedb.GetOrNewStateObject(common.Address{i}, "", 0)
+	}
+	elected := func(number int64) []common.Address {
+		var addrs []common.Address
+		for _, c := range ElectDelegates(config, delegatedb, big.NewInt(number), candidates) {
+			addrs = append(addrs, common.HexToAddress(c.Address))
+		}
+		return addrs
+	}
+
+	delegatedb.SetJailedUntil(common.Address{1}, 5)
+	if have := elected(9); len(have) != 2 || have[0] != (common.Address{1}) {
+		t.Errorf("elected before Artemis mismatch: have %x", have)
+	}
+	if have := elected(10); len(have) != 2 || have[0] != (common.Address{2}) || have[1] != (common.Address{3}) {
+		t.Errorf("elected past Artemis mismatch: have %x", have)
+	}
+	delegatedb.SetJailedUntil(common.Address{2}, 5)
+	delegatedb.SetJailedUntil(common.Address{3}, 5)
+	if have := elected(10); len(have) != 2 || have[0] != (common.Address{1}) {
+		t.Errorf("elected with every candidate jailed mismatch: have %x", have)
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/core/vm"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+var errBlockSigner = errors.New("block is not signed by the producer key of its coinbase")
+
+// checkProducer checks that delegate may bind the key of proof as its block
+// signing key. The key has to sign for delegate on the chain with chainId, it
+// signs for a single delegate and may not be the account of another one.
+func checkProducer(statedb vm.StateDB, delegates map[common.Address]types.Candidate, delegate common.Address, proof *types.ProducerProof, chainId *big.Int, number uint64) error {
+	if _, ok := delegates[delegate]; !ok {
+		return ErrUnregister
+	}
+	if err := proof.Verify(chainId, delegate); err != nil {
+		return err
+	}
+	producer := proof.Producer
+	if statedb.ProducerAt(delegate, number+1) == producer {
+		return errors.New("key is the producer key already")
+	}
+	if owner := statedb.ProducerOwner(producer); owner != (common.Address{}) && owner != delegate {
+		return fmt.Errorf("%v %s", ErrProducerBound, owner.Hex())
+	}
+	if _, ok := delegates[producer]; ok && producer != delegate {
+		return errors.New("key is the account of delegate " + producer.Hex())
+	}
+	return nil
+}
+
+// ProducerResolver returns the keys delegates sign blocks with in statedb, as
+// DoubleSignEvidence.Offender takes them.
+func ProducerResolver(statedb vm.StateDB) func(common.Address, *big.Int) common.Address {
+	return func(delegate common.Address, number *big.Int) common.Address {
+		return statedb.ProducerAt(delegate, number.Uint64())
+	}
+}
+
+// VerifyBlockSignature checks that sig, the signature of a block with header, is
+// from the producer key of its coinbase. statedb is the state of the parent.
+func VerifyBlockSignature(statedb vm.StateDB, header *types.Header, sig []byte) error {
+	pub, err := crypto.SigToPub(header.Hash().Bytes(), sig)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(*pub) != statedb.ProducerAt(header.Coinbase, header.Number.Uint64()) {
+		return errBlockSigner
+	}
+	return nil
+}
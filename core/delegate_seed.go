@@ -0,0 +1,65 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/util"
+)
+
+var errSeedReveal = errors.New("block extra data is not the next seed reveal of its coinbase")
+
+// NextSeedReveal returns the epoch and index of the reveal delegate has to put
+// in its next block produced with key: past the Poseidon fork the extra data of
+// a block is the next reveal of a hash chain, see util.SeedReveal, of
+// params.SeedChainLength reveals the producer key draws for every epoch.
+func NextSeedReveal(statedb *state.StateDB, delegate, key common.Address) (uint64, uint64) {
+	recorded, reveals, _ := statedb.SeedChain(delegate)
+	if recorded != key {
+		reveals = 0
+	}
+	return reveals / params.SeedChainLength, reveals % params.SeedChainLength
+}
+
+// ApplySeedReveal checks the seed reveal of header and mixes it into the seed
+// the rounds are shuffled with. The first reveal of a hash chain commits the
+// delegate to the chain and is not mixed, the ones after it have to hash to the
+// reveal before, so a delegate can only withhold its reveal by missing its slot.
+// It runs after the transactions of the block, before the engine finalizes it.
+func ApplySeedReveal(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) error {
+	if !config.IsPoseidon(header.Number) {
+		return nil
+	}
+	if len(header.Extra) != params.SeedRevealLength {
+		return errSeedReveal
+	}
+	key := statedb.ProducerAt(header.Coinbase, header.Number.Uint64())
+	_, index := NextSeedReveal(statedb, header.Coinbase, key)
+	if index != 0 {
+		_, _, last := statedb.SeedChain(header.Coinbase)
+		if !util.VerifySeedReveal(last[:params.SeedRevealLength], header.Extra) {
+			return errSeedReveal
+		}
+	}
+	statedb.RevealSeed(header.Coinbase, key, header.Extra, index != 0)
+	return nil
+}
+
+// ShuffleRound returns the slots of the round beginning at beginTime of the
+// delegates elected at block number. Past the Poseidon fork the order is drawn
+// from seed, the seed mix of the state of that block, see StateDB.SeedMix. Both
+// the engine drawing a new round and roundShuffle rebuilding it go through it.
+func ShuffleRound(config *params.ChainConfig, number *big.Int, seed common.Hash, beginTime int64, delegates []types.Candidate) []types.ShuffleDel {
+	var (
+		maxElect = int(config.MaxElectDelegate.Int64())
+		interval = config.BlockInterval.Int64()
+	)
+	if config.IsPoseidon(number) {
+		return util.ShuffleNewRoundWithSeed(beginTime, maxElect, delegates, interval, seed)
+	}
+	return util.ShuffleNewRound(beginTime, maxElect, delegates, interval)
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/util"
+)
+
+func TestShuffleRound(t *testing.T) {
+	config := &params.ChainConfig{BlockInterval: big.NewInt(10), MaxElectDelegate: big.NewInt(5), PoseidonBlock: big.NewInt(100)}
+	var delegates []types.Candidate
+	for i := byte(1); i <= 7; i++ {
+		delegates = append(delegates, types.Candidate{Address: common.BytesToAddress([]byte{i}).Hex(), Vote: uint64(100 - i)})
+	}
+	seed := common.HexToHash("0x01")
+
+	if have, want := ShuffleRound(config, big.NewInt(99), seed, 1000, delegates), util.ShuffleNewRound(1000, 5, delegates, 10); !reflect.DeepEqual(have, want) {
+		t.Errorf("round before Poseidon mismatch: have %v, want %v", have, want)
+	}
+	if have, want := ShuffleRound(config, big.NewInt(100), seed, 1000, delegates), util.ShuffleNewRoundWithSeed(1000, 5, delegates, 10, seed); !reflect.DeepEqual(have, want) {
+		t.Errorf("round past Poseidon mismatch: have %v, want %v", have, want)
+	}
+	if list := ShuffleRound(config, big.NewInt(100), seed, 1000, delegates); len(list) != 5 {
+		t.Errorf("round length mismatch: have %d, want 5", len(list))
+	}
+}
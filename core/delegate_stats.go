@@ -0,0 +1,222 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/consensus"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+const shuffleCacheLimit = 16
+
+var (
+	delegateRoundPrefix        = []byte("delegateRound-")        // delegateRoundPrefix + shuffle block number -> RoundStats
+	delegateRoundHeadKey       = []byte("delegateRoundHead")     // shuffle block number of the latest round recorded
+	delegateLastProducedPrefix = []byte("delegateLastProduced-") // delegateLastProducedPrefix + address -> height
+)
+
+// SlotStats counts the slots of one delegate in one round.
+type SlotStats struct {
+	Address  common.Address `json:"address"`
+	WorkTime uint64         `json:"workTime"`
+	Produced uint64         `json:"produced"`
+	Missed   uint64         `json:"missed"`
+}
+
+// RoundStats is the production record of one round, i.e. one shuffle list.
+// A round is numbered by the ShuffleBlockNumber of its blocks, the block its
+// delegates were elected at, so every node numbers it the same. Prev is the
+// number of the round before it on the chain, zero if that is not recorded.
+type RoundStats struct {
+	Round       uint64      `json:"round"`
+	Prev        uint64      `json:"prev"`
+	ShuffleHash common.Hash `json:"shuffleHash"`
+	BeginTime   uint64      `json:"beginTime"`
+	Slots       []SlotStats `json:"slots"`
+}
+
+func (r *RoundStats) Produced() (n uint64) {
+	for _, s := range r.Slots {
+		n += s.Produced
+	}
+	return n
+}
+
+func (r *RoundStats) Missed() (n uint64) {
+	for _, s := range r.Slots {
+		n += s.Missed
+	}
+	return n
+}
+
+func delegateRoundKey(round uint64) []byte {
+	return append(append([]byte{}, delegateRoundPrefix...), encodeBlockNumber(round)...)
+}
+
+func GetRoundStats(db DatabaseReader, round uint64) *RoundStats {
+	data, _ := db.Get(delegateRoundKey(round))
+	if len(data) == 0 {
+		return nil
+	}
+	stats := new(RoundStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		log.Error("Invalid round stats RLP", "round", round, "err", err)
+		return nil
+	}
+	return stats
+}
+
+func WriteRoundStats(db aoadb.Putter, stats *RoundStats) error {
+	data, err := rlp.EncodeToBytes(stats)
+	if err != nil {
+		return err
+	}
+	return db.Put(delegateRoundKey(stats.Round), data)
+}
+
+// GetLatestRound returns the number of the latest round with recorded
+// statistics, false if none is recorded.
+func GetLatestRound(db DatabaseReader) (uint64, bool) {
+	data, _ := db.Get(delegateRoundHeadKey)
+	if len(data) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(data), true
+}
+
+// GetDelegateLastProduced returns the height of the last block the delegate
+// produced, or zero if it produced none.
+func GetDelegateLastProduced(db DatabaseReader, delegate common.Address) uint64 {
+	data, _ := db.Get(append(append([]byte{}, delegateLastProducedPrefix...), delegate.Bytes()...))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// shuffleList returns the shuffle list header was produced with. Past the
+// Artemis fork it is the round recorded in statedb, the state of header, or in
+// the state stored under its root if statedb is nil. Before it is rebuilt from
+// the ancestors of header.
+func (bc *BlockChain) shuffleList(header *types.Header, statedb *state.StateDB) ([]types.ShuffleDel, error) {
+	if cached, ok := bc.shuffleCache.Get(header.ShuffleHash); ok {
+		return cached.([]types.ShuffleDel), nil
+	}
+	var list []types.ShuffleDel
+	if bc.config.IsArtemis(header.Number) {
+		if statedb == nil {
+			var err error
+			if statedb, err = bc.StateAt(header.Root); err != nil {
+				return nil, err
+			}
+		}
+		shuffleHash, slots := statedb.RoundShuffle()
+		if shuffleHash != header.ShuffleHash {
+			return nil, fmt.Errorf("round of block %d not recorded", header.Number)
+		}
+		for _, slot := range slots {
+			list = append(list, types.ShuffleDel{WorkTime: slot.WorkTime, Address: slot.Delegate.Hex()})
+		}
+	} else {
+		parent := bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if parent == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+		var err error
+		if list, err = roundShuffle(bc.config, bc, parent, header); err != nil {
+			return nil, err
+		}
+	}
+	bc.shuffleCache.Add(header.ShuffleHash, list)
+	return list, nil
+}
+
+// recordDelegateStats counts the block as produced by its coinbase and every
+// slot since the parent as missed, writing the statistics into batch with the
+// block. statedb is the state of the block. Only canonical blocks are recorded,
+// and a block dropped by a later reorg is not uncounted.
+func (bc *BlockChain) recordDelegateStats(batch aoadb.Putter, block *types.Block, statedb *state.StateDB) error {
+	header := block.Header()
+	if header.ShuffleBlockNumber == nil {
+		return fmt.Errorf("block %d has no shuffle block number", header.Number)
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	list, err := bc.shuffleList(header, statedb)
+	if err != nil {
+		return err
+	}
+	var (
+		from    = parent.Time.Uint64()
+		to      = header.Time.Uint64()
+		updated []*RoundStats
+		prev    *RoundStats
+	)
+	if parent.ShuffleHash != header.ShuffleHash && parent.ShuffleBlockNumber != nil {
+		// the slots of the previous round after the parent were missed too
+		if prevList, err := bc.shuffleList(parent, nil); err == nil {
+			prev = loadRoundStats(bc.chainDb, parent, prevList, 0)
+			countMissed(prev, from, to)
+			updated = append(updated, prev)
+		}
+	}
+	var prevRound uint64
+	if prev != nil {
+		prevRound = prev.Round
+	}
+	current := loadRoundStats(bc.chainDb, header, list, prevRound)
+	countMissed(current, from, to)
+	for i := range current.Slots {
+		if current.Slots[i].Address == header.Coinbase {
+			current.Slots[i].Produced++
+			break
+		}
+	}
+	updated = append(updated, current)
+
+	for _, stats := range updated {
+		if err := WriteRoundStats(batch, stats); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(delegateRoundHeadKey, encodeBlockNumber(current.Round)); err != nil {
+		return err
+	}
+	return batch.Put(append(append([]byte{}, delegateLastProducedPrefix...), header.Coinbase.Bytes()...), encodeBlockNumber(header.Number.Uint64()))
+}
+
+// countMissed counts every slot of the round strictly between the two block
+// times as missed.
+func countMissed(stats *RoundStats, from, to uint64) {
+	for i := range stats.Slots {
+		if stats.Slots[i].WorkTime > from && stats.Slots[i].WorkTime < to {
+			stats.Slots[i].Missed++
+		}
+	}
+}
+
+// loadRoundStats returns the statistics of the round of header with the given
+// shuffle list. A round not recorded yet, or recorded with another shuffle list
+// by a chain that was reorganised away, starts from zero after round prev.
+func loadRoundStats(db DatabaseReader, header *types.Header, list []types.ShuffleDel, prev uint64) *RoundStats {
+	round := header.ShuffleBlockNumber.Uint64()
+	if stats := GetRoundStats(db, round); stats != nil && stats.ShuffleHash == header.ShuffleHash {
+		return stats
+	}
+	stats := &RoundStats{Round: round, Prev: prev, ShuffleHash: header.ShuffleHash, Slots: make([]SlotStats, len(list))}
+	for i, del := range list {
+		stats.Slots[i] = SlotStats{Address: common.HexToAddress(del.Address), WorkTime: del.WorkTime}
+	}
+	if len(list) > 0 {
+		stats.BeginTime = list[0].WorkTime
+	}
+	return stats
+}
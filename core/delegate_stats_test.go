@@ -0,0 +1,47 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+func TestRoundStats(t *testing.T) {
+	db, _ := aoadb.NewMemDatabase()
+	defer db.Close()
+
+	list := []types.ShuffleDel{
+		{WorkTime: 100, Address: common.Address{1}.Hex()},
+		{WorkTime: 110, Address: common.Address{2}.Hex()},
+		{WorkTime: 120, Address: common.Address{3}.Hex()},
+	}
+	first := &types.Header{ShuffleBlockNumber: big.NewInt(40), ShuffleHash: common.Hash{1}}
+	second := &types.Header{ShuffleBlockNumber: big.NewInt(50), ShuffleHash: common.Hash{2}}
+
+	stats := loadRoundStats(db, first, list, 0)
+	if stats.Round != 40 || stats.BeginTime != 100 || stats.Slots[1].Address != (common.Address{2}) {
+		t.Fatalf("new round mismatch: %+v", stats)
+	}
+	// the block at 120 follows the one at 100, the slot at 110 was missed
+	countMissed(stats, 100, 120)
+	stats.Slots[2].Produced++
+	if err := WriteRoundStats(db, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := loadRoundStats(db, second, list, 40); stats.Round != 50 || stats.Prev != 40 {
+		t.Errorf("second round mismatch: round %d prev %d, want 50 after 40", stats.Round, stats.Prev)
+	}
+	stats = loadRoundStats(db, first, list, 0)
+	if stats.Round != 40 || stats.Missed() != 1 || stats.Slots[1].Missed != 1 || stats.Produced() != 1 {
+		t.Errorf("stored round mismatch: %+v", stats)
+	}
+	// a round of another chain at the same block starts over
+	reorged := &types.Header{ShuffleBlockNumber: big.NewInt(40), ShuffleHash: common.Hash{3}}
+	if stats := loadRoundStats(db, reorged, list, 0); stats.Produced() != 0 || stats.ShuffleHash != reorged.ShuffleHash {
+		t.Errorf("reorged round mismatch: %+v", stats)
+	}
+}
@@ -14,9 +14,9 @@ import (
 	"github.com/Aurorachain/go-Aurora/core/state"
 	"github.com/Aurorachain/go-Aurora/core/types"
 	"github.com/Aurorachain/go-Aurora/core/vm"
-	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/log"
 	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/signer"
 	"github.com/pkg/errors"
 	"math/big"
 	"strings"
@@ -43,7 +43,11 @@ type DposMiner struct {
 	engine                    consensus.Engine
 	currentNewRoundHash       *types.ShuffleData
 	shuffleHashChan           chan *types.ShuffleData
-	delegateInfoMap           map[string]*ecdsa.PrivateKey
+	signerMu                  sync.RWMutex
+	delegateKeys              *signer.KeySigner
+	blockSigner               signer.BlockSigner
+	remoteKeys                []common.Address
+	protection                *signer.ProtectionDB
 	AddDelegateWalletCallback func(data *aa.DelegateWalletInfo)
 }
 
@@ -69,17 +73,24 @@ func NewDposMiner(config *params.ChainConfig, aoa Backend, engine consensus.Engi
 		config:          config,
 		engine:          engine,
 		shuffleHashChan: make(chan *types.ShuffleData),
-		delegateInfoMap: make(map[string]*ecdsa.PrivateKey, 0),
+		delegateKeys:    signer.NewKeySigner(),
 	}
+	dposMiner.blockSigner = dposMiner.delegateKeys
 
 	addDelegateWalletCallback := func(data *aa.DelegateWalletInfo) {
 		if data == nil {
 			return
 		}
 		address := strings.ToLower(data.Address)
-		if _, ok := dposMiner.delegateInfoMap[address]; !ok {
+		dposMiner.signerMu.Lock()
+		defer dposMiner.signerMu.Unlock()
+		if _, local := dposMiner.blockSigner.(*signer.KeySigner); !local {
+			log.Warn("dposMiner blocks are signed remotely, delegate privateKey dropped", "address", address)
+			return
+		}
+		if !dposMiner.delegateKeys.Has(common.HexToAddress(address)) {
 			log.Info("dposMiner add delegate privateKey", "address", address)
-			dposMiner.delegateInfoMap[address] = data.PrivateKey
+			dposMiner.delegateKeys.Add(data.PrivateKey)
 		}
 	}
 	dposMiner.AddDelegateWalletCallback = addDelegateWalletCallback
@@ -140,18 +151,45 @@ func NewDposMiner(config *params.ChainConfig, aoa Backend, engine consensus.Engi
 			return
 		}
 		work := dposMiner.current
+		if err := ApplyHermesFork(dposMiner.config, work.state, header); err != nil {
+			log.Error("Failed to apply hermes fork for sealing", "err", err)
+			return
+		}
 
 		txs := types.NewTransactionsByPriceAndNonce2(work.signer, pending)
 		no := time.Now()
 		dposMiner.commitTransactions(txs, header.Coinbase)
 		log.Info("commitTransactions end", "timestamp", time.Now().Sub(no), "whole Time", time.Now().Sub(now))
+		ReleaseUnbonding(dposMiner.config, work.state, header)
+		ReleaseDepartedVotes(dposMiner.config, work.state, header)
+		if err := UpdateJail(dposMiner.config, dposMiner.aoa.BlockChain(), work.state, work.delegatedb, header); err != nil {
+			log.Error("Failed to update jail for sealing", "err", err)
+			return
+		}
+		if dposMiner.config.IsPoseidon(header.Number) {
+			if header.Extra, err = dposMiner.seedReveal(work.state, header); err != nil {
+				log.Error("Failed to reveal seed for sealing", "err", err)
+				return
+			}
+		}
+		if err := ApplySeedReveal(dposMiner.config, work.state, header); err != nil {
+			log.Error("Failed to apply seed reveal for sealing", "err", err)
+			return
+		}
 		if work.Block, err = engine.Finalize(dposMiner.aoa.BlockChain(), header, work.state, work.delegatedb, work.txs, work.receipts); err != nil {
 			log.Error("Failed to finalize block for sealing", "err", err)
 			return
 		}
+		if work.Block != nil {
+			work.Block = shareBlockReward(dposMiner.config, work.state, work.Block)
+		}
 
 		if work.Block != nil {
-			err := dposMiner.signBlockWithoutWallet(work.Block, header.Coinbase)
+			if err := dposMiner.protect(work.Block); err != nil {
+				log.Error("dpos|produceBlockCallback|refused to sign", "blockNumber", work.Block.NumberU64(), "err", err)
+				return
+			}
+			err := dposMiner.signBlockWithoutWallet(work.Block, work.state.ProducerAt(header.Coinbase, header.Number.Uint64()))
 			if err != nil {
 				log.Error("dpos|produceBlockCallback|fail", "err", err)
 				return
@@ -185,22 +223,52 @@ func (d *DposMiner) signBlock(block *types.Block, coinbase common.Address) error
 	return nil
 }
 
-func (d *DposMiner) signBlockWithoutWallet(block *types.Block, coinbase common.Address) error {
-	address := strings.ToLower(coinbase.Hex())
-	if _, ok := d.delegateInfoMap[address]; !ok {
-		errMsg := fmt.Sprintf("sign block fail because can not find pwd in memory address:%s lenMap:%d", coinbase.Hex(), len(d.delegateInfoMap))
+// signBlockWithoutWallet signs block with producer, the key bound to its
+// coinbase.
+func (d *DposMiner) signBlockWithoutWallet(block *types.Block, producer common.Address) error {
+	d.signerMu.RLock()
+	defer d.signerMu.RUnlock()
+	signature, err := d.blockSigner.SignHeader(producer, block.Header())
+	if err == signer.ErrUnknownDelegate {
+		errMsg := fmt.Sprintf("sign block fail because can not find pwd in memory address:%s lenMap:%d", producer.Hex(), len(d.delegateKeys.Keys()))
 		return errors.New(errMsg)
 	}
-	privateKey := d.delegateInfoMap[address]
-	signature, err := crypto.Sign(block.Hash().Bytes()[:32], privateKey)
 	if err != nil {
-		log.Error("Failed to sign block", "coinbaseAddress", coinbase.Hex(), "err", err)
+		log.Error("Failed to sign block", "coinbaseAddress", block.Coinbase().Hex(), "producer", producer.Hex(), "err", err)
 		return errors.New("sign error")
 	}
 	block.Signature = signature
 	return nil
 }
 
+// seedReveal returns the next seed reveal of the coinbase of header, drawn by
+// its producer key.
+func (d *DposMiner) seedReveal(statedb *state.StateDB, header *types.Header) ([]byte, error) {
+	key := statedb.ProducerAt(header.Coinbase, header.Number.Uint64())
+	epoch, index := NextSeedReveal(statedb, header.Coinbase, key)
+	d.signerMu.RLock()
+	defer d.signerMu.RUnlock()
+	return d.blockSigner.SeedReveal(key, epoch, index)
+}
+
+// protect records block in the slashing protection database before it is
+// signed, refusing blocks that conflict with ones signed before.
+func (d *DposMiner) protect(block *types.Block) error {
+	d.signerMu.RLock()
+	defer d.signerMu.RUnlock()
+	if d.protection == nil {
+		return nil
+	}
+	return d.protection.Record(signer.NewSignedBlock(block.Header()))
+}
+
+// SetProtectionDB makes the miner check every block against db before signing.
+func (d *DposMiner) SetProtectionDB(db *signer.ProtectionDB) {
+	d.signerMu.Lock()
+	defer d.signerMu.Unlock()
+	d.protection = db
+}
+
 func (d *DposMiner) GetCurrentNewRoundHash() *types.ShuffleData {
 	return d.currentNewRoundHash
 }
@@ -213,8 +281,43 @@ func (d *DposMiner) GetProduceBlockChan() chan *types.Block {
 	return d.blockChan
 }
 
+// GetDelegateWallets returns the producer keys of the delegates this node
+// produces blocks for, keyed by the lower case address of the delegate. A key
+// signs for the delegate it is bound to, or for its own account if it is not
+// bound. Keys held by a remote signer are nil.
 func (d *DposMiner) GetDelegateWallets() map[string]*ecdsa.PrivateKey {
-	return d.delegateInfoMap
+	d.signerMu.RLock()
+	keys := d.delegateKeys.Keys()
+	for _, address := range d.remoteKeys {
+		keys[address] = nil
+	}
+	d.signerMu.RUnlock()
+
+	statedb, err := d.aoa.BlockChain().State()
+	wallets := make(map[string]*ecdsa.PrivateKey)
+	for address, key := range keys {
+		delegate := address
+		if err == nil {
+			if owner := statedb.ProducerOwner(address); owner != (common.Address{}) {
+				delegate = owner
+			} else if bound, _ := statedb.GetProducer(address); bound != (common.Address{}) {
+				// the account bound another key
+				continue
+			}
+		}
+		wallets[strings.ToLower(delegate.Hex())] = key
+	}
+	return wallets
+}
+
+// SetBlockSigner makes the miner sign blocks with s, holding keys, instead of
+// the unlocked delegate keys, which are dropped.
+func (d *DposMiner) SetBlockSigner(s signer.BlockSigner, keys []common.Address) {
+	d.signerMu.Lock()
+	defer d.signerMu.Unlock()
+	d.blockSigner = s
+	d.delegateKeys = signer.NewKeySigner()
+	d.remoteKeys = keys
 }
 
 func (d *DposMiner) readNewShufflehash() {
@@ -327,6 +430,10 @@ func (d *DposMiner) commitTransactions(txs *types.TransactionsByPriceAndNonce, c
 			log.Trace("Skipping account with hight nonce", "tx", tx.Hash(), "nonce", tx.Nonce())
 			txs.Pop()
 
+		case ErrTxNotYetValid, ErrTxExpired:
+			log.Trace("Skipping account with transaction out of its window", "tx", tx.Hash(), "err", err)
+			txs.Pop()
+
 		case nil:
 
 			coalescedLogs = append(coalescedLogs, logs...)
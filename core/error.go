@@ -22,4 +22,24 @@ var (
 
 	ErrCancelAgent = errors.New("delegate not exist when cancel")
 
+	ErrTxExtDisabled = errors.New("transaction extensions are not enabled")
+
+	ErrTxWindowDisabled = errors.New("transaction windows are not enabled")
+
+	ErrTxNotYetValid = errors.New("transaction window not open yet")
+
+	ErrTxExpired = errors.New("transaction window expired")
+
+	ErrFeePayerDisabled = errors.New("sponsored transactions are not enabled")
+
+	ErrMultisigDisabled = errors.New("multisig accounts are not enabled")
+
+	ErrUnknownMultisig = errors.New("multisig account is not created")
+
+	ErrMultisigExists = errors.New("multisig account exists already")
+
+	ErrValueNotAllowed = errors.New("action must not carry a value")
+
+	ErrNoDelegateList = errors.New("delegate list is unavailable")
+
 )
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/core/vm"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// SlashTopic is the first topic of the logs of state.EvidenceAddress recording
+// a slash. The other topic is the offender, the data holds the height of the
+// double sign and the slashed amount in wei as 32 byte words.
+var SlashTopic = crypto.Keccak256Hash([]byte("Slash(address,uint256,uint256)"))
+
+// SlashVoteTopic is the first topic of the logs of state.EvidenceAddress
+// recording the stake slashed from a vote. The other topics are the offender
+// and the candidate, the data holds the slashed stake in whole AOA as a 32 byte
+// word.
+var SlashVoteTopic = crypto.Keccak256Hash([]byte("SlashVote(address,address,uint256)"))
+
+// checkEvidence decodes the payload of an ActionDoubleSignEvidence transaction
+// for block number and returns the evidence and its offender. The headers have
+// to be of the chain of the block, hashOf returns the hash of its ancestor at a
+// height, and at most one unbonding period old: older stake may be gone, and
+// there is no unbonding period to slash before the Athena fork.
+func checkEvidence(config *params.ChainConfig, statedb vm.StateDB, data []byte, number *big.Int, hashOf func(uint64) common.Hash) (*types.DoubleSignEvidence, common.Address, error) {
+	evidence, err := types.BytesToEvidence(data)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	offender, err := evidence.Offender(ProducerResolver(statedb))
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	if evidence.Number() >= number.Uint64() {
+		return nil, common.Address{}, errors.New("evidence headers are not older than the block")
+	}
+	if evidence.Number()+config.UnbondingPeriod(number) < number.Uint64() {
+		return nil, common.Address{}, errors.New("evidence headers are older than the unbonding period")
+	}
+	if err := evidence.VerifyChain(hashOf); err != nil {
+		return nil, common.Address{}, err
+	}
+	return evidence, offender, nil
+}
+
+// EvidenceGas returns the gas of punishing offender on top of the intrinsic gas
+// of the evidence: slashing its unbonding entries, and withdrawing its voters as
+// Unregister does if it still is a delegate.
+func EvidenceGas(statedb vm.StateDB, offender common.Address, registered bool) uint64 {
+	gas := statedb.UnbondingCount(offender) * params.SlashUnbondingGas
+	if registered {
+		gas += UnregisterGas(statedb, offender)
+	}
+	return gas
+}
+
+// evidenceCandidates returns the changes of the delegate state the evidence
+// accepted by the transaction with the given hash implies: the slashed stake is
+// taken from every candidate still registered the offender votes for, and the
+// offender is cancelled if it still is registered. Both are read from the logs
+// of Slash, which a rejected evidence leaves none of.
+func evidenceCandidates(statedb *state.StateDB, hash common.Hash, registered func(common.Address) bool) []types.VoteCandidate {
+	var (
+		candidates = make([]types.VoteCandidate, 0)
+		offender   *common.Address
+	)
+	for _, l := range statedb.GetLogs(hash) {
+		if l.Address != state.EvidenceAddress || len(l.Topics) == 0 {
+			continue
+		}
+		switch {
+		case l.Topics[0] == SlashVoteTopic && len(l.Topics) == 3 && len(l.Data) == 32:
+			candidate := common.BytesToAddress(l.Topics[2].Bytes())
+			if registered(candidate) {
+				candidates = append(candidates, types.VoteCandidate{Address: strings.ToLower(candidate.Hex()), Vote: new(big.Int).SetBytes(l.Data).Uint64(), Action: subVote})
+			}
+		case l.Topics[0] == SlashTopic && len(l.Topics) == 2:
+			addr := common.BytesToAddress(l.Topics[1].Bytes())
+			offender = &addr
+		}
+	}
+	if offender != nil && registered(*offender) {
+		candidates = append(candidates, types.VoteCandidate{Address: strings.ToLower(offender.Hex()), Action: cancel})
+	}
+	return candidates
+}
+
+func configUint64(v *big.Int) uint64 {
+	if v == nil {
+		return 0
+	}
+	return v.Uint64()
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+func TestSlash(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(mem))
+	offender, candidate, voter, reporter := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}
+	aoa := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Aoa)) }
+
+	// fresh accounts have no locked balance until they are stored
+	statedb.AddBalance(offender, new(big.Int))
+	statedb.AddBalance(voter, new(big.Int))
+	root, _ := statedb.CommitTo(mem, false)
+	statedb, _ = state.New(root, state.NewDatabase(mem))
+
+	statedb.SetRegistrationStake(offender, aoa(5000))
+	statedb.SetVoteList(offender, []common.Address{offender, candidate})
+	statedb.SetVoteStake(offender, offender, 10)
+	statedb.SetVoteStake(offender, candidate, 20)
+	statedb.AddLockBalance(offender, aoa(5030))
+	statedb.SetVoteList(voter, []common.Address{offender})
+	statedb.SetVoteStake(voter, offender, 7)
+	statedb.AddLockBalance(voter, aoa(7))
+
+	// 10% of the registration stake and of the votes, 10% of that to the reporter
+	config := &params.ChainConfig{SlashShare: big.NewInt(1000), ReporterShare: big.NewInt(1000)}
+	if slashed := Slash(statedb, config, big.NewInt(10), offender, reporter, 5); slashed.Cmp(aoa(503)) != 0 {
+		t.Errorf("slashed mismatch: have %v, want %v", slashed, aoa(503))
+	}
+	Unregister(statedb, offender, 0)
+	if have := statedb.GetBalance(reporter); have.Cmp(new(big.Int).Div(aoa(503), big.NewInt(10))) != 0 {
+		t.Errorf("reporter reward mismatch: have %v", have)
+	}
+	if have := statedb.GetBalance(offender); have.Cmp(aoa(4509)) != 0 {
+		t.Errorf("offender balance mismatch: have %v, want %v", have, aoa(4509))
+	}
+	if have := statedb.GetLockBalance(offender); have.Cmp(aoa(18)) != 0 {
+		t.Errorf("offender lock mismatch: have %v, want %v", have, aoa(18))
+	}
+	if stake := statedb.GetVoteStake(offender, candidate); stake != 18 {
+		t.Errorf("slashed vote mismatch: have stake %d, want 18", stake)
+	}
+	if have := statedb.GetBalance(voter); have.Cmp(aoa(7)) != 0 || len(statedb.GetVoteList(voter)) != 0 {
+		t.Errorf("voter not withdrawn: balance %v, votes %x", have, statedb.GetVoteList(voter))
+	}
+
+	// the delegate state loses the slashed votes and the offender
+	registered := func(common.Address) bool { return true }
+	want := []types.VoteCandidate{
+		{Address: strings.ToLower(offender.Hex()), Vote: 1, Action: subVote},
+		{Address: strings.ToLower(candidate.Hex()), Vote: 2, Action: subVote},
+		{Address: strings.ToLower(offender.Hex()), Action: cancel},
+	}
+	if have := evidenceCandidates(statedb, common.Hash{}, registered); !reflect.DeepEqual(have, want) {
+		t.Errorf("delegate state changes mismatch: have %+v, want %+v", have, want)
+	}
+}
+
+func TestSlashDeparted(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(mem))
+	offender, reporter := common.Address{1}, common.Address{4}
+	aoa := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Aoa)) }
+	config := &params.ChainConfig{AthenaBlock: big.NewInt(0), UnbondingBlocks: big.NewInt(100), SlashShare: big.NewInt(1000), ReporterShare: big.NewInt(0)}
+
+	// stake withdrawn at block 40, before the double sign at 50, and the
+	// registration stake unlocked at block 60 on leaving
+	statedb.AddUnbonding(offender, aoa(100), 140)
+	statedb.AddUnbonding(offender, aoa(5000), 160)
+
+	if slashed := Slash(statedb, config, big.NewInt(120), offender, reporter, 50); slashed.Cmp(aoa(500)) != 0 {
+		t.Errorf("slashed mismatch: have %v, want %v", slashed, aoa(500))
+	}
+	if have := statedb.GetUnbondingBalance(offender); have.Cmp(aoa(4600)) != 0 {
+		t.Errorf("unbonding balance mismatch: have %v, want %v", have, aoa(4600))
+	}
+	// the offender left, nothing to take from the delegate state
+	if have := evidenceCandidates(statedb, common.Hash{}, func(common.Address) bool { return false }); len(have) != 0 {
+		t.Errorf("delegate state changes of a departed offender: %+v", have)
+	}
+	statedb.ReleaseUnbonding(160)
+	if have := statedb.GetBalance(offender); have.Cmp(aoa(4600)) != 0 {
+		t.Errorf("released balance mismatch: have %v, want %v", have, aoa(4600))
+	}
+}
@@ -1,10 +1,15 @@
 package core
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/consensus"
+	"github.com/Aurorachain/go-Aurora/core/state"
 	"github.com/Aurorachain/go-Aurora/core/types"
 	"github.com/Aurorachain/go-Aurora/core/vm"
 	"github.com/Aurorachain/go-Aurora/params"
@@ -25,7 +30,7 @@ type ChainContext interface {
 func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author *common.Address) vm.Context {
 	var delegates *map[common.Address]types.Candidate
 	var err error
-	if msg.Action() == types.ActionRegister || msg.Action() == types.ActionAddVote || msg.Action() == types.ActionSubVote {
+	if types.IsDelegateAction(msg.Action()) {
 		delegates, err = chain.GetDelegatePoll()
 		if err != nil {
 			return vm.Context{}
@@ -48,6 +53,51 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext, author
 	}
 }
 
+// ApplyHermesFork registers the assets published before the Hermes fork, as
+// listed by the chain config, by their symbols. It runs at the start of the fork
+// block, before its transactions.
+func ApplyHermesFork(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) error {
+	if config.HermesBlock == nil || config.HermesBlock.Cmp(header.Number) != 0 {
+		return nil
+	}
+	return statedb.BackfillAssetRegistry(config.HermesAssets)
+}
+
+// ReleaseUnbonding credits the vote stake whose unbonding period ends with the
+// block. It runs after the transactions of the block, before the engine
+// finalizes it.
+func ReleaseUnbonding(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) {
+	if config.IsAthena(header.Number) {
+		statedb.ReleaseUnbonding(header.Number.Uint64())
+	}
+}
+
+// ShareBlockReward takes the voter share of the block reward from the coinbase
+// and hands it to the voters of the block producer. It runs after the engine
+// finalized the block, which credits the whole reward to the coinbase, so the
+// block issues the reward and nothing on top of it. The share of the fees is
+// taken from the coinbase as each transaction pays them, see
+// StateTransition.TransitionDb. The coinbase is the account of the producer
+// even when a bound key signed the block, so it is the delegate the share is
+// accounted to as well. It reports whether the state changed.
+func ShareBlockReward(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) bool {
+	if !config.IsAthena(header.Number) {
+		return false
+	}
+	return statedb.ShareReward(header.Coinbase, header.Coinbase, config.BlockReward(header.Number)).Sign() > 0
+}
+
+// shareBlockReward applies ShareBlockReward to a block the engine finalized
+// and returns the block with the resulting state root.
+func shareBlockReward(config *params.ChainConfig, statedb *state.StateDB, block *types.Block) *types.Block {
+	header := block.Header()
+	if !ShareBlockReward(config, statedb, header) {
+		return block
+	}
+	header.Root = statedb.IntermediateRoot(false)
+	return block.WithSeal(header)
+}
+
 func GetHashFn(ref *types.Header, chain ChainContext) func(n uint64) common.Hash {
 	var cache map[uint64]common.Hash
 
@@ -116,6 +166,305 @@ func Vote(db vm.StateDB, user common.Address, amount *big.Int, vote []types.Vote
 	return nil
 }
 
+// voteFunc returns the vote rule of block number under config: stake weighted
+// votes past the Athena fork.
+func voteFunc(config *params.ChainConfig, number *big.Int) vm.VoteFunc {
+	if config.IsAthena(number) {
+		return newStakeVote(unbondingRelease(config, number))
+	}
+	return Vote
+}
+
+func newStakeVote(release uint64) vm.VoteFunc {
+	return func(db vm.StateDB, user common.Address, amount *big.Int, vote []types.Vote, delegateList *map[common.Address]types.Candidate, maxElectDelegate int64) error {
+		return StakeVote(db, user, amount, vote, delegateList, maxElectDelegate, release)
+	}
+}
+
+// unbondingRelease returns the block at which stake withdrawn in block number is
+// released, or zero if it is released immediately.
+func unbondingRelease(config *params.ChainConfig, number *big.Int) uint64 {
+	period := config.UnbondingPeriod(number)
+	if period == 0 {
+		return 0
+	}
+	return number.Uint64() + period
+}
+
+// StakeVote replaces Vote once voting is stake weighted: every added candidate
+// locks the amount the voter chose instead of one AOA, and the tx value has to
+// match the net change of the locked balance. Withdrawn stake is unbonding until
+// block release, unless release is zero. Any failure is reported as vm.ErrVote
+// so that the transaction is dropped instead of being tallied.
+func StakeVote(db vm.StateDB, user common.Address, amount *big.Int, vote []types.Vote, delegateList *map[common.Address]types.Candidate, maxElectDelegate int64, release uint64) error {
+	newVoteList, stakes, diff, err := ChangeStakeVoteList(db, user, vote, *delegateList)
+	if err != nil {
+		log.Debug("InVoteError", "err", err)
+		return vm.ErrVote
+	}
+	if int64(len(newVoteList)) > maxElectDelegate || new(big.Int).Abs(diff).Cmp(amount) != 0 {
+		return vm.ErrVote
+	}
+	if diff.Sign() > 0 && db.GetBalance(user).Cmp(diff) < 0 {
+		return vm.ErrVote
+	}
+
+	if diff.Sign() < 0 && release > 0 {
+		withdrawn := new(big.Int).Neg(diff)
+		db.SubLockBalance(user, withdrawn)
+		db.AddUnbonding(user, withdrawn, release)
+	} else {
+		db.SubBalance(user, diff)
+		db.AddLockBalance(user, diff)
+	}
+
+	db.SetVoteList(user, newVoteList)
+
+	for _, candidate := range sortedCandidates(stakes) {
+		db.SetVoteStake(user, candidate, stakes[candidate])
+	}
+	return nil
+}
+
+// UnregisterGas returns the gas to withdraw the votes Unregister withdraws at
+// once from delegate.
+func UnregisterGas(db vm.StateDB, delegate common.Address) uint64 {
+	voters := db.CandidateStakerCount(delegate)
+	if voters > params.MaxUnregisterVoters {
+		voters = params.MaxUnregisterVoters
+	}
+	return voters * params.UnregisterVoterGas
+}
+
+// Unregister withdraws up to params.MaxUnregisterVoters votes with a recorded
+// stake on delegate and unlocks the registration stake of delegate. Withdrawn
+// stake is unbonding until block release, unless release is zero. The recorded
+// votes left are withdrawn by ReleaseDepartedVotes in the next blocks, the ones
+// cast before stake weighting, which are not recorded, by ReleaseStaleVotes.
+func Unregister(db vm.StateDB, delegate common.Address, release uint64) {
+	withdrawVoters(db, delegate, params.MaxUnregisterVoters, release)
+	if db.CandidateStakerCount(delegate) > 0 {
+		db.QueueDeparted(delegate)
+	}
+	if stake := db.GetRegistrationStake(delegate); stake.Sign() > 0 {
+		db.SetRegistrationStake(delegate, new(big.Int))
+		unlockStake(db, delegate, stake, release)
+	}
+}
+
+// withdrawVoters withdraws the votes of at most max voters with a recorded
+// stake on delegate and returns how many it withdrew.
+func withdrawVoters(db vm.StateDB, delegate common.Address, max uint64, release uint64) uint64 {
+	voters := db.CandidateStakers(delegate, max)
+	for _, voter := range voters {
+		voteList := db.GetVoteList(voter)
+		if i, ok := sliceContains(delegate, voteList); ok {
+			db.SetVoteList(voter, append(append([]common.Address{}, voteList[:i]...), voteList[i+1:]...))
+		}
+		withdrawVote(db, voter, delegate, release)
+	}
+	return uint64(len(voters))
+}
+
+// ReleaseDepartedVotes withdraws up to params.MaxUnregisterVoters of the
+// recorded votes Unregister left on the delegates it queued. It runs after the
+// transactions of the block, before the engine finalizes it.
+func ReleaseDepartedVotes(config *params.ChainConfig, statedb *state.StateDB, header *types.Header) {
+	if !config.IsAthena(header.Number) {
+		return
+	}
+	release := unbondingRelease(config, header.Number)
+	for budget := params.MaxUnregisterVoters; budget > 0; {
+		delegate, ok := statedb.DepartedDelegate()
+		if !ok {
+			return
+		}
+		budget -= withdrawVoters(statedb, delegate, budget, release)
+		if statedb.CandidateStakerCount(delegate) == 0 {
+			statedb.PopDeparted()
+		}
+	}
+}
+
+// Slash punishes offender for signing two blocks at height number, which the
+// block being processed is at most one unbonding period past. SlashShare basis
+// points are taken from its registration stake, from every vote stake it
+// recorded and from the stake it withdrew after the double sign, which is still
+// unbonding. A vote keeps at least one AOA so that it stays in the vote list of
+// offender. ReporterShare basis points of the slashed amount are paid to
+// reporter, the rest is burnt. Every slashed vote is logged with SlashVoteTopic
+// and the slash with SlashTopic for CountTrxVote to tally. The caller
+// unregisters offender if it still is a delegate. It returns the slashed amount.
+func Slash(db vm.StateDB, config *params.ChainConfig, block *big.Int, offender, reporter common.Address, number uint64) *big.Int {
+	var (
+		share   = configUint64(config.SlashShare)
+		slashed = db.SlashUnbonding(offender, number+config.UnbondingPeriod(block), func(amount *big.Int) *big.Int {
+			return basisPoints(amount, share)
+		})
+	)
+	if stake := db.GetRegistrationStake(offender); stake.Sign() > 0 {
+		cut := basisPoints(stake, share)
+		db.SetRegistrationStake(offender, new(big.Int).Sub(stake, cut))
+		db.SubLockBalance(offender, cut)
+		slashed.Add(slashed, cut)
+	}
+	for _, candidate := range db.GetVoteList(offender) {
+		stake := db.GetVoteStake(offender, candidate)
+		if stake == 0 {
+			continue
+		}
+		cut := basisPoints(new(big.Int).SetUint64(stake), share).Uint64()
+		if cut >= stake {
+			cut = stake - 1
+		}
+		if cut == 0 {
+			continue
+		}
+		db.SetVoteStake(offender, candidate, stake-cut)
+		amount := new(big.Int).Mul(new(big.Int).SetUint64(cut), big.NewInt(params.Aoa))
+		db.SubLockBalance(offender, amount)
+		slashed.Add(slashed, amount)
+		db.AddLog(&types.Log{
+			Address:     state.EvidenceAddress,
+			Topics:      []common.Hash{SlashVoteTopic, offender.Hash(), candidate.Hash()},
+			Data:        common.BigToHash(new(big.Int).SetUint64(cut)).Bytes(),
+			BlockNumber: block.Uint64(),
+		})
+	}
+	db.AddBalance(reporter, basisPoints(slashed, configUint64(config.ReporterShare)))
+	db.AddLog(&types.Log{
+		Address:     state.EvidenceAddress,
+		Topics:      []common.Hash{SlashTopic, offender.Hash()},
+		Data:        append(common.BigToHash(new(big.Int).SetUint64(number)).Bytes(), common.BigToHash(slashed).Bytes()...),
+		BlockNumber: block.Uint64(),
+	})
+	return slashed
+}
+
+func basisPoints(amount *big.Int, bp uint64) *big.Int {
+	part := new(big.Int).Mul(amount, new(big.Int).SetUint64(bp))
+	return part.Div(part, big.NewInt(10000))
+}
+
+// ReleaseStaleVotes drops the votes of voter on candidates that are no longer
+// delegates and unlocks their stake, one AOA for a vote cast before stake
+// weighting. It returns the number of votes dropped.
+func ReleaseStaleVotes(db vm.StateDB, voter common.Address, delegateList map[common.Address]types.Candidate, release uint64) uint64 {
+	voteList := db.GetVoteList(voter)
+	kept := make([]common.Address, 0, len(voteList))
+	for _, candidate := range voteList {
+		if _, ok := delegateList[candidate]; ok {
+			kept = append(kept, candidate)
+			continue
+		}
+		withdrawVote(db, voter, candidate, release)
+	}
+	if len(kept) != len(voteList) {
+		db.SetVoteList(voter, kept)
+	}
+	return uint64(len(voteList) - len(kept))
+}
+
+// ReleaseVotesGas returns the gas to drop the stale votes of voters, as an
+// ActionReleaseVotes transaction does.
+func ReleaseVotesGas(db vm.StateDB, voters []common.Address, delegateList map[common.Address]types.Candidate) uint64 {
+	var stale uint64
+	for _, voter := range voters {
+		for _, candidate := range db.GetVoteList(voter) {
+			if _, ok := delegateList[candidate]; !ok {
+				stale++
+			}
+		}
+	}
+	return stale * params.UnregisterVoterGas
+}
+
+// withdrawVote removes the stake record of voter on candidate and unlocks the
+// stake. The vote list of voter is left to the caller.
+func withdrawVote(db vm.StateDB, voter, candidate common.Address, release uint64) {
+	stake := db.GetVoteStake(voter, candidate)
+	if stake == 0 {
+		stake = 1
+	} else {
+		db.SetVoteStake(voter, candidate, 0)
+	}
+	unlockStake(db, voter, new(big.Int).Mul(new(big.Int).SetUint64(stake), big.NewInt(params.Aoa)), release)
+}
+
+func unlockStake(db vm.StateDB, addr common.Address, amount *big.Int, release uint64) {
+	db.SubLockBalance(addr, amount)
+	if release > 0 {
+		db.AddUnbonding(addr, amount, release)
+	} else {
+		db.AddBalance(addr, amount)
+	}
+}
+
+// ChangeStakeVoteList applies a stake weighted vote list to the current votes of
+// user. It returns the new vote list, the new stake in whole AOA of every
+// touched candidate and the change of the locked balance in wei. Removing a
+// candidate has to name its whole stake; a vote without an amount names one
+// AOA, the stake of every vote cast before stake weighting.
+func ChangeStakeVoteList(db vm.StateDB, user common.Address, curVoteList []types.Vote, delegateList map[common.Address]types.Candidate) ([]common.Address, map[common.Address]uint64, *big.Int, error) {
+	var (
+		voteChangeList = append([]common.Address{}, db.GetVoteList(user)...)
+		stakes         = make(map[common.Address]uint64)
+		diff           = new(big.Int)
+	)
+	for _, vote := range curVoteList {
+		if vote.Candidate == nil {
+			return nil, nil, nil, errors.New("vote candidate is nil")
+		}
+		candidate := *vote.Candidate
+		units, err := vote.StakeUnits()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		switch vote.Operation {
+		case 0:
+			if _, contain := sliceContains(candidate, voteChangeList); contain {
+				return nil, nil, nil, errors.New("You have already vote candidate " + candidate.Hex())
+			}
+			if _, ok := delegateList[candidate]; !ok {
+				return nil, nil, nil, ErrVoteList
+			}
+			voteChangeList = append(voteChangeList, candidate)
+			stakes[candidate] = units
+			diff.Add(diff, new(big.Int).SetUint64(units))
+		case 1:
+			j, contain := sliceContains(candidate, voteChangeList)
+			if !contain {
+				return nil, nil, nil, errors.New("You haven't vote candidate " + candidate.Hex() + " yet")
+			}
+			stake, ok := stakes[candidate]
+			if !ok {
+				stake = db.GetVoteStake(user, candidate)
+			}
+			if stake == 0 {
+				stake = 1
+			}
+			if units != stake {
+				return nil, nil, nil, fmt.Errorf("vote on candidate %s stakes %d AOA, not %d", candidate.Hex(), stake, units)
+			}
+			voteChangeList = append(voteChangeList[:j], voteChangeList[j+1:]...)
+			stakes[candidate] = 0
+			diff.Sub(diff, new(big.Int).SetUint64(units))
+		default:
+			return nil, nil, nil, errors.New("Vote candidate " + candidate.Hex() + " Operation error!")
+		}
+	}
+	return voteChangeList, stakes, diff.Mul(diff, big.NewInt(params.Aoa)), nil
+}
+
+func sortedCandidates(stakes map[common.Address]uint64) []common.Address {
+	candidates := make([]common.Address, 0, len(stakes))
+	for candidate := range stakes {
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return bytes.Compare(candidates[i][:], candidates[j][:]) < 0 })
+	return candidates
+}
+
 func changeVoteList(prevVoteList []common.Address, curVoteList []types.Vote, delegateList map[common.Address]types.Candidate) ([]common.Address, *big.Int, error) {
 	var (
 		voteChangeList = prevVoteList
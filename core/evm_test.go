@@ -0,0 +1,79 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+func TestShareBlockRewardSupply(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(mem))
+	config := &params.ChainConfig{ByzantiumBlock: big.NewInt(0), ByzantiumBlockReward: big.NewInt(1e18), AthenaBlock: big.NewInt(0)}
+	coinbase, voter := common.Address{1}, common.Address{2}
+	header := &types.Header{Number: big.NewInt(10), Coinbase: coinbase}
+
+	statedb.SetCommission(coinbase, 2000)
+	statedb.SetVoteStake(voter, coinbase, 10)
+	supply := func() *big.Int {
+		return new(big.Int).Add(statedb.GetBalance(coinbase), statedb.GetBalance(state.RewardAddress))
+	}
+	before := supply()
+
+	// the engine credits the whole reward to the coinbase when it finalizes
+	reward := config.BlockReward(header.Number)
+	statedb.AddBalance(coinbase, reward)
+	if !ShareBlockReward(config, statedb, header) {
+		t.Fatalf("block reward not shared")
+	}
+	if grown := new(big.Int).Sub(supply(), before); grown.Cmp(reward) != 0 {
+		t.Errorf("supply growth mismatch: have %v, want %v", grown, reward)
+	}
+	if have, want := statedb.GetBalance(coinbase), big.NewInt(2e17); have.Cmp(want) != 0 {
+		t.Errorf("coinbase balance mismatch: have %v, want %v", have, want)
+	}
+	if have, want := statedb.GetPendingRewards(voter, []common.Address{coinbase}), big.NewInt(8e17); have.Cmp(want) != 0 {
+		t.Errorf("voter reward mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestReleaseDepartedVotes(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(mem))
+	config := &params.ChainConfig{AthenaBlock: big.NewInt(0)}
+	delegate := common.Address{1}
+	voters := params.MaxUnregisterVoters + 10
+	for i := uint64(0); i < voters; i++ {
+		voter := common.BigToAddress(new(big.Int).SetUint64(1000 + i))
+		statedb.SetVoteList(voter, []common.Address{delegate})
+		statedb.SetVoteStake(voter, delegate, 1)
+		statedb.AddLockBalance(voter, big.NewInt(params.Aoa))
+	}
+
+	Unregister(statedb, delegate, 0)
+	if left := statedb.CandidateStakerCount(delegate); left != 10 {
+		t.Fatalf("voters left after unregistering: have %d, want 10", left)
+	}
+	if departed, ok := statedb.DepartedDelegate(); !ok || departed != delegate {
+		t.Fatalf("departed delegate mismatch: have %x (%v), want %x", departed, ok, delegate)
+	}
+	ReleaseDepartedVotes(config, statedb, &types.Header{Number: big.NewInt(1)})
+	if left := statedb.CandidateStakerCount(delegate); left != 0 {
+		t.Errorf("voters left after release: have %d, want 0", left)
+	}
+	if departed, ok := statedb.DepartedDelegate(); ok {
+		t.Errorf("departed delegate left: have %x", departed)
+	}
+	voter := common.BigToAddress(big.NewInt(1000))
+	if list := statedb.GetVoteList(voter); len(list) != 0 {
+		t.Errorf("vote list left: have %x", list)
+	}
+	if have, want := statedb.GetBalance(voter), big.NewInt(params.Aoa); have.Cmp(want) != 0 {
+		t.Errorf("voter balance mismatch: have %v, want %v", have, want)
+	}
+}
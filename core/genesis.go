@@ -17,7 +17,6 @@ import (
 	"github.com/Aurorachain/go-Aurora/log"
 	"github.com/Aurorachain/go-Aurora/params"
 	"github.com/Aurorachain/go-Aurora/rlp"
-	"github.com/Aurorachain/go-Aurora/util"
 	"math/big"
 	"strings"
 )
@@ -192,13 +191,10 @@ func (g *Genesis) ToBlock() (*types.Block, *state.StateDB, *delegatestate.Delega
 		obj.AddVote(big.NewInt(int64(agent.Vote)))
 	}
 	delegateRoot := delegatedb.IntermediateRoot(false)
-	topDelegates := delegatedb.GetDelegates()
 	config := g.Config
-	MaxElectDelegate := config.MaxElectDelegate.Int64()
-	if len(topDelegates) > int(MaxElectDelegate) {
-		topDelegates = topDelegates[:int(MaxElectDelegate)]
-	}
-	shuffleNewRound := util.ShuffleNewRound(int64(g.Timestamp), int(MaxElectDelegate), topDelegates, config.BlockInterval.Int64())
+	number := new(big.Int).SetUint64(g.Number)
+	topDelegates := ElectDelegates(config, delegatedb, number, delegatedb.GetDelegates())
+	shuffleNewRound := ShuffleRound(config, number, statedb.SeedMix(), int64(g.Timestamp), topDelegates)
 	shuffleList := types.ShuffleList{ShuffleDels: shuffleNewRound}
 	rlpShufflehash := rlpHash(shuffleList)
 
@@ -245,6 +241,9 @@ func (g *Genesis) Commit(db aoadb.Database) (*types.Block, error) {
 	if err := WriteBlockReceipts(db, block.Hash(), block.NumberU64(), nil); err != nil {
 		return nil, err
 	}
+	if err := WriteAssetChanges(db, block.Hash(), block.NumberU64(), statedb.AssetChanges()); err != nil {
+		return nil, err
+	}
 	if err := WriteCanonicalHash(db, block.Hash(), block.NumberU64()); err != nil {
 		return nil, err
 	}
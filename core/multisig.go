@@ -0,0 +1,25 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/core/vm"
+)
+
+// checkMultisig checks an ActionCreateMultisig transaction to account with the
+// payload data and returns the multisig account it creates.
+func checkMultisig(statedb vm.StateDB, account *common.Address, data []byte) (*types.Multisig, error) {
+	m, err := types.BytesToMultisig(data)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || *account != m.Address() {
+		return nil, errors.New("multisig account must be sent to its address")
+	}
+	if threshold, _ := statedb.GetMultisig(*account); threshold != 0 {
+		return nil, ErrMultisigExists
+	}
+	return m, nil
+}
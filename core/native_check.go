@@ -0,0 +1,177 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/core/vm"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// The checks below validate a native action against the state it is applied
+// to. The state transition runs them before applying the action and the tx
+// pool before accepting it, so both reject the same transactions.
+
+// checkBatchTransfer checks an ActionBatchTransfer transaction and returns its
+// legs.
+func checkBatchTransfer(value *big.Int, data []byte) ([]types.BatchTransfer, error) {
+	if value.Sign() != 0 {
+		return nil, ErrValueNotAllowed
+	}
+	return types.BytesToBatchTransfers(data)
+}
+
+// checkUnregister checks that delegate, jailed until round jailedUntil, may
+// leave the delegates. A cancelled delegate would lose its jail status with its
+// delegate state.
+func checkUnregister(delegates map[common.Address]types.Candidate, delegate common.Address, value *big.Int, jailedUntil uint64) error {
+	if value.Sign() != 0 {
+		return ErrValueNotAllowed
+	}
+	if _, ok := delegates[delegate]; !ok {
+		return ErrUnregister
+	}
+	if jailedUntil != 0 {
+		return ErrJailed
+	}
+	return nil
+}
+
+// checkSetCommission checks an ActionSetCommission transaction of delegate and
+// returns the commission it sets.
+func checkSetCommission(delegates map[common.Address]types.Candidate, delegate common.Address, value *big.Int, data []byte) (uint64, error) {
+	if value.Sign() != 0 {
+		return 0, ErrValueNotAllowed
+	}
+	commission, err := types.BytesToCommission(data)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := delegates[delegate]; !ok {
+		return 0, ErrUnregister
+	}
+	return commission, nil
+}
+
+// checkClaimRewards checks that voter has rewards to claim and returns the vote
+// list they are claimed on.
+func checkClaimRewards(statedb vm.StateDB, voter common.Address, value *big.Int) ([]common.Address, error) {
+	if value.Sign() != 0 {
+		return nil, ErrValueNotAllowed
+	}
+	voteList := statedb.GetVoteList(voter)
+	if statedb.GetPendingRewards(voter, voteList).Sign() == 0 {
+		return nil, ErrNoRewards
+	}
+	return voteList, nil
+}
+
+// checkUnjail checks that the jail of a delegate jailed until round jailedUntil
+// is over.
+func checkUnjail(statedb vm.StateDB, value *big.Int, jailedUntil uint64) error {
+	if value.Sign() != 0 {
+		return ErrValueNotAllowed
+	}
+	if jailedUntil == 0 {
+		return ErrNotJailed
+	}
+	if statedb.CurrentRound() < jailedUntil {
+		return fmt.Errorf("%v until round %d", ErrJailed, jailedUntil)
+	}
+	return nil
+}
+
+// checkDoubleSignEvidence checks an ActionDoubleSignEvidence transaction of
+// reporter for block number, see checkEvidence, and returns the evidence and
+// its offender. A double sign is only punished once per offender and height.
+func checkDoubleSignEvidence(config *params.ChainConfig, statedb vm.StateDB, reporter common.Address, value *big.Int, data []byte, number *big.Int, hashOf func(uint64) common.Hash) (*types.DoubleSignEvidence, common.Address, error) {
+	if value.Sign() != 0 {
+		return nil, common.Address{}, ErrValueNotAllowed
+	}
+	evidence, offender, err := checkEvidence(config, statedb, data, number, hashOf)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	if offender == reporter {
+		return nil, common.Address{}, errors.New("delegate can not report itself")
+	}
+	if statedb.HasEvidence(offender, evidence.Number()) {
+		return nil, common.Address{}, ErrDuplicateEvidence
+	}
+	return evidence, offender, nil
+}
+
+// checkSetProducer checks an ActionSetProducer transaction of delegate in block
+// number, see checkProducer, and returns its proof.
+func checkSetProducer(statedb vm.StateDB, delegates map[common.Address]types.Candidate, delegate common.Address, value *big.Int, data []byte, chainId *big.Int, number uint64) (*types.ProducerProof, error) {
+	if value.Sign() != 0 {
+		return nil, ErrValueNotAllowed
+	}
+	proof, err := types.BytesToProducer(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProducer(statedb, delegates, delegate, proof, chainId, number); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// checkSetProxy checks an ActionSetProxy transaction of nominator to proxy, see
+// checkProxy, and returns the stake it adds in whole AOA.
+func checkSetProxy(statedb vm.StateDB, nominator common.Address, proxy *common.Address, value *big.Int) (uint64, error) {
+	units, err := types.ProxyStakeUnits(value)
+	if err != nil {
+		return 0, err
+	}
+	if proxy == nil {
+		return 0, errors.New("proxy is nil")
+	}
+	if err := checkProxy(statedb, nominator, *proxy); err != nil {
+		return 0, err
+	}
+	return units, nil
+}
+
+// checkRevokeProxy checks that nominator has a proxy and returns it with the
+// stake nominator proxied in whole AOA.
+func checkRevokeProxy(statedb vm.StateDB, nominator common.Address, value *big.Int) (common.Address, uint64, error) {
+	if value.Sign() != 0 {
+		return common.Address{}, 0, ErrValueNotAllowed
+	}
+	proxy, stake := statedb.GetProxy(nominator)
+	if proxy == (common.Address{}) {
+		return common.Address{}, 0, ErrNoProxy
+	}
+	return proxy, stake, nil
+}
+
+// checkCreateMultisig checks an ActionCreateMultisig transaction, see
+// checkMultisig, and returns the multisig account it creates.
+func checkCreateMultisig(statedb vm.StateDB, account *common.Address, value *big.Int, data []byte) (*types.Multisig, error) {
+	if value.Sign() != 0 {
+		return nil, ErrValueNotAllowed
+	}
+	return checkMultisig(statedb, account, data)
+}
+
+// checkReleaseVotes checks that the voters in the payload of an
+// ActionReleaseVotes transaction have stale votes and returns them with the gas
+// of releasing them.
+func checkReleaseVotes(statedb vm.StateDB, delegates map[common.Address]types.Candidate, value *big.Int, data []byte) ([]common.Address, uint64, error) {
+	if value.Sign() != 0 {
+		return nil, 0, ErrValueNotAllowed
+	}
+	voters, err := types.BytesToVoters(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	gas := ReleaseVotesGas(statedb, voters, delegates)
+	if gas == 0 {
+		return nil, 0, ErrNoStaleVotes
+	}
+	return voters, gas, nil
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/core/vm"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// ProxyVoteTopic is the first topic of the logs of state.ProxyAddress recording
+// a change of the stake counted for a candidate through a proxy. The other
+// topics are the proxy and the candidate, the data holds the previous and the
+// new share in whole AOA as 32 byte words.
+var ProxyVoteTopic = crypto.Keccak256Hash([]byte("ProxyVote(address,address,uint256,uint256)"))
+
+// checkProxy checks that nominator may nominate proxy. Proxies do not chain: an
+// account with a proxy can not be nominated, and a proxy can not nominate one.
+func checkProxy(statedb vm.StateDB, nominator, proxy common.Address) error {
+	if proxy == nominator {
+		return errors.New("account can not be its own proxy")
+	}
+	if current, _ := statedb.GetProxy(nominator); current != (common.Address{}) && current != proxy {
+		return fmt.Errorf("%v: %s", ErrProxySet, current.Hex())
+	}
+	if next, _ := statedb.GetProxy(proxy); next != (common.Address{}) {
+		return ErrProxyChain
+	}
+	if statedb.ProxiedStake(nominator) > 0 {
+		return ErrProxyChain
+	}
+	return nil
+}
+
+// ProxySyncGas returns the gas to recount the stake proxied to proxy once its
+// vote list changed by at most changes candidates.
+func ProxySyncGas(statedb vm.StateDB, proxy common.Address, changes int) uint64 {
+	return uint64(len(statedb.ProxyShares(proxy))+len(statedb.GetVoteList(proxy))+changes) * params.ProxyShareGas
+}
+
+// ProxyRecountGas returns the gas to move the shares the proxies have on
+// delegate to the rest of their vote lists once delegate leaves the delegates.
+func ProxyRecountGas(statedb vm.StateDB, delegate common.Address) uint64 {
+	var gas uint64
+	for _, proxy := range statedb.CandidateProxies(delegate) {
+		gas += ProxySyncGas(statedb, proxy, 0)
+	}
+	return gas
+}
+
+// delegateCounts returns whether the stake of a proxy is counted for a
+// candidate: it has to be one of delegates other than the excluded ones.
+func delegateCounts(delegates map[common.Address]types.Candidate, excluded ...common.Address) func(common.Address) bool {
+	return func(candidate common.Address) bool {
+		for _, e := range excluded {
+			if candidate == e {
+				return false
+			}
+		}
+		_, ok := delegates[candidate]
+		return ok
+	}
+}
+
+// proxyShares splits the stake proxied to proxy evenly over the candidates of
+// its vote list that count. The remainder goes one AOA each to the candidates
+// first in address order.
+func proxyShares(statedb vm.StateDB, proxy common.Address, counts func(common.Address) bool) map[common.Address]uint64 {
+	shares := make(map[common.Address]uint64)
+	var candidates []common.Address
+	for _, candidate := range statedb.GetVoteList(proxy) {
+		if counts(candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	stake := statedb.ProxiedStake(proxy)
+	if len(candidates) == 0 || stake == 0 {
+		return shares
+	}
+	sort.Slice(candidates, func(i, j int) bool { return bytes.Compare(candidates[i][:], candidates[j][:]) < 0 })
+	n := uint64(len(candidates))
+	for i, candidate := range candidates {
+		share := stake / n
+		if uint64(i) < stake%n {
+			share++
+		}
+		if share > 0 {
+			shares[candidate] = share
+		}
+	}
+	return shares
+}
+
+// SyncProxy recounts the stake proxied to proxy over its vote list, counting
+// the candidates for which counts returns true. Every share that changes is
+// recorded and logged with ProxyVoteTopic for CountTrxVote to tally.
+func SyncProxy(statedb vm.StateDB, proxy common.Address, counts func(common.Address) bool, number uint64) {
+	shares := proxyShares(statedb, proxy, counts)
+	touched := make(map[common.Address]uint64, len(shares))
+	for candidate, share := range shares {
+		touched[candidate] = share
+	}
+	for _, candidate := range statedb.ProxyShares(proxy) {
+		touched[candidate] = shares[candidate]
+	}
+	for _, candidate := range sortedCandidates(touched) {
+		prev, share := statedb.GetProxyShare(proxy, candidate), touched[candidate]
+		if prev == share {
+			continue
+		}
+		statedb.SetProxyShare(proxy, candidate, share)
+		data := append(common.BigToHash(new(big.Int).SetUint64(prev)).Bytes(), common.BigToHash(new(big.Int).SetUint64(share)).Bytes()...)
+		statedb.AddLog(&types.Log{
+			Address:     state.ProxyAddress,
+			Topics:      []common.Hash{ProxyVoteTopic, proxy.Hash(), candidate.Hash()},
+			Data:        data,
+			BlockNumber: number,
+		})
+	}
+}
+
+// proxyVotes sums the changes of the stake counted for each candidate through
+// proxies that the transaction with the given hash logged.
+func proxyVotes(statedb *state.StateDB, hash common.Hash) map[common.Address]int64 {
+	votes := make(map[common.Address]int64)
+	for _, l := range statedb.GetLogs(hash) {
+		if l.Address != state.ProxyAddress || len(l.Topics) != 3 || l.Topics[0] != ProxyVoteTopic || len(l.Data) != 64 {
+			continue
+		}
+		candidate := common.BytesToAddress(l.Topics[2].Bytes())
+		prev := new(big.Int).SetBytes(l.Data[:32]).Int64()
+		share := new(big.Int).SetBytes(l.Data[32:]).Int64()
+		votes[candidate] += share - prev
+	}
+	return votes
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/rlp"
+	"github.com/Aurorachain/go-Aurora/trie"
+)
+
+// AssetChange is the balance of one asset held by one account after the
+// changes of a block, zero if the account no longer holds it.
+type AssetChange struct {
+	Holder  common.Address
+	Asset   common.Address
+	Balance *big.Int
+}
+
+type assetHolding struct {
+	holder, asset common.Address
+}
+
+// markAssetChanged records that the balance of asset held by holder may have
+// changed. Reverted changes stay marked, their holding is reported with the
+// balance it has anyway.
+func (self *StateDB) markAssetChanged(holder, asset common.Address) {
+	self.assetsChanged[assetHolding{holder, asset}] = struct{}{}
+}
+
+// AssetChanges returns the current balance of every asset holding modified
+// since the state was opened, ordered by holder and asset.
+func (self *StateDB) AssetChanges() []AssetChange {
+	changes := make([]AssetChange, 0, len(self.assetsChanged))
+	for h := range self.assetsChanged {
+		changes = append(changes, AssetChange{
+			Holder:  h.holder,
+			Asset:   h.asset,
+			Balance: new(big.Int).Set(self.GetAssetBalance(h.holder, h.asset)),
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if c := bytes.Compare(changes[i].Holder[:], changes[j].Holder[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(changes[i].Asset[:], changes[j].Asset[:]) < 0
+	})
+	return changes
+}
+
+// AssetHoldings returns every positive asset balance of the committed state,
+// ordered by holder and asset. It resolves the holders from the preimages of
+// the account trie and fails if one is missing, as on a fast-synced node.
+func (self *StateDB) AssetHoldings() ([]AssetChange, error) {
+	var holdings []AssetChange
+	it := trie.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return nil, err
+		}
+		if data.AssetList == nil {
+			continue
+		}
+		assets := data.AssetList.GetAssets()
+		if len(assets) == 0 {
+			continue
+		}
+		addr := self.trie.GetKey(it.Key)
+		if addr == nil {
+			return nil, fmt.Errorf("missing preimage of account %x", it.Key)
+		}
+		for _, asset := range assets {
+			if asset.Balance != nil && asset.Balance.Sign() > 0 {
+				holdings = append(holdings, AssetChange{Holder: common.BytesToAddress(addr), Asset: asset.ID, Balance: new(big.Int).Set(asset.Balance)})
+			}
+		}
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		if c := bytes.Compare(holdings[i].Holder[:], holdings[j].Holder[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(holdings[i].Asset[:], holdings[j].Asset[:]) < 0
+	})
+	return holdings, nil
+}
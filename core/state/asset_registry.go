@@ -0,0 +1,151 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/rlp"
+	"github.com/Aurorachain/go-Aurora/trie"
+)
+
+// AssetRegistryAddress is the account whose storage maps asset symbols to the
+// assets published with them. Layout:
+//
+//	keccak256("symbol", upper(symbol)) -> asset address
+//	keccak256("count")                 -> number of registered assets
+//	keccak256("index", i)              -> address of the i-th registered asset
+var AssetRegistryAddress = common.StringToAddress("Asset Registry")
+
+var assetCountKey = crypto.Keccak256Hash([]byte("count"))
+
+func assetSymbolKey(symbol string) common.Hash {
+	return crypto.Keccak256Hash([]byte("symbol"), []byte(strings.ToUpper(symbol)))
+}
+
+func assetIndexKey(index uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("index"), new(big.Int).SetUint64(index).Bytes())
+}
+
+// GetAssetBySymbol returns the asset registered with the given symbol. The lookup
+// is case-insensitive.
+func (self *StateDB) GetAssetBySymbol(symbol string) (common.Address, bool) {
+	value := self.GetState(AssetRegistryAddress, assetSymbolKey(symbol))
+	if value == (common.Hash{}) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(value.Bytes()), true
+}
+
+// AssetCount returns the number of assets in the symbol registry.
+func (self *StateDB) AssetCount() uint64 {
+	return self.GetState(AssetRegistryAddress, assetCountKey).Big().Uint64()
+}
+
+// ListAssets returns at most limit registered assets in registration order,
+// starting from index start.
+func (self *StateDB) ListAssets(start, limit uint64) []common.Address {
+	count := self.AssetCount()
+	if start >= count {
+		return []common.Address{}
+	}
+	if limit > count-start {
+		limit = count - start
+	}
+	assets := make([]common.Address, 0, limit)
+	for i := start; i < start+limit; i++ {
+		assets = append(assets, common.BytesToAddress(self.GetState(AssetRegistryAddress, assetIndexKey(i)).Bytes()))
+	}
+	return assets
+}
+
+func (self *StateDB) validateAssetSymbol(symbol string) error {
+	if asset, exist := self.GetAssetBySymbol(symbol); exist {
+		return fmt.Errorf("symbol %s is already used by asset %s", symbol, asset.String())
+	}
+	return nil
+}
+
+func (self *StateDB) registerAsset(symbol string, asset common.Address) {
+	count := self.AssetCount()
+	if _, exist := self.GetAssetBySymbol(symbol); !exist {
+		self.setSystemState(AssetRegistryAddress, assetSymbolKey(symbol), asset.Hash())
+	}
+	self.setSystemState(AssetRegistryAddress, assetIndexKey(count), asset.Hash())
+	self.setSystemState(AssetRegistryAddress, assetCountKey, common.BigToHash(new(big.Int).SetUint64(count+1)))
+}
+
+// BackfillAssetRegistry registers the assets published before symbols were
+// registered, in the given order. Symbols were not unique then: of the assets
+// sharing one the first keeps it, the others are only listed.
+func (self *StateDB) BackfillAssetRegistry(assets []common.Address) error {
+	for _, asset := range assets {
+		info, err := self.GetAssetInfo(asset)
+		if err != nil {
+			return err
+		}
+		self.registerAsset(info.Symbol, asset)
+	}
+	return nil
+}
+
+// LegacyAssets returns every asset of the state in address order, as the
+// HermesAssets table of the chain config has to list them. It iterates the whole
+// account trie and is meant for offline use, not for block processing.
+//
+// The trie is keyed by address hashes and fast-synced nodes have no preimages,
+// so the address of an asset is recovered from its issuer, which created it with
+// one of its past nonces.
+func (self *StateDB) LegacyAssets() ([]common.Address, error) {
+	issued := make(map[common.Address][]common.Hash)
+	it := trie.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return nil, err
+		}
+		if len(data.AssetHash) == 0 || bytes.Equal(data.AssetHash, emptyCodeHash) {
+			continue
+		}
+		addrHash := common.BytesToHash(it.Key)
+		enc, err := self.db.AssetData(addrHash, common.BytesToHash(data.AssetHash))
+		if err != nil {
+			return nil, err
+		}
+		var info types.AssetInfo
+		if err := rlp.DecodeBytes(enc, &info); err != nil {
+			return nil, err
+		}
+		if info.Issuer == nil {
+			return nil, fmt.Errorf("asset account %x has no issuer", it.Key)
+		}
+		issued[*info.Issuer] = append(issued[*info.Issuer], addrHash)
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	var assets []common.Address
+	for issuer, hashes := range issued {
+		wanted := make(map[common.Hash]bool, len(hashes))
+		for _, hash := range hashes {
+			wanted[hash] = true
+		}
+		for nonce, end := uint64(0), self.GetNonce(issuer); nonce < end && len(wanted) > 0; nonce++ {
+			asset := crypto.CreateAddress(issuer, nonce)
+			if hash := crypto.Keccak256Hash(asset[:]); wanted[hash] {
+				delete(wanted, hash)
+				assets = append(assets, asset)
+			}
+		}
+		for hash := range wanted {
+			return nil, fmt.Errorf("can not recover the address of asset account %x issued by %s", hash, issuer.String())
+		}
+	}
+	sort.Slice(assets, func(i, j int) bool { return bytes.Compare(assets[i][:], assets[j][:]) < 0 })
+	return assets, nil
+}
@@ -0,0 +1,28 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// EvidenceAddress is the account whose storage keeps the double signs that were
+// already punished, one per offender and height. Layout:
+//
+//	keccak256("evidence", offender, number) -> block number the evidence was accepted in
+var EvidenceAddress = common.StringToAddress("Evidence")
+
+func evidenceKey(offender common.Address, number uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("evidence"), offender.Bytes(), new(big.Int).SetUint64(number).Bytes())
+}
+
+// HasEvidence reports whether offender was punished for signing two blocks at
+// height number already, with whichever pair of headers.
+func (self *StateDB) HasEvidence(offender common.Address, number uint64) bool {
+	return self.GetState(EvidenceAddress, evidenceKey(offender, number)) != (common.Hash{})
+}
+
+func (self *StateDB) AddEvidence(offender common.Address, number uint64, block uint64) {
+	self.setSystemState(EvidenceAddress, evidenceKey(offender, number), common.BigToHash(new(big.Int).SetUint64(block)))
+}
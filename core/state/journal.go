@@ -75,6 +75,11 @@ type (
 		asset      types.Asset
 		preOpIsAdd bool
 	}
+
+	assetDataChange struct {
+		account            *common.Address
+		prevhash, prevdata []byte
+	}
 )
 
 func (ch createObjectChange) undo(s *StateDB) {
@@ -150,3 +155,7 @@ func (ch addPreimageChange) undo(s *StateDB) {
 func (ch assetBalanceChange) undo(s *StateDB) {
 	s.getStateObject(*ch.account).revertAssetBalance(ch.asset, ch.preOpIsAdd)
 }
+
+func (ch assetDataChange) undo(s *StateDB) {
+	s.getStateObject(*ch.account).setAssetData(ch.prevhash, ch.prevdata)
+}
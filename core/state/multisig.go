@@ -0,0 +1,60 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// MultisigAddress is the account whose storage keeps the multisig accounts
+// created on chain. Layout:
+//
+//	keccak256("threshold", account)   -> signatures a transaction of account needs
+//	keccak256("owners", account)      -> number of owners of account
+//	keccak256("owner", account, i)    -> i-th owner of account
+//
+// A multisig account is never changed once created, its address is derived
+// from its threshold and owners.
+var MultisigAddress = common.StringToAddress("Multisig")
+
+func multisigThresholdKey(account common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("threshold"), account.Bytes())
+}
+
+func multisigOwnersKey(account common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("owners"), account.Bytes())
+}
+
+func multisigOwnerKey(account common.Address, i uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("owner"), account.Bytes(), new(big.Int).SetUint64(i).Bytes())
+}
+
+func (self *StateDB) setMultisigState(key common.Hash, value common.Hash) {
+	self.setSystemState(MultisigAddress, key, value)
+}
+
+// SetMultisig records account as a multisig account that needs threshold
+// signatures of owners.
+func (self *StateDB) SetMultisig(account common.Address, threshold uint64, owners []common.Address) {
+	self.setMultisigState(multisigThresholdKey(account), common.BigToHash(new(big.Int).SetUint64(threshold)))
+	self.setMultisigState(multisigOwnersKey(account), common.BigToHash(new(big.Int).SetUint64(uint64(len(owners)))))
+	for i, owner := range owners {
+		self.setMultisigState(multisigOwnerKey(account, uint64(i)), owner.Hash())
+	}
+}
+
+// GetMultisig returns the threshold and owners of the multisig account, or a
+// zero threshold if account was not created as one.
+func (self *StateDB) GetMultisig(account common.Address) (uint64, []common.Address) {
+	threshold := self.GetState(MultisigAddress, multisigThresholdKey(account)).Big().Uint64()
+	if threshold == 0 {
+		return 0, nil
+	}
+	count := self.GetState(MultisigAddress, multisigOwnersKey(account)).Big().Uint64()
+	owners := make([]common.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		owners = append(owners, common.BytesToAddress(self.GetState(MultisigAddress, multisigOwnerKey(account, i)).Bytes()))
+	}
+	return threshold, owners
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// ProducerAddress is the account whose storage keeps the keys delegates sign
+// their blocks with. Layout:
+//
+//	keccak256("producers", delegate)   -> number of keys delegate bound
+//	keccak256("producer", delegate, i) -> key of binding i
+//	keccak256("rotated", delegate, i)  -> block number binding i was made in
+//	keccak256("owner", key)            -> delegate key is currently bound to
+//
+// A binding signs the blocks after the one it was made in. The bindings are
+// kept so that evidence of an old double sign can be checked against the key
+// of its height. A delegate without a binding signs with its account key.
+var ProducerAddress = common.StringToAddress("Producer")
+
+func producerCountKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("producers"), delegate.Bytes())
+}
+
+func producerKey(delegate common.Address, i uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("producer"), delegate.Bytes(), new(big.Int).SetUint64(i).Bytes())
+}
+
+func producerRotatedKey(delegate common.Address, i uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("rotated"), delegate.Bytes(), new(big.Int).SetUint64(i).Bytes())
+}
+
+func producerOwnerKey(key common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("owner"), key.Bytes())
+}
+
+func (self *StateDB) setProducerState(key common.Hash, value common.Hash) {
+	self.setSystemState(ProducerAddress, key, value)
+}
+
+func (self *StateDB) producerCount(delegate common.Address) uint64 {
+	return self.GetState(ProducerAddress, producerCountKey(delegate)).Big().Uint64()
+}
+
+func (self *StateDB) producerBinding(delegate common.Address, i uint64) (common.Address, uint64) {
+	key := common.BytesToAddress(self.GetState(ProducerAddress, producerKey(delegate, i)).Bytes())
+	rotated := self.GetState(ProducerAddress, producerRotatedKey(delegate, i)).Big().Uint64()
+	return key, rotated
+}
+
+// BindProducer makes key the block signing key of delegate from the block after
+// number on.
+func (self *StateDB) BindProducer(delegate, key common.Address, number uint64) {
+	n := self.producerCount(delegate)
+	if n > 0 {
+		prev, _ := self.producerBinding(delegate, n-1)
+		self.setProducerState(producerOwnerKey(prev), common.Hash{})
+	}
+	self.setProducerState(producerKey(delegate, n), key.Hash())
+	self.setProducerState(producerRotatedKey(delegate, n), common.BigToHash(new(big.Int).SetUint64(number)))
+	self.setProducerState(producerCountKey(delegate), common.BigToHash(new(big.Int).SetUint64(n+1)))
+	self.setProducerState(producerOwnerKey(key), delegate.Hash())
+}
+
+// GetProducer returns the key delegate bound last and the block number it was
+// bound in, or the zero address if delegate never bound one.
+func (self *StateDB) GetProducer(delegate common.Address) (common.Address, uint64) {
+	n := self.producerCount(delegate)
+	if n == 0 {
+		return common.Address{}, 0
+	}
+	return self.producerBinding(delegate, n-1)
+}
+
+// ProducerAt returns the key delegate signs block number with.
+func (self *StateDB) ProducerAt(delegate common.Address, number uint64) common.Address {
+	for i := self.producerCount(delegate); i > 0; i-- {
+		if key, rotated := self.producerBinding(delegate, i-1); rotated < number {
+			return key
+		}
+	}
+	return delegate
+}
+
+// ProducerOwner returns the delegate key is currently bound to, or the zero
+// address.
+func (self *StateDB) ProducerOwner(key common.Address) common.Address {
+	return common.BytesToAddress(self.GetState(ProducerAddress, producerOwnerKey(key)).Bytes())
+}
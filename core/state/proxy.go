@@ -0,0 +1,186 @@
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// ProxyAddress is the account whose storage keeps the proxies voters nominate
+// and the part of the proxied stake counted for each candidate. Layout:
+//
+//	keccak256("proxy", nominator)             -> proxy of nominator
+//	keccak256("proxied", nominator)           -> whole AOA nominator locked for its proxy
+//	keccak256("proxiedStake", proxy)          -> sum of the stakes of the nominators of proxy
+//	keccak256("share", proxy, candidate)      -> whole AOA of the proxied stake counted for candidate
+//	keccak256("shared", candidate)            -> sum of the shares of every proxy on candidate
+//
+// The nominators of a proxy, the candidates a proxy has a share on and the
+// proxies with a share on a candidate are kept as indexed sets:
+//
+//	keccak256(name, owner)                    -> number of members
+//	keccak256(name, owner, i)                 -> i-th member
+//	keccak256(name+"Index", owner, member)    -> i+1 for the i-th member
+var ProxyAddress = common.StringToAddress("Proxy")
+
+const (
+	nominatorSet = "nominators"
+	shareSet     = "shares"
+	sharerSet    = "sharers"
+)
+
+func proxyKey(nominator common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("proxy"), nominator.Bytes())
+}
+
+func proxiedKey(nominator common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("proxied"), nominator.Bytes())
+}
+
+func proxiedStakeKey(proxy common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("proxiedStake"), proxy.Bytes())
+}
+
+func proxyShareKey(proxy, candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("share"), proxy.Bytes(), candidate.Bytes())
+}
+
+func proxySharedKey(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("shared"), candidate.Bytes())
+}
+
+func memberCountKey(name string, owner common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte(name), owner.Bytes())
+}
+
+func memberKey(name string, owner common.Address, index uint64) common.Hash {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], index)
+	return crypto.Keccak256Hash([]byte(name), owner.Bytes(), enc[:])
+}
+
+func memberIndexKey(name string, owner, member common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte(name+"Index"), owner.Bytes(), member.Bytes())
+}
+
+func (self *StateDB) getProxyUint64(key common.Hash) uint64 {
+	return self.GetState(ProxyAddress, key).Big().Uint64()
+}
+
+func (self *StateDB) setProxyState(key common.Hash, value common.Hash) {
+	self.setSystemState(ProxyAddress, key, value)
+}
+
+func (self *StateDB) setProxyUint64(key common.Hash, value uint64) {
+	self.setProxyState(key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+func (self *StateDB) members(name string, owner common.Address) []common.Address {
+	count := self.getProxyUint64(memberCountKey(name, owner))
+	members := make([]common.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		members = append(members, common.BytesToAddress(self.GetState(ProxyAddress, memberKey(name, owner, i)).Bytes()))
+	}
+	return members
+}
+
+func (self *StateDB) addMember(name string, owner, member common.Address) {
+	if self.getProxyUint64(memberIndexKey(name, owner, member)) != 0 {
+		return
+	}
+	count := self.getProxyUint64(memberCountKey(name, owner))
+	self.setProxyState(memberKey(name, owner, count), member.Hash())
+	self.setProxyUint64(memberIndexKey(name, owner, member), count+1)
+	self.setProxyUint64(memberCountKey(name, owner), count+1)
+}
+
+func (self *StateDB) removeMember(name string, owner, member common.Address) {
+	index := self.getProxyUint64(memberIndexKey(name, owner, member))
+	if index == 0 {
+		return
+	}
+	// move the last member into the slot of the removed one
+	count := self.getProxyUint64(memberCountKey(name, owner))
+	last := self.GetState(ProxyAddress, memberKey(name, owner, count-1))
+	self.setProxyState(memberKey(name, owner, index-1), last)
+	self.setProxyUint64(memberIndexKey(name, owner, common.BytesToAddress(last.Bytes())), index)
+	self.setProxyState(memberKey(name, owner, count-1), common.Hash{})
+	self.setProxyState(memberIndexKey(name, owner, member), common.Hash{})
+	self.setProxyUint64(memberCountKey(name, owner), count-1)
+}
+
+// GetProxy returns the proxy of nominator and the whole AOA it locked for the
+// proxy, or the zero address if nominator has none.
+func (self *StateDB) GetProxy(nominator common.Address) (common.Address, uint64) {
+	proxy := common.BytesToAddress(self.GetState(ProxyAddress, proxyKey(nominator)).Bytes())
+	return proxy, self.getProxyUint64(proxiedKey(nominator))
+}
+
+// SetProxy makes proxy vote with stake whole AOA of nominator, replacing the
+// stake nominator proxied before. A zero proxy or stake revokes the proxy. The
+// shares of the candidates are not touched.
+func (self *StateDB) SetProxy(nominator, proxy common.Address, stake uint64) {
+	if prev, prevStake := self.GetProxy(nominator); prev != (common.Address{}) {
+		self.setProxyUint64(proxiedStakeKey(prev), self.ProxiedStake(prev)-prevStake)
+		self.removeMember(nominatorSet, prev, nominator)
+	}
+	if proxy == (common.Address{}) || stake == 0 {
+		self.setProxyState(proxyKey(nominator), common.Hash{})
+		self.setProxyState(proxiedKey(nominator), common.Hash{})
+		return
+	}
+	self.setProxyState(proxyKey(nominator), proxy.Hash())
+	self.setProxyUint64(proxiedKey(nominator), stake)
+	self.setProxyUint64(proxiedStakeKey(proxy), self.ProxiedStake(proxy)+stake)
+	self.addMember(nominatorSet, proxy, nominator)
+}
+
+// ProxiedStake returns the whole AOA the nominators of proxy locked for it.
+func (self *StateDB) ProxiedStake(proxy common.Address) uint64 {
+	return self.getProxyUint64(proxiedStakeKey(proxy))
+}
+
+// ProxyNominators returns the accounts that nominated proxy.
+func (self *StateDB) ProxyNominators(proxy common.Address) []common.Address {
+	return self.members(nominatorSet, proxy)
+}
+
+// GetProxyShare returns the whole AOA of the stake proxied to proxy that is
+// counted for candidate.
+func (self *StateDB) GetProxyShare(proxy, candidate common.Address) uint64 {
+	return self.getProxyUint64(proxyShareKey(proxy, candidate))
+}
+
+// SetProxyShare records the share of candidate in the stake proxied to proxy
+// and keeps the totals of the candidate in step. A zero share removes it.
+func (self *StateDB) SetProxyShare(proxy, candidate common.Address, share uint64) {
+	prev := self.GetProxyShare(proxy, candidate)
+	self.setProxyUint64(proxyShareKey(proxy, candidate), share)
+	self.setProxyUint64(proxySharedKey(candidate), self.getProxyUint64(proxySharedKey(candidate))-prev+share)
+	if share > 0 {
+		self.addMember(shareSet, proxy, candidate)
+		self.addMember(sharerSet, candidate, proxy)
+	} else {
+		self.removeMember(shareSet, proxy, candidate)
+		self.removeMember(sharerSet, candidate, proxy)
+	}
+}
+
+// ProxyShares returns the candidates with a share in the stake proxied to proxy.
+func (self *StateDB) ProxyShares(proxy common.Address) []common.Address {
+	return self.members(shareSet, proxy)
+}
+
+// CandidateProxies returns the proxies whose proxied stake has a share on
+// candidate.
+func (self *StateDB) CandidateProxies(candidate common.Address) []common.Address {
+	return self.members(sharerSet, candidate)
+}
+
+// candidateProxyShared returns the whole AOA counted for candidate through
+// proxies.
+func (self *StateDB) candidateProxyShared(candidate common.Address) uint64 {
+	return self.getProxyUint64(proxySharedKey(candidate))
+}
@@ -0,0 +1,136 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// RewardAddress is the account that holds the share of the block rewards owed
+// to voters until they claim it. Its storage keeps:
+//
+//	keccak256("commission", delegate)  -> commission of delegate in basis points, plus one
+//	keccak256("acc", delegate)         -> reward per staked AOA of delegate, times RewardPrecision
+//	keccak256("paid", voter, delegate) -> acc of delegate when the reward of voter was last settled
+//	keccak256("owed", voter)           -> settled reward of voter that is not claimed yet
+//
+// The accumulator only grows, so sharing a block reward touches a single slot
+// whatever the number of voters, and each voter settles lazily.
+var RewardAddress = common.StringToAddress("Rewards")
+
+// RewardPrecision scales the accumulator so that rewards much smaller than the
+// staked AOA of a delegate are still shared.
+var RewardPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func commissionKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("commission"), delegate.Bytes())
+}
+
+func rewardAccKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("acc"), delegate.Bytes())
+}
+
+func rewardPaidKey(voter, delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("paid"), voter.Bytes(), delegate.Bytes())
+}
+
+func rewardOwedKey(voter common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("owed"), voter.Bytes())
+}
+
+func (self *StateDB) getRewardBig(key common.Hash) *big.Int {
+	return self.GetState(RewardAddress, key).Big()
+}
+
+func (self *StateDB) setRewardBig(key common.Hash, value *big.Int) {
+	self.setSystemState(RewardAddress, key, common.BigToHash(value))
+}
+
+// GetCommission returns the commission of delegate in basis points. A delegate
+// that never set one keeps the whole reward, as before rewards were shared.
+func (self *StateDB) GetCommission(delegate common.Address) uint64 {
+	stored := self.getRewardBig(commissionKey(delegate)).Uint64()
+	if stored == 0 {
+		return types.MaxCommission
+	}
+	return stored - 1
+}
+
+func (self *StateDB) SetCommission(delegate common.Address, commission uint64) {
+	self.setRewardBig(commissionKey(delegate), new(big.Int).SetUint64(commission+1))
+}
+
+// ShareReward takes the voter share of reward, earned by delegate, from the
+// balance of payer and adds it to the accumulator of delegate. It returns the
+// amount taken, which is zero if no voter of delegate has a recorded stake.
+func (self *StateDB) ShareReward(payer, delegate common.Address, reward *big.Int) *big.Int {
+	staked := self.getUint64(candidateStakedKey(delegate))
+	if staked == 0 || reward.Sign() <= 0 {
+		return new(big.Int)
+	}
+	share := new(big.Int).Mul(reward, new(big.Int).SetUint64(types.MaxCommission-self.GetCommission(delegate)))
+	share.Div(share, big.NewInt(types.MaxCommission))
+
+	perStake := new(big.Int).Mul(share, RewardPrecision)
+	perStake.Div(perStake, new(big.Int).SetUint64(staked))
+	if perStake.Sign() == 0 {
+		return new(big.Int)
+	}
+	// only take what the voters can claim, the rounding stays with the payer
+	taken := new(big.Int).Mul(perStake, new(big.Int).SetUint64(staked))
+	taken.Div(taken, RewardPrecision)
+
+	self.setRewardBig(rewardAccKey(delegate), new(big.Int).Add(self.getRewardBig(rewardAccKey(delegate)), perStake))
+	self.SubBalance(payer, taken)
+	self.AddBalance(RewardAddress, taken)
+	return taken
+}
+
+// settleReward moves the reward voter earned on delegate with stake since its
+// last settlement to the owed reward of voter.
+func (self *StateDB) settleReward(voter, delegate common.Address, stake uint64) {
+	acc := self.getRewardBig(rewardAccKey(delegate))
+	if pending := self.pendingReward(voter, delegate, stake, acc); pending.Sign() > 0 {
+		self.setRewardBig(rewardOwedKey(voter), new(big.Int).Add(self.getRewardBig(rewardOwedKey(voter)), pending))
+	}
+	if acc.Cmp(self.getRewardBig(rewardPaidKey(voter, delegate))) != 0 {
+		self.setRewardBig(rewardPaidKey(voter, delegate), acc)
+	}
+}
+
+func (self *StateDB) pendingReward(voter, delegate common.Address, stake uint64, acc *big.Int) *big.Int {
+	pending := new(big.Int).Sub(acc, self.getRewardBig(rewardPaidKey(voter, delegate)))
+	pending.Mul(pending, new(big.Int).SetUint64(stake))
+	return pending.Div(pending, RewardPrecision)
+}
+
+// GetPendingRewards returns the reward voter can claim from its votes on
+// candidates.
+func (self *StateDB) GetPendingRewards(voter common.Address, candidates []common.Address) *big.Int {
+	total := new(big.Int).Set(self.getRewardBig(rewardOwedKey(voter)))
+	for _, delegate := range candidates {
+		if stake := self.GetVoteStake(voter, delegate); stake > 0 {
+			total.Add(total, self.pendingReward(voter, delegate, stake, self.getRewardBig(rewardAccKey(delegate))))
+		}
+	}
+	return total
+}
+
+// ClaimRewards settles the votes of voter on candidates and pays the whole
+// owed reward to voter. It returns the amount paid.
+func (self *StateDB) ClaimRewards(voter common.Address, candidates []common.Address) *big.Int {
+	for _, delegate := range candidates {
+		if stake := self.GetVoteStake(voter, delegate); stake > 0 {
+			self.settleReward(voter, delegate, stake)
+		}
+	}
+	owed := self.getRewardBig(rewardOwedKey(voter))
+	if owed.Sign() > 0 {
+		self.setRewardBig(rewardOwedKey(voter), new(big.Int))
+		self.SubBalance(RewardAddress, owed)
+		self.AddBalance(voter, owed)
+	}
+	return owed
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// RoundAddress is the account whose storage keeps the shuffle list of the
+// current round, which missed slots are counted against. Layout:
+//
+//	keccak256("round")   -> number of the current round, from 1
+//	keccak256("shuffle") -> shuffle hash of the current round
+//	keccak256("slots")   -> number of slots of the current round
+//	keccak256("slot", i) -> delegate and work time of the i-th slot
+var RoundAddress = common.StringToAddress("Round")
+
+// RoundSlot is the slot of a delegate in the shuffle list of a round.
+type RoundSlot struct {
+	Delegate common.Address
+	WorkTime uint64
+}
+
+var (
+	roundKey   = crypto.Keccak256Hash([]byte("round"))
+	shuffleKey = crypto.Keccak256Hash([]byte("shuffle"))
+	slotsKey   = crypto.Keccak256Hash([]byte("slots"))
+)
+
+func slotKey(i uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("slot"), new(big.Int).SetUint64(i).Bytes())
+}
+
+func (self *StateDB) getRoundUint64(key common.Hash) uint64 {
+	return self.GetState(RoundAddress, key).Big().Uint64()
+}
+
+func (self *StateDB) setRoundState(key common.Hash, value common.Hash) {
+	self.setSystemState(RoundAddress, key, value)
+}
+
+func (self *StateDB) setRoundUint64(key common.Hash, value uint64) {
+	self.setRoundState(key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+// CurrentRound returns the number of the current round, or zero before the
+// first round is recorded.
+func (self *StateDB) CurrentRound() uint64 {
+	return self.getRoundUint64(roundKey)
+}
+
+// RoundShuffle returns the shuffle hash and slots of the current round.
+func (self *StateDB) RoundShuffle() (common.Hash, []RoundSlot) {
+	count := self.getRoundUint64(slotsKey)
+	slots := make([]RoundSlot, 0, count)
+	for i := uint64(0); i < count; i++ {
+		enc := self.GetState(RoundAddress, slotKey(i))
+		slots = append(slots, RoundSlot{
+			Delegate: common.BytesToAddress(enc[:common.AddressLength]),
+			WorkTime: binary.BigEndian.Uint64(enc[common.HashLength-8:]),
+		})
+	}
+	return self.GetState(RoundAddress, shuffleKey), slots
+}
+
+// BeginRound records the shuffle list of a new round and advances the round
+// number.
+func (self *StateDB) BeginRound(shuffleHash common.Hash, slots []RoundSlot) {
+	prev := self.getRoundUint64(slotsKey)
+	for i, slot := range slots {
+		var enc common.Hash
+		copy(enc[:common.AddressLength], slot.Delegate.Bytes())
+		binary.BigEndian.PutUint64(enc[common.HashLength-8:], slot.WorkTime)
+		self.setRoundState(slotKey(uint64(i)), enc)
+	}
+	for i := uint64(len(slots)); i < prev; i++ {
+		self.setRoundState(slotKey(i), common.Hash{})
+	}
+	self.setRoundUint64(slotsKey, uint64(len(slots)))
+	self.setRoundState(shuffleKey, shuffleHash)
+	self.setRoundUint64(roundKey, self.CurrentRound()+1)
+}
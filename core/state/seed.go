@@ -0,0 +1,68 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// SeedAddress is the account whose storage keeps the seed hash chains the
+// delegates reveal their blocks with and the seed mixed from the reveals.
+// Layout:
+//
+//	keccak256("mix")               -> keccak256 chain of every reveal so far
+//	keccak256("key", delegate)     -> producer key the hash chain is drawn with
+//	keccak256("reveals", delegate) -> reveals of delegate with that key
+//	keccak256("last", delegate)    -> last reveal of delegate, left aligned
+var SeedAddress = common.StringToAddress("Seed")
+
+var seedMixKey = crypto.Keccak256Hash([]byte("mix"))
+
+func seedChainKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("key"), delegate.Bytes())
+}
+
+func seedRevealsKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("reveals"), delegate.Bytes())
+}
+
+func seedLastKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("last"), delegate.Bytes())
+}
+
+func (self *StateDB) setSeedState(key common.Hash, value common.Hash) {
+	self.setSystemState(SeedAddress, key, value)
+}
+
+// SeedMix returns the seed mixed from every reveal so far.
+func (self *StateDB) SeedMix() common.Hash {
+	return self.GetState(SeedAddress, seedMixKey)
+}
+
+// SeedChain returns the producer key the seed hash chain of delegate is drawn
+// with, the number of reveals of delegate with that key and the last one.
+func (self *StateDB) SeedChain(delegate common.Address) (common.Address, uint64, common.Hash) {
+	key := common.BytesToAddress(self.GetState(SeedAddress, seedChainKey(delegate)).Bytes())
+	reveals := self.GetState(SeedAddress, seedRevealsKey(delegate)).Big().Uint64()
+	return key, reveals, self.GetState(SeedAddress, seedLastKey(delegate))
+}
+
+// RevealSeed records reveal as the next reveal of delegate with key, the first
+// of a new hash chain if key differs from the one recorded, and mixes it into
+// the seed if mix is set. Only a reveal that follows a recorded one could not be
+// chosen by delegate, the first of a chain must not be mixed.
+func (self *StateDB) RevealSeed(delegate common.Address, key common.Address, reveal []byte, mix bool) {
+	recorded, reveals, _ := self.SeedChain(delegate)
+	if recorded != key {
+		self.setSeedState(seedChainKey(delegate), key.Hash())
+		reveals = 0
+	}
+	var last common.Hash
+	copy(last[:], reveal)
+	self.setSeedState(seedLastKey(delegate), last)
+	self.setSeedState(seedRevealsKey(delegate), common.BigToHash(new(big.Int).SetUint64(reveals+1)))
+	if mix {
+		self.setSeedState(seedMixKey, crypto.Keccak256Hash(self.SeedMix().Bytes(), reveal))
+	}
+}
@@ -290,6 +290,8 @@ func (self *stateObject) deepCopy(db *StateDB, onDirty func(addr common.Address)
 	stateObject.cachedStorage = self.dirtyStorage.Copy()
 	stateObject.suicided = self.suicided
 	stateObject.dirtyCode = self.dirtyCode
+	stateObject.assetData = self.assetData
+	stateObject.dirtyAssetData = self.dirtyAssetData
 	stateObject.deleted = self.deleted
 	return stateObject
 }
@@ -412,6 +414,7 @@ func (self *stateObject) SubAssetBalance(asset common.Address, amount *big.Int)
 			asset:      types.Asset{ID: asset, Balance: new(big.Int).Set(amount)},
 			preOpIsAdd: false,
 		})
+		self.db.markAssetChanged(self.address, asset)
 		self.tryMarkDirty()
 	}
 	return isOk
@@ -425,6 +428,7 @@ func (self *stateObject) AddAssetBalance(asset common.Address, amount *big.Int)
 	})
 	var a = types.Asset{ID: asset, Balance: new(big.Int).Set(amount)}
 	self.data.AssetList.AddAsset(a)
+	self.db.markAssetChanged(self.address, asset)
 	self.tryMarkDirty()
 }
 
@@ -452,6 +456,22 @@ func (self *stateObject) SetAssetData(hash common.Hash, data []byte) error {
 	return nil
 }
 
+func (self *stateObject) UpdateAssetData(hash common.Hash, data []byte) {
+	self.db.journal = append(self.db.journal, assetDataChange{
+		account:  &self.address,
+		prevhash: self.data.AssetHash,
+		prevdata: self.assetData,
+	})
+	self.setAssetData(hash[:], data)
+}
+
+func (self *stateObject) setAssetData(hash []byte, data []byte) {
+	self.assetData = data
+	self.data.AssetHash = hash
+	self.dirtyAssetData = true
+	self.tryMarkDirty()
+}
+
 func (self *stateObject) AssetHash() []byte {
 	return self.data.AssetHash
 }
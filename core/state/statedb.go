@@ -39,6 +39,8 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	assetsChanged map[assetHolding]struct{}
+
 	journal        journal
 	validRevisions []revision
 	nextRevisionId int
@@ -59,6 +61,7 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		stateObjectsDirty: make(map[common.Address]struct{}),
 		logs:              make(map[common.Hash][]*types.Log),
 		preimages:         make(map[common.Hash][]byte),
+		assetsChanged:     make(map[assetHolding]struct{}),
 	}, nil
 }
 
@@ -86,6 +89,7 @@ func (self *StateDB) Reset(root common.Hash) error {
 	self.logs = make(map[common.Hash][]*types.Log)
 	self.logSize = 0
 	self.preimages = make(map[common.Hash][]byte)
+	self.assetsChanged = make(map[assetHolding]struct{})
 	self.clearJournalAndRefund()
 	return nil
 }
@@ -352,6 +356,16 @@ func (self *StateDB) SetState(addr common.Address, key common.Hash, value common
 	}
 }
 
+// setSystemState writes a slot of one of the protocol registry accounts. The
+// registry is given a nonce so it is never swept as an empty account.
+func (self *StateDB) setSystemState(addr common.Address, key common.Hash, value common.Hash) {
+	registry := self.GetOrNewStateObject(addr)
+	if registry.Nonce() == 0 {
+		registry.SetNonce(1)
+	}
+	registry.SetState(self.db, key, value)
+}
+
 func (self *StateDB) Suicide(addr common.Address) bool {
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
@@ -364,6 +378,9 @@ func (self *StateDB) Suicide(addr common.Address) bool {
 	})
 	stateObject.markSuicided()
 	stateObject.data.Balance = new(big.Int)
+	for _, asset := range stateObject.GetAssets() {
+		self.markAssetChanged(addr, asset.ID)
+	}
 
 	return true
 }
@@ -477,6 +494,7 @@ func (self *StateDB) Copy() *StateDB {
 		logs:              make(map[common.Hash][]*types.Log, len(self.logs)),
 		logSize:           self.logSize,
 		preimages:         make(map[common.Hash][]byte),
+		assetsChanged:     make(map[assetHolding]struct{}, len(self.assetsChanged)),
 	}
 
 	for addr := range self.stateObjectsDirty {
@@ -490,6 +508,9 @@ func (self *StateDB) Copy() *StateDB {
 	for hash, preimage := range self.preimages {
 		state.preimages[hash] = preimage
 	}
+	for h := range self.assetsChanged {
+		state.assetsChanged[h] = struct{}{}
+	}
 
 	return state
 }
@@ -604,14 +625,14 @@ func (s *StateDB) CommitTo(dbw trie.DatabaseWriter, deleteEmptyObjects bool) (ro
 	return root, err
 }
 
-func (self *StateDB) PublishAsset(addr common.Address, assetInfo types.AssetInfo) error {
+func (self *StateDB) PublishAsset(addr common.Address, assetInfo types.AssetInfo, isHermes bool) error {
 	stateObject := self.GetOrNewStateObject(addr)
 	if nil == stateObject {
 		return errors.New("Can't find the account: " + addr.String())
 	}
 	assetInfo.Issuer = &addr
 
-	err := self.ValidateAsset(assetInfo)
+	err := self.ValidateAsset(assetInfo, isHermes)
 	if nil != err {
 		return err
 	}
@@ -627,11 +648,20 @@ func (self *StateDB) PublishAsset(addr common.Address, assetInfo types.AssetInfo
 	assetAccount.SetAssetData(crypto.Keccak256Hash(data), data)
 
 	stateObject.AddAssetBalance(id, assetInfo.Supply)
+	if isHermes {
+		self.registerAsset(assetInfo.Symbol, id)
+	}
 	return nil
 }
 
-func (self *StateDB) ValidateAsset(assetInfo types.AssetInfo) error {
-	return types.IsAssetInfoValid(&assetInfo)
+func (self *StateDB) ValidateAsset(assetInfo types.AssetInfo, isHermes bool) error {
+	if err := types.IsAssetInfoValid(&assetInfo); err != nil {
+		return err
+	}
+	if isHermes {
+		return self.validateAssetSymbol(assetInfo.Symbol)
+	}
+	return nil
 }
 
 func (self *StateDB) GetAssetInfo(addr common.Address) (*types.AssetInfo, error) {
@@ -651,3 +681,58 @@ func (self *StateDB) GetAssetInfo(addr common.Address) (*types.AssetInfo, error)
 	}
 	return nil, fmt.Errorf("%s is not an asset account", addr.String())
 }
+
+func (self *StateDB) ValidateAssetIssuer(asset common.Address, issuer common.Address) (*types.AssetInfo, error) {
+	ai, err := self.GetAssetInfo(asset)
+	if nil != err {
+		return nil, err
+	}
+	if nil == ai.Issuer || *ai.Issuer != issuer {
+		return nil, fmt.Errorf("%s is not the issuer of asset %s", issuer.String(), asset.String())
+	}
+	return ai, nil
+}
+
+func (self *StateDB) MintAsset(issuer common.Address, asset common.Address, to common.Address, amount *big.Int) error {
+	if nil == amount || amount.Sign() <= 0 {
+		return errors.New("Mint amount must be greater then 0")
+	}
+	ai, err := self.ValidateAssetIssuer(asset, issuer)
+	if nil != err {
+		return err
+	}
+	ai.Supply = new(big.Int).Add(ai.Supply, amount)
+	if err := self.updateAssetInfo(asset, ai); nil != err {
+		return err
+	}
+	self.AddAssetBalance(to, asset, amount)
+	return nil
+}
+
+func (self *StateDB) BurnAsset(issuer common.Address, asset common.Address, amount *big.Int) error {
+	if nil == amount || amount.Sign() <= 0 {
+		return errors.New("Burn amount must be greater then 0")
+	}
+	ai, err := self.ValidateAssetIssuer(asset, issuer)
+	if nil != err {
+		return err
+	}
+	if !self.SubAssetBalance(issuer, asset, amount) {
+		return fmt.Errorf("insufficient balance of asset %s to burn", asset.String())
+	}
+	ai.Supply = new(big.Int).Sub(ai.Supply, amount)
+	return self.updateAssetInfo(asset, ai)
+}
+
+func (self *StateDB) updateAssetInfo(asset common.Address, assetInfo *types.AssetInfo) error {
+	stateObject := self.getStateObject(asset)
+	if nil == stateObject || !stateObject.IsAssetAccount() {
+		return fmt.Errorf("%s is not an asset account", asset.String())
+	}
+	data, err := rlp.EncodeToBytes(assetInfo)
+	if nil != err {
+		return err
+	}
+	stateObject.UpdateAssetData(crypto.Keccak256Hash(data), data)
+	return nil
+}
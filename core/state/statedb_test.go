@@ -17,6 +17,7 @@ import (
 	"github.com/Aurorachain/go-Aurora/aoadb"
 	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
 )
 
 func TestUpdateLeaks(t *testing.T) {
@@ -402,3 +403,518 @@ func (s *StateSuite) TestTouchDelete(c *check.C) {
 		c.Fatal("expected no dirty state object")
 	}
 }
+
+// newTestState returns an empty state on a memory database.
+func newTestState() (*StateDB, *aoadb.MemDatabase) {
+	mem, _ := aoadb.NewMemDatabase()
+	state, _ := New(common.Hash{}, NewDatabase(mem))
+	return state, mem
+}
+
+// reopenState commits state to mem and opens it again from its root, so that
+// what follows reads what was stored.
+func reopenState(state *StateDB, mem *aoadb.MemDatabase) *StateDB {
+	root, _ := state.CommitTo(mem, true)
+	state, _ = New(root, NewDatabase(mem))
+	return state
+}
+
+func TestMintBurnAsset(t *testing.T) {
+	state, _ := newTestState()
+	issuer := common.Address{1}
+	holder := common.Address{2}
+
+	ai := types.AssetInfo{Name: "Test Dollar", Symbol: "TUSD", Supply: big.NewInt(1000), Desc: "test"}
+	if err := state.PublishAsset(issuer, ai, true); err != nil {
+		t.Fatalf("publish asset failed: %v", err)
+	}
+	asset := crypto.CreateAddress(issuer, 0)
+
+	if err := state.MintAsset(holder, asset, holder, big.NewInt(1)); err == nil {
+		t.Fatalf("mint by non-issuer should fail")
+	}
+	if err := state.MintAsset(issuer, asset, holder, big.NewInt(500)); err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if have := state.GetAssetBalance(holder, asset); have.Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("holder balance mismatch: have %v, want 500", have)
+	}
+	info, _ := state.GetAssetInfo(asset)
+	if info.Supply.Cmp(big.NewInt(1500)) != 0 {
+		t.Errorf("supply mismatch after mint: have %v, want 1500", info.Supply)
+	}
+
+	snapshot := state.Snapshot()
+	if err := state.BurnAsset(issuer, asset, big.NewInt(300)); err != nil {
+		t.Fatalf("burn failed: %v", err)
+	}
+	info, _ = state.GetAssetInfo(asset)
+	if info.Supply.Cmp(big.NewInt(1200)) != 0 {
+		t.Errorf("supply mismatch after burn: have %v, want 1200", info.Supply)
+	}
+	state.RevertToSnapshot(snapshot, true)
+	info, _ = state.GetAssetInfo(asset)
+	if info.Supply.Cmp(big.NewInt(1500)) != 0 {
+		t.Errorf("supply mismatch after revert: have %v, want 1500", info.Supply)
+	}
+	if have := state.GetAssetBalance(issuer, asset); have.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("issuer balance mismatch after revert: have %v, want 1000", have)
+	}
+
+	if err := state.BurnAsset(issuer, asset, big.NewInt(1001)); err == nil {
+		t.Fatalf("burning more than the issuer balance should fail")
+	}
+}
+
+func TestAssetChanges(t *testing.T) {
+	state, mem := newTestState()
+	issuer, alice, bob := common.Address{1}, common.Address{2}, common.Address{3}
+	asset := common.Address{9}
+
+	state.AddAssetBalance(issuer, asset, big.NewInt(100))
+	state.AddAssetBalance(bob, asset, big.NewInt(5))
+	root, _ := state.CommitTo(mem, false)
+
+	state, _ = New(root, NewDatabase(mem))
+	state.SubAssetBalance(issuer, asset, big.NewInt(40))
+	state.AddAssetBalance(alice, asset, big.NewInt(40))
+	state.SubAssetBalance(bob, asset, big.NewInt(5))
+	want := []AssetChange{
+		{Holder: issuer, Asset: asset, Balance: big.NewInt(60)},
+		{Holder: alice, Asset: asset, Balance: big.NewInt(40)},
+		{Holder: bob, Asset: asset, Balance: big.NewInt(0)},
+	}
+	if changes := state.AssetChanges(); !reflect.DeepEqual(changes, want) {
+		t.Errorf("asset changes mismatch: have %v, want %v", changes, want)
+	}
+	if changes := state.Copy().AssetChanges(); !reflect.DeepEqual(changes, want) {
+		t.Errorf("asset changes of the copy mismatch: have %v, want %v", changes, want)
+	}
+}
+
+func TestAssetSymbolRegistry(t *testing.T) {
+	state, mem := newTestState()
+	issuer := common.Address{1}
+
+	usdx := types.AssetInfo{Name: "USD X", Symbol: "USDX", Supply: big.NewInt(1000), Desc: "test"}
+	if err := state.PublishAsset(issuer, usdx, true); err != nil {
+		t.Fatalf("publish asset failed: %v", err)
+	}
+	dup := types.AssetInfo{Name: "Fake USD X", Symbol: "usdx", Supply: big.NewInt(1000), Desc: "test"}
+	if err := state.PublishAsset(common.Address{2}, dup, true); err == nil {
+		t.Fatalf("publishing a duplicate symbol should fail")
+	}
+	if err := state.PublishAsset(common.Address{2}, dup, false); err != nil {
+		t.Fatalf("duplicate symbol before the fork should be accepted: %v", err)
+	}
+	other := types.AssetInfo{Name: "Other", Symbol: "OTH", Supply: big.NewInt(1), Desc: "test"}
+	if err := state.PublishAsset(issuer, other, true); err != nil {
+		t.Fatalf("publish asset failed: %v", err)
+	}
+
+	asset, exist := state.GetAssetBySymbol("UsDx")
+	if !exist || asset != crypto.CreateAddress(issuer, 0) {
+		t.Errorf("symbol lookup mismatch: have %x (%v), want %x", asset, exist, crypto.CreateAddress(issuer, 0))
+	}
+	if count := state.AssetCount(); count != 2 {
+		t.Errorf("asset count mismatch: have %d, want 2", count)
+	}
+	if list := state.ListAssets(1, 10); len(list) != 1 || list[0] != crypto.CreateAddress(issuer, 1) {
+		t.Errorf("asset list mismatch: have %x", list)
+	}
+	if list := state.ListAssets(2, 10); len(list) != 0 {
+		t.Errorf("asset list out of range should be empty: have %x", list)
+	}
+
+	// the registry must survive the empty account sweep
+	state = reopenState(state, mem)
+	if _, exist := state.GetAssetBySymbol("USDX"); !exist {
+		t.Errorf("symbol registry lost after commit")
+	}
+}
+
+func TestBackfillAssetRegistry(t *testing.T) {
+	state, mem := newTestState()
+
+	// legacy assets, one symbol published twice
+	usdx := types.AssetInfo{Name: "USD X", Symbol: "USDX", Supply: big.NewInt(1000), Desc: "test"}
+	for _, issuer := range []common.Address{{1}, {2}} {
+		if err := state.PublishAsset(issuer, usdx, false); err != nil {
+			t.Fatalf("publish asset failed: %v", err)
+		}
+	}
+	root, _ := state.CommitTo(mem, false)
+	state, _ = New(root, NewDatabase(mem))
+	if count := state.AssetCount(); count != 0 {
+		t.Fatalf("legacy assets registered: have %d", count)
+	}
+
+	// the table is recovered without preimages, in address order
+	first, second := crypto.CreateAddress(common.Address{1}, 0), crypto.CreateAddress(common.Address{2}, 0)
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+	for _, key := range mem.Keys() {
+		if bytes.HasPrefix(key, []byte("secure-key-")) {
+			mem.Delete(key)
+		}
+	}
+	legacy, err := New(root, NewDatabase(mem))
+	if err != nil {
+		t.Fatalf("failed to reopen state: %v", err)
+	}
+	assets, err := legacy.LegacyAssets()
+	if err != nil {
+		t.Fatalf("legacy assets failed: %v", err)
+	}
+	if len(assets) != 2 || assets[0] != first || assets[1] != second {
+		t.Fatalf("legacy assets mismatch: have %x, want [%x %x]", assets, first, second)
+	}
+
+	first, second = second, first
+	if err := state.BackfillAssetRegistry([]common.Address{first, second}); err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	if count := state.AssetCount(); count != 2 {
+		t.Errorf("asset count mismatch: have %d, want 2", count)
+	}
+	if list := state.ListAssets(0, 10); len(list) != 2 || list[0] != first || list[1] != second {
+		t.Errorf("asset list mismatch: have %x, want [%x %x]", list, first, second)
+	}
+	if asset, exist := state.GetAssetBySymbol("USDX"); !exist || asset != first {
+		t.Errorf("symbol lookup mismatch: have %x (%v), want %x", asset, exist, first)
+	}
+	if err := state.BackfillAssetRegistry([]common.Address{{3}}); err == nil {
+		t.Errorf("backfill of a non-asset account succeeded")
+	}
+}
+
+func TestVoteStake(t *testing.T) {
+	state, mem := newTestState()
+	alice, bob, delegate := common.Address{1}, common.Address{2}, common.Address{3}
+
+	// a tally of 3 AOA without records: three votes cast before stake weighting
+	if voters := state.CandidateVoters(delegate, 3); voters != 3 {
+		t.Errorf("legacy voters mismatch: have %d, want 3", voters)
+	}
+	state.SetVoteStake(alice, delegate, 100)
+	state.SetVoteStake(bob, delegate, 20)
+	if stake := state.GetVoteStake(alice, delegate); stake != 100 {
+		t.Errorf("stake mismatch: have %d, want 100", stake)
+	}
+	if voters := state.CandidateVoters(delegate, 123); voters != 5 {
+		t.Errorf("voters mismatch: have %d, want 5", voters)
+	}
+	state.SetVoteStake(alice, delegate, 0)
+	if voters := state.CandidateVoters(delegate, 23); voters != 4 {
+		t.Errorf("voters after unstaking mismatch: have %d, want 4", voters)
+	}
+
+	state = reopenState(state, mem)
+	if stake := state.GetVoteStake(bob, delegate); stake != 20 {
+		t.Errorf("stake after commit mismatch: have %d, want 20", stake)
+	}
+}
+
+func TestCandidateStakers(t *testing.T) {
+	state, mem := newTestState()
+	alice, bob, carol, delegate := common.Address{1}, common.Address{2}, common.Address{4}, common.Address{3}
+
+	state.SetVoteStake(alice, delegate, 1)
+	state.SetVoteStake(bob, delegate, 2)
+	state.SetVoteStake(carol, delegate, 3)
+	state.SetVoteStake(bob, delegate, 5)
+	state.SetVoteStake(alice, delegate, 0)
+	if stakers := state.CandidateStakers(delegate, 10); !reflect.DeepEqual(stakers, []common.Address{carol, bob}) {
+		t.Errorf("stakers mismatch: have %x", stakers)
+	}
+	if stakers := state.CandidateStakers(delegate, 1); !reflect.DeepEqual(stakers, []common.Address{carol}) {
+		t.Errorf("limited stakers mismatch: have %x", stakers)
+	}
+	if count := state.CandidateStakerCount(delegate); count != 2 {
+		t.Errorf("staker count mismatch: have %d, want 2", count)
+	}
+	state.SetVoteStake(bob, delegate, 0)
+	state.SetVoteStake(carol, delegate, 0)
+	if stakers := state.CandidateStakers(delegate, 10); len(stakers) != 0 {
+		t.Errorf("stakers left: have %x", stakers)
+	}
+
+	stake := big.NewInt(5000)
+	state.SetRegistrationStake(delegate, stake)
+	state = reopenState(state, mem)
+	if have := state.GetRegistrationStake(delegate); have.Cmp(stake) != 0 {
+		t.Errorf("registration stake mismatch: have %v, want %v", have, stake)
+	}
+}
+
+func TestDepartedQueue(t *testing.T) {
+	state, mem := newTestState()
+	first, second := common.Address{1}, common.Address{2}
+
+	if _, ok := state.DepartedDelegate(); ok {
+		t.Fatalf("departed delegate in an empty queue")
+	}
+	state.QueueDeparted(first)
+	state.QueueDeparted(second)
+	state = reopenState(state, mem)
+	if delegate, ok := state.DepartedDelegate(); !ok || delegate != first {
+		t.Errorf("first departed mismatch: have %x (%v), want %x", delegate, ok, first)
+	}
+	state.PopDeparted()
+	if delegate, ok := state.DepartedDelegate(); !ok || delegate != second {
+		t.Errorf("second departed mismatch: have %x (%v), want %x", delegate, ok, second)
+	}
+	state.PopDeparted()
+	state.PopDeparted()
+	if delegate, ok := state.DepartedDelegate(); ok {
+		t.Errorf("departed delegate left: have %x", delegate)
+	}
+}
+
+func TestRewards(t *testing.T) {
+	state, _ := newTestState()
+	alice, bob, delegate := common.Address{1}, common.Address{2}, common.Address{3}
+	state.AddBalance(delegate, big.NewInt(10000))
+
+	state.SetVoteStake(alice, delegate, 3)
+	state.SetVoteStake(bob, delegate, 1)
+	if taken := state.ShareReward(delegate, delegate, big.NewInt(1000)); taken.Sign() != 0 {
+		t.Errorf("reward shared without a commission: %v", taken)
+	}
+
+	state.SetCommission(delegate, 2000)
+	if taken := state.ShareReward(delegate, delegate, big.NewInt(1000)); taken.Cmp(big.NewInt(800)) != 0 {
+		t.Errorf("shared reward mismatch: have %v, want 800", taken)
+	}
+	// bob leaves, keeping what he earned so far
+	state.SetVoteStake(bob, delegate, 0)
+	state.ShareReward(delegate, delegate, big.NewInt(900))
+
+	candidates := []common.Address{delegate}
+	if pending := state.GetPendingRewards(alice, candidates); pending.Cmp(big.NewInt(1320)) != 0 {
+		t.Errorf("alice pending mismatch: have %v, want 1320", pending)
+	}
+	if paid := state.ClaimRewards(alice, candidates); paid.Cmp(big.NewInt(1320)) != 0 {
+		t.Errorf("alice claim mismatch: have %v, want 1320", paid)
+	}
+	if paid := state.ClaimRewards(bob, nil); paid.Cmp(big.NewInt(200)) != 0 {
+		t.Errorf("bob claim mismatch: have %v, want 200", paid)
+	}
+	if pending := state.GetPendingRewards(alice, candidates); pending.Sign() != 0 {
+		t.Errorf("rewards left after claim: %v", pending)
+	}
+	if pool := state.GetBalance(RewardAddress); pool.Sign() != 0 {
+		t.Errorf("reward pool mismatch: have %v, want 0", pool)
+	}
+	if balance := state.GetBalance(delegate); balance.Cmp(big.NewInt(8480)) != 0 {
+		t.Errorf("delegate balance mismatch: have %v, want 8480", balance)
+	}
+	// over one block the engine credits the reward to the coinbase and the voter
+	// share is taken back from it, so the supply grows by the reward alone
+	supply := func() *big.Int {
+		total := new(big.Int)
+		for _, addr := range []common.Address{alice, bob, delegate, RewardAddress} {
+			total.Add(total, state.GetBalance(addr))
+		}
+		return total
+	}
+	before := supply()
+	state.AddBalance(delegate, big.NewInt(900))
+	if taken := state.ShareReward(delegate, delegate, big.NewInt(900)); taken.Cmp(big.NewInt(720)) != 0 {
+		t.Errorf("shared block reward mismatch: have %v, want 720", taken)
+	}
+	if grown := new(big.Int).Sub(supply(), before); grown.Cmp(big.NewInt(900)) != 0 {
+		t.Errorf("supply growth mismatch: have %v, want 900", grown)
+	}
+	if balance := state.GetBalance(delegate); balance.Cmp(big.NewInt(8660)) != 0 {
+		t.Errorf("delegate balance mismatch after block: have %v, want 8660", balance)
+	}
+}
+
+func TestUnbonding(t *testing.T) {
+	state, _ := newTestState()
+	alice, bob := common.Address{1}, common.Address{2}
+
+	state.AddUnbonding(alice, big.NewInt(10), 100)
+	state.AddUnbonding(alice, big.NewInt(5), 200)
+	state.AddUnbonding(bob, big.NewInt(7), 100)
+	if total := state.GetUnbondingBalance(alice); total.Cmp(big.NewInt(15)) != 0 {
+		t.Errorf("unbonding balance mismatch: have %v, want 15", total)
+	}
+
+	state.ReleaseUnbonding(99)
+	if balance := state.GetBalance(alice); balance.Sign() != 0 {
+		t.Errorf("stake released early: balance %v", balance)
+	}
+	state.ReleaseUnbonding(100)
+	if balance := state.GetBalance(alice); balance.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("released balance mismatch: have %v, want 10", balance)
+	}
+	if balance := state.GetBalance(bob); balance.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("released balance mismatch: have %v, want 7", balance)
+	}
+	entries := state.GetUnbonding(alice)
+	if len(entries) != 1 || entries[0].Release != 200 || entries[0].Amount.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("pending entries mismatch: have %+v", entries)
+	}
+	if len(state.GetUnbonding(bob)) != 0 || state.GetUnbondingBalance(bob).Sign() != 0 {
+		t.Errorf("bob should have nothing unbonding")
+	}
+	// releasing the same block again must not credit twice
+	state.ReleaseUnbonding(100)
+	if balance := state.GetBalance(alice); balance.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("double release: balance %v", balance)
+	}
+
+	// slashing only cuts the entries released after the given block
+	state.AddUnbonding(alice, big.NewInt(8), 150)
+	half := func(amount *big.Int) *big.Int { return new(big.Int).Div(amount, big.NewInt(2)) }
+	if slashed := state.SlashUnbonding(alice, 150, half); slashed.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("slashed amount mismatch: have %v, want 2", slashed)
+	}
+	if count := state.UnbondingCount(alice); count != 2 {
+		t.Errorf("unbonding count mismatch: have %d, want 2", count)
+	}
+	if total := state.GetUnbondingBalance(alice); total.Cmp(big.NewInt(11)) != 0 {
+		t.Errorf("unbonding balance after slash mismatch: have %v, want 11", total)
+	}
+	state.ReleaseUnbonding(200)
+	if balance := state.GetBalance(alice); balance.Cmp(big.NewInt(21)) != 0 {
+		t.Errorf("released balance after slash mismatch: have %v, want 21", balance)
+	}
+}
+
+func TestEvidence(t *testing.T) {
+	state, _ := newTestState()
+	offender := common.Address{1}
+
+	state.AddEvidence(offender, 10, 12)
+	if !state.HasEvidence(offender, 10) {
+		t.Errorf("evidence not recorded")
+	}
+	if state.HasEvidence(offender, 11) || state.HasEvidence(common.Address{2}, 10) {
+		t.Errorf("evidence recorded for the wrong offender or height")
+	}
+}
+
+func TestRound(t *testing.T) {
+	state, mem := newTestState()
+
+	first := []RoundSlot{{Delegate: common.Address{1}, WorkTime: 100}, {Delegate: common.Address{2}, WorkTime: 110}}
+	state.BeginRound(common.Hash{1}, first)
+	state.BeginRound(common.Hash{2}, first[1:])
+	state = reopenState(state, mem)
+	hash, slots := state.RoundShuffle()
+	if state.CurrentRound() != 2 || hash != (common.Hash{2}) || len(slots) != 1 || slots[0] != first[1] {
+		t.Errorf("round mismatch: round %d, hash %x, slots %v", state.CurrentRound(), hash, slots)
+	}
+}
+
+func TestSeedReveal(t *testing.T) {
+	state, mem := newTestState()
+	delegate, key, other := common.Address{1}, common.Address{2}, common.Address{3}
+
+	// the first reveal of a chain is not mixed
+	state.RevealSeed(delegate, key, []byte{1}, false)
+	if state.SeedMix() != (common.Hash{}) {
+		t.Errorf("first reveal mixed into the seed")
+	}
+	state.RevealSeed(delegate, key, []byte{2}, true)
+	want := crypto.Keccak256Hash(common.Hash{}.Bytes(), []byte{2})
+	state = reopenState(state, mem)
+	if state.SeedMix() != want {
+		t.Errorf("seed mismatch: have %x, want %x", state.SeedMix(), want)
+	}
+	if recorded, reveals, last := state.SeedChain(delegate); recorded != key || reveals != 2 || last != (common.Hash{2}) {
+		t.Errorf("seed chain mismatch: key %x, reveals %d, last %x", recorded, reveals, last)
+	}
+
+	// another key starts a new chain
+	state.RevealSeed(delegate, other, []byte{3}, false)
+	if recorded, reveals, _ := state.SeedChain(delegate); recorded != other || reveals != 1 {
+		t.Errorf("new seed chain mismatch: key %x, reveals %d", recorded, reveals)
+	}
+}
+
+func TestProducer(t *testing.T) {
+	state, mem := newTestState()
+	delegate, first, second := common.Address{1}, common.Address{2}, common.Address{3}
+
+	if state.ProducerAt(delegate, 10) != delegate {
+		t.Errorf("unbound delegate does not sign with its account")
+	}
+	state.BindProducer(delegate, first, 10)
+	state = reopenState(state, mem)
+	state.BindProducer(delegate, second, 20)
+
+	if key, rotated := state.GetProducer(delegate); key != second || rotated != 20 {
+		t.Errorf("producer mismatch: have %x at %d", key, rotated)
+	}
+	for number, want := range map[uint64]common.Address{10: delegate, 11: first, 20: first, 21: second} {
+		if key := state.ProducerAt(delegate, number); key != want {
+			t.Errorf("block %d: have key %x, want %x", number, key, want)
+		}
+	}
+	if owner := state.ProducerOwner(first); owner != (common.Address{}) {
+		t.Errorf("rotated key still bound to %x", owner)
+	}
+	if owner := state.ProducerOwner(second); owner != delegate {
+		t.Errorf("owner mismatch: have %x, want %x", owner, delegate)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	state, mem := newTestState()
+	proxy, alice, bob, candidate := common.Address{1}, common.Address{2}, common.Address{3}, common.Address{4}
+
+	state.SetProxy(alice, proxy, 3)
+	state.SetProxy(bob, proxy, 5)
+	state.SetProxyShare(proxy, candidate, 8)
+	state = reopenState(state, mem)
+
+	if p, stake := state.GetProxy(alice); p != proxy || stake != 3 {
+		t.Errorf("proxy mismatch: have %x with %d", p, stake)
+	}
+	if stake := state.ProxiedStake(proxy); stake != 8 {
+		t.Errorf("proxied stake mismatch: have %d, want 8", stake)
+	}
+	if voters := state.CandidateVoters(candidate, 10); voters != 2 {
+		t.Errorf("voters mismatch: have %d, want 2", voters)
+	}
+
+	state.SetProxy(alice, common.Address{}, 0)
+	if p, stake := state.GetProxy(alice); p != (common.Address{}) || stake != 0 {
+		t.Errorf("revoked proxy still set: %x with %d", p, stake)
+	}
+	if nominators := state.ProxyNominators(proxy); len(nominators) != 1 || nominators[0] != bob {
+		t.Errorf("nominators mismatch: have %x", nominators)
+	}
+	if stake := state.ProxiedStake(proxy); stake != 5 {
+		t.Errorf("proxied stake mismatch after revoke: have %d, want 5", stake)
+	}
+
+	state.SetProxyShare(proxy, candidate, 0)
+	if shares := state.ProxyShares(proxy); len(shares) != 0 {
+		t.Errorf("cleared share still listed: %x", shares)
+	}
+	if proxies := state.CandidateProxies(candidate); len(proxies) != 0 {
+		t.Errorf("cleared share still indexed: %x", proxies)
+	}
+}
+
+func TestMultisig(t *testing.T) {
+	state, mem := newTestState()
+	account, owners := common.Address{1}, []common.Address{{2}, {3}, {4}}
+
+	if threshold, _ := state.GetMultisig(account); threshold != 0 {
+		t.Fatalf("threshold of a plain account: have %d, want 0", threshold)
+	}
+	state.SetMultisig(account, 2, owners)
+	state = reopenState(state, mem)
+
+	threshold, have := state.GetMultisig(account)
+	if threshold != 2 || !reflect.DeepEqual(have, owners) {
+		t.Errorf("multisig mismatch: have %d of %x, want 2 of %x", threshold, have, owners)
+	}
+}
@@ -0,0 +1,154 @@
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// UnbondingAddress is the account whose storage keeps vote stake that was
+// withdrawn but is not spendable yet. Layout:
+//
+//	keccak256("total", addr)            -> sum of the pending amounts of addr
+//	keccak256("count", addr)            -> number of pending entries of addr
+//	keccak256("block", addr, i)         -> release block of the i-th entry
+//	keccak256("amount", addr, i)        -> amount of the i-th entry
+//	keccak256("queue", block)           -> number of accounts releasing at block
+//	keccak256("queue", block, j)        -> j-th account releasing at block
+var UnbondingAddress = common.StringToAddress("Unbonding")
+
+// Unbonding is an amount of withdrawn vote stake and the block at which it is
+// released to the spendable balance.
+type Unbonding struct {
+	Amount  *big.Int `json:"amount"`
+	Release uint64   `json:"release"`
+}
+
+func encodeUint64s(values ...uint64) []byte {
+	enc := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint64(enc[8*i:], v)
+	}
+	return enc
+}
+
+func unbondingKey(tag string, addr common.Address, index ...uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte(tag), addr.Bytes(), encodeUint64s(index...))
+}
+
+func unbondingQueueKey(block uint64, index ...uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("queue"), encodeUint64s(append([]uint64{block}, index...)...))
+}
+
+func (self *StateDB) getUnbondingBig(key common.Hash) *big.Int {
+	return self.GetState(UnbondingAddress, key).Big()
+}
+
+func (self *StateDB) setUnbondingBig(key common.Hash, value *big.Int) {
+	self.setSystemState(UnbondingAddress, key, common.BigToHash(value))
+}
+
+// GetUnbondingBalance returns the withdrawn vote stake of addr that is not
+// released yet.
+func (self *StateDB) GetUnbondingBalance(addr common.Address) *big.Int {
+	return self.getUnbondingBig(unbondingKey("total", addr))
+}
+
+// GetUnbonding returns the pending entries of addr.
+func (self *StateDB) GetUnbonding(addr common.Address) []Unbonding {
+	count := self.UnbondingCount(addr)
+	entries := make([]Unbonding, 0, count)
+	for i := uint64(0); i < count; i++ {
+		entries = append(entries, Unbonding{
+			Amount:  self.getUnbondingBig(unbondingKey("amount", addr, i)),
+			Release: self.getUnbondingBig(unbondingKey("block", addr, i)).Uint64(),
+		})
+	}
+	return entries
+}
+
+// AddUnbonding moves amount into the unbonding bucket of addr until block
+// release. The caller has already taken it from the locked balance.
+func (self *StateDB) AddUnbonding(addr common.Address, amount *big.Int, release uint64) {
+	count := self.getUnbondingBig(unbondingKey("count", addr)).Uint64()
+	self.setUnbondingBig(unbondingKey("amount", addr, count), amount)
+	self.setUnbondingBig(unbondingKey("block", addr, count), new(big.Int).SetUint64(release))
+	self.setUnbondingBig(unbondingKey("count", addr), new(big.Int).SetUint64(count+1))
+	self.setUnbondingBig(unbondingKey("total", addr), new(big.Int).Add(self.GetUnbondingBalance(addr), amount))
+
+	queued := self.getUnbondingBig(unbondingQueueKey(release)).Uint64()
+	self.setSystemState(UnbondingAddress, unbondingQueueKey(release, queued), addr.Hash())
+	self.setUnbondingBig(unbondingQueueKey(release), new(big.Int).SetUint64(queued+1))
+}
+
+// UnbondingCount returns the number of pending entries of addr.
+func (self *StateDB) UnbondingCount(addr common.Address) uint64 {
+	return self.getUnbondingBig(unbondingKey("count", addr)).Uint64()
+}
+
+// SlashUnbonding takes cut(amount) from every pending entry of addr released
+// after block after and returns the sum taken. The entries stay queued.
+func (self *StateDB) SlashUnbonding(addr common.Address, after uint64, cut func(amount *big.Int) *big.Int) *big.Int {
+	slashed := new(big.Int)
+	for i, e := range self.GetUnbonding(addr) {
+		if e.Release <= after {
+			continue
+		}
+		part := cut(e.Amount)
+		if part.Sign() <= 0 {
+			continue
+		}
+		self.setUnbondingBig(unbondingKey("amount", addr, uint64(i)), new(big.Int).Sub(e.Amount, part))
+		slashed.Add(slashed, part)
+	}
+	if slashed.Sign() > 0 {
+		self.setUnbondingBig(unbondingKey("total", addr), new(big.Int).Sub(self.GetUnbondingBalance(addr), slashed))
+	}
+	return slashed
+}
+
+// ReleaseUnbonding credits every entry released at block to the balance of its
+// owner and clears the queue of the block.
+func (self *StateDB) ReleaseUnbonding(block uint64) {
+	queued := self.getUnbondingBig(unbondingQueueKey(block)).Uint64()
+	if queued == 0 {
+		return
+	}
+	for j := uint64(0); j < queued; j++ {
+		addr := common.BytesToAddress(self.GetState(UnbondingAddress, unbondingQueueKey(block, j)).Bytes())
+		self.releaseUnbonding(addr, block)
+		self.setSystemState(UnbondingAddress, unbondingQueueKey(block, j), common.Hash{})
+	}
+	self.setSystemState(UnbondingAddress, unbondingQueueKey(block), common.Hash{})
+}
+
+func (self *StateDB) releaseUnbonding(addr common.Address, block uint64) {
+	var (
+		entries  = self.GetUnbonding(addr)
+		kept     = entries[:0]
+		released = new(big.Int)
+	)
+	for _, e := range entries {
+		if e.Release <= block {
+			released.Add(released, e.Amount)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if released.Sign() == 0 {
+		return
+	}
+	for i, e := range kept {
+		self.setUnbondingBig(unbondingKey("amount", addr, uint64(i)), e.Amount)
+		self.setUnbondingBig(unbondingKey("block", addr, uint64(i)), new(big.Int).SetUint64(e.Release))
+	}
+	for i := uint64(len(kept)); i < uint64(len(entries)); i++ {
+		self.setSystemState(UnbondingAddress, unbondingKey("amount", addr, i), common.Hash{})
+		self.setSystemState(UnbondingAddress, unbondingKey("block", addr, i), common.Hash{})
+	}
+	self.setUnbondingBig(unbondingKey("count", addr), new(big.Int).SetUint64(uint64(len(kept))))
+	self.setUnbondingBig(unbondingKey("total", addr), new(big.Int).Sub(self.GetUnbondingBalance(addr), released))
+	self.AddBalance(addr, released)
+}
@@ -0,0 +1,173 @@
+package state
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+// VoteStakeAddress is the account whose storage keeps the whole AOA each voter
+// stakes on each candidate once voting is stake weighted. Layout:
+//
+//	keccak256("stake", voter, candidate)      -> stake of voter on candidate
+//	keccak256("staked", candidate)            -> sum of the recorded stakes on candidate
+//	keccak256("stakers", candidate)           -> number of voters with a recorded stake
+//	keccak256("staker", candidate, i)         -> i-th voter with a recorded stake
+//	keccak256("stakerIndex", candidate, voter) -> i+1 for the i-th voter
+//	keccak256("registration", delegate)       -> registration stake of delegate in wei
+//	keccak256("departed")                     -> number of delegates that unregistered with votes left
+//	keccak256("departedHead")                 -> index of the first of them still holding votes
+//	keccak256("departed", i)                  -> i-th of them
+//
+// Votes cast before stake weighting have no record and stake one AOA each.
+var VoteStakeAddress = common.StringToAddress("Vote Stake")
+
+func voteStakeKey(voter, candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("stake"), voter.Bytes(), candidate.Bytes())
+}
+
+func candidateStakedKey(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("staked"), candidate.Bytes())
+}
+
+func candidateStakersKey(candidate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("stakers"), candidate.Bytes())
+}
+
+func stakerKey(candidate common.Address, index uint64) common.Hash {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], index)
+	return crypto.Keccak256Hash([]byte("staker"), candidate.Bytes(), enc[:])
+}
+
+func stakerIndexKey(candidate, voter common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("stakerIndex"), candidate.Bytes(), voter.Bytes())
+}
+
+func registrationStakeKey(delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("registration"), delegate.Bytes())
+}
+
+var (
+	departedCountKey = crypto.Keccak256Hash([]byte("departed"))
+	departedHeadKey  = crypto.Keccak256Hash([]byte("departedHead"))
+)
+
+func departedKey(index uint64) common.Hash {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], index)
+	return crypto.Keccak256Hash([]byte("departed"), enc[:])
+}
+
+func (self *StateDB) getUint64(key common.Hash) uint64 {
+	return self.GetState(VoteStakeAddress, key).Big().Uint64()
+}
+
+func (self *StateDB) setUint64(key common.Hash, value uint64) {
+	self.setSystemState(VoteStakeAddress, key, common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+// GetVoteStake returns the whole AOA voter staked on candidate, or zero if no
+// stake was recorded.
+func (self *StateDB) GetVoteStake(voter, candidate common.Address) uint64 {
+	return self.getUint64(voteStakeKey(voter, candidate))
+}
+
+// SetVoteStake records the stake of voter on candidate and keeps the totals of
+// the candidate in step. A zero stake removes the record. The reward earned
+// with the previous stake is settled first.
+func (self *StateDB) SetVoteStake(voter, candidate common.Address, stake uint64) {
+	prev := self.GetVoteStake(voter, candidate)
+	self.settleReward(voter, candidate, prev)
+	self.setUint64(voteStakeKey(voter, candidate), stake)
+	self.setUint64(candidateStakedKey(candidate), self.getUint64(candidateStakedKey(candidate))-prev+stake)
+
+	stakers := self.getUint64(candidateStakersKey(candidate))
+	switch {
+	case prev == 0 && stake > 0:
+		self.setSystemState(VoteStakeAddress, stakerKey(candidate, stakers), voter.Hash())
+		self.setUint64(stakerIndexKey(candidate, voter), stakers+1)
+		stakers++
+	case prev > 0 && stake == 0:
+		// move the last staker into the slot of the removed one
+		index := self.getUint64(stakerIndexKey(candidate, voter)) - 1
+		last := self.GetState(VoteStakeAddress, stakerKey(candidate, stakers-1))
+		self.setSystemState(VoteStakeAddress, stakerKey(candidate, index), last)
+		self.setUint64(stakerIndexKey(candidate, common.BytesToAddress(last.Bytes())), index+1)
+		self.setSystemState(VoteStakeAddress, stakerKey(candidate, stakers-1), common.Hash{})
+		self.setSystemState(VoteStakeAddress, stakerIndexKey(candidate, voter), common.Hash{})
+		stakers--
+	}
+	self.setUint64(candidateStakersKey(candidate), stakers)
+}
+
+// CandidateStakerCount returns the number of voters with a recorded stake on
+// candidate.
+func (self *StateDB) CandidateStakerCount(candidate common.Address) uint64 {
+	return self.getUint64(candidateStakersKey(candidate))
+}
+
+// CandidateStakers returns at most max of the voters with a recorded stake on
+// candidate.
+func (self *StateDB) CandidateStakers(candidate common.Address, max uint64) []common.Address {
+	count := self.getUint64(candidateStakersKey(candidate))
+	if count > max {
+		count = max
+	}
+	stakers := make([]common.Address, 0, count)
+	for i := uint64(0); i < count; i++ {
+		stakers = append(stakers, common.BytesToAddress(self.GetState(VoteStakeAddress, stakerKey(candidate, i)).Bytes()))
+	}
+	return stakers
+}
+
+// GetRegistrationStake returns the stake delegate locked when registering, or
+// zero for delegates registered before the stake was locked.
+func (self *StateDB) GetRegistrationStake(delegate common.Address) *big.Int {
+	return self.GetState(VoteStakeAddress, registrationStakeKey(delegate)).Big()
+}
+
+func (self *StateDB) SetRegistrationStake(delegate common.Address, stake *big.Int) {
+	self.setSystemState(VoteStakeAddress, registrationStakeKey(delegate), common.BigToHash(stake))
+}
+
+// CandidateVoters returns the number of voters of a candidate whose tally in the
+// delegate state is weight. Every unrecorded AOA of the tally belongs to a vote
+// cast before stake weighting, i.e. to a distinct voter. Stake counted through
+// a proxy is recorded but not counted as voters.
+func (self *StateDB) CandidateVoters(candidate common.Address, weight uint64) uint64 {
+	staked := self.getUint64(candidateStakedKey(candidate)) + self.candidateProxyShared(candidate)
+	if staked > weight {
+		staked = weight
+	}
+	return weight - staked + self.getUint64(candidateStakersKey(candidate))
+}
+
+// QueueDeparted queues delegate, which unregistered while voters still had a
+// recorded stake on it, so that the stake is withdrawn in the next blocks.
+func (self *StateDB) QueueDeparted(delegate common.Address) {
+	count := self.getUint64(departedCountKey)
+	self.setSystemState(VoteStakeAddress, departedKey(count), delegate.Hash())
+	self.setUint64(departedCountKey, count+1)
+}
+
+// DepartedDelegate returns the first queued delegate, if any.
+func (self *StateDB) DepartedDelegate() (common.Address, bool) {
+	head := self.getUint64(departedHeadKey)
+	if head >= self.getUint64(departedCountKey) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(self.GetState(VoteStakeAddress, departedKey(head)).Bytes()), true
+}
+
+// PopDeparted removes the first queued delegate.
+func (self *StateDB) PopDeparted() {
+	head := self.getUint64(departedHeadKey)
+	if head >= self.getUint64(departedCountKey) {
+		return
+	}
+	self.setSystemState(VoteStakeAddress, departedKey(head), common.Hash{})
+	self.setUint64(departedHeadKey, head+1)
+}
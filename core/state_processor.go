@@ -38,6 +38,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		allLogs  []*types.Log
 		gp       = new(GasPool).AddGas(block.GasLimit())
 	)
+	if err := ApplyHermesFork(p.config, statedb, header); err != nil {
+		return nil, nil, 0, err
+	}
 
 	for i, tx := range block.Transactions() {
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
@@ -51,7 +54,16 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		allLogs = append(allLogs, receipt.Logs...)
 	}
 
+	ReleaseUnbonding(p.config, statedb, header)
+	ReleaseDepartedVotes(p.config, statedb, header)
+	if err := UpdateJail(p.config, p.bc, statedb, db, header); err != nil {
+		return nil, nil, 0, err
+	}
+	if err := ApplySeedReveal(p.config, statedb, header); err != nil {
+		return nil, nil, 0, err
+	}
 	p.engine.Finalize(p.bc, header, statedb, db, block.Transactions(), receipts)
+	ShareBlockReward(p.config, statedb, header)
 
 	return receipts, allLogs, *usedGas, nil
 }
@@ -63,6 +75,7 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	}
 
 	context := NewEVMContext(msg, header, bc, author)
+	context.JailedUntil = db.GetJailedUntil
 
 	vmenv := vm.NewEVM(context, statedb, config, cfg)
 	vmenv.WatchInnerTx = watchInnerTx
@@ -71,11 +84,16 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	if err != nil {
 		return nil, 0, err
 	}
-	err = voteChangeToDelegateState(msg.From(), tx, statedb, db, blockTime, header.Number.Int64())
+	err = voteChangeToDelegateState(msg.From(), tx, statedb, db, blockTime, header.Number.Int64(), config.IsAthena(header.Number))
 
 	if err != nil {
 		return nil, 0, err
 	}
+	// The state transition only checked the release round, the jail itself is
+	// kept in the delegate state.
+	if !failed && msg.Action() == types.ActionUnjail {
+		db.SetJailedUntil(msg.From(), 0)
+	}
 
 	var root []byte
 
@@ -108,10 +126,10 @@ func ApplyTransaction(config *params.ChainConfig, bc *BlockChain, author *common
 	return receipt, gas, err
 }
 
-func voteChangeToDelegateState(from common.Address, tx *types.Transaction, statedb *state.StateDB, db *delegatestate.DelegateDB, blockTime uint64, blockNumber int64) error {
+func voteChangeToDelegateState(from common.Address, tx *types.Transaction, statedb *state.StateDB, db *delegatestate.DelegateDB, blockTime uint64, blockNumber int64, stakeWeighted bool) error {
 
 	address := strings.ToLower(from.Hex())
-	candidates, err := CountTrxVote(address, tx, statedb, db)
+	candidates, err := CountTrxVote(address, tx, statedb, db, stakeWeighted)
 
 	if err != nil {
 		return err
@@ -2,7 +2,6 @@ package core
 
 import (
 	"errors"
-	"math"
 	"math/big"
 
 	"fmt"
@@ -64,48 +63,18 @@ type Message interface {
 	AssetInfo() types.AssetInfo
 	SubAddress() string
 	Abi() string
+	Window() *types.TxWindow
+	Payer() *common.Address
+	Multisig() *types.Multisig
+	HasExt() bool
 }
 
 func IntrinsicGas(data []byte, action uint64) (uint64, error) {
-
-	var gas uint64
-	switch action {
-	case types.ActionTrans:
-		gas = params.TxGas
-	case types.ActionRegister:
-
-		gas = params.TxGas
-	case types.ActionAddVote, types.ActionSubVote:
-		gas = params.TxGas
-	case types.ActionPublishAsset:
-		gas = params.TxGasAssetPublish
-	case types.ActionCreateContract:
-		gas = params.TxGasContractCreation
-	case types.ActionCallContract:
-		gas = params.TxGas
+	gas, err := types.IntrinsicGas(data, action)
+	if err == types.ErrGasUintOverflow {
+		return 0, vm.ErrOutOfGas
 	}
-
-	if len(data) > 0 {
-
-		var nz uint64
-		for _, byt := range data {
-			if byt != 0 {
-				nz++
-			}
-		}
-
-		if (math.MaxUint64-gas)/params.TxDataNonZeroGas < nz {
-			return 0, vm.ErrOutOfGas
-		}
-		gas += nz * params.TxDataNonZeroGas
-
-		z := uint64(len(data)) - nz
-		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
-			return 0, vm.ErrOutOfGas
-		}
-		gas += z * params.TxDataZeroGas
-	}
-	return gas, nil
+	return gas, err
 }
 
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
@@ -157,13 +126,26 @@ func (st *StateTransition) useGas(amount uint64) error {
 	return nil
 }
 
+// gasPayer returns the account buying the gas: the fee payer of a sponsored
+// message, the sender otherwise.
+func (st *StateTransition) gasPayer() vm.AccountRef {
+	if payer := st.msg.Payer(); payer != nil {
+		return vm.AccountRef(*payer)
+	}
+	return st.from()
+}
+
 func (st *StateTransition) buyGas() error {
 	var (
-		state  = st.state
-		sender = st.from()
+		state = st.state
+		payer = st.gasPayer()
 	)
+	// a fee payer has to exist, it is not created to buy gas
+	if st.msg.Payer() != nil && !state.Exist(payer.Address()) {
+		return errInsufficientBalanceForGas
+	}
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if state.GetBalance(sender.Address()).Cmp(mgval) < 0 {
+	if state.GetBalance(payer.Address()).Cmp(mgval) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -172,15 +154,64 @@ func (st *StateTransition) buyGas() error {
 	st.gas += st.msg.Gas()
 
 	st.initialGas = st.msg.Gas()
-	state.SubBalance(sender.Address(), mgval)
+	state.SubBalance(payer.Address(), mgval)
+	return nil
+}
+
+// actionFork returns the block the action is enabled from, nil if it is unknown
+// or its fork is not scheduled.
+func actionFork(config *params.ChainConfig, action uint64) *big.Int {
+	switch action {
+	case types.ActionTrans, types.ActionRegister, types.ActionAddVote, types.ActionSubVote,
+		types.ActionPublishAsset, types.ActionCreateContract, types.ActionCallContract:
+		return common.Big0
+	case types.ActionMintAsset, types.ActionBurnAsset, types.ActionBatchTransfer:
+		return config.HermesBlock
+	case types.ActionUnregister, types.ActionSetCommission, types.ActionClaimRewards, types.ActionReleaseVotes:
+		return config.AthenaBlock
+	case types.ActionUnjail, types.ActionDoubleSignEvidence:
+		return config.ArtemisBlock
+	case types.ActionSetProducer:
+		return config.HephaestusBlock
+	case types.ActionSetProxy, types.ActionRevokeProxy:
+		return config.HeraBlock
+	case types.ActionCreateMultisig:
+		return config.HestiaBlock
+	}
 	return nil
 }
 
 func (st *StateTransition) preCheck() error {
 
-	if st.msg.Action() > types.ActionCallContract {
+	if fork := actionFork(st.evm.ChainConfig(), st.msg.Action()); fork == nil || fork.Cmp(st.evm.BlockNumber) > 0 {
 		return fmt.Errorf("Illegal action: %d", st.msg.Action())
 	}
+	if st.msg.HasExt() && !st.evm.ChainConfig().IsTxExt(st.evm.BlockNumber) {
+		return ErrTxExtDisabled
+	}
+	if window := st.msg.Window(); window != nil {
+		if !st.evm.ChainConfig().IsDemeter(st.evm.BlockNumber) {
+			return ErrTxWindowDisabled
+		}
+		number, time := st.evm.BlockNumber.Uint64(), st.evm.Time.Uint64()
+		if !window.Opened(number, time) {
+			return ErrTxNotYetValid
+		}
+		if window.Expired(number, time) {
+			return ErrTxExpired
+		}
+	}
+	if st.msg.Payer() != nil && !st.evm.ChainConfig().IsDionysus(st.evm.BlockNumber) {
+		return ErrFeePayerDisabled
+	}
+	if st.msg.Multisig() != nil {
+		if !st.evm.ChainConfig().IsHestia(st.evm.BlockNumber) {
+			return ErrMultisigDisabled
+		}
+		if threshold, _ := st.state.GetMultisig(st.msg.From()); threshold == 0 {
+			return ErrUnknownMultisig
+		}
+	}
 
 	msg := st.msg
 	sender := st.from()
@@ -208,12 +239,23 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 	if err = st.useGas(gas); err != nil {
 		return nil, 0, false, err
 	}
+	if m := msg.Multisig(); m != nil {
+		if err = st.useGas(types.MultisigGas(m)); err != nil {
+			return nil, 0, false, err
+		}
+	}
 
 	var (
 		evm = st.evm
 
 		vmerr error
 	)
+	// the votes left by Unregister have to go before the delegate comes back
+	if msg.Action() == types.ActionRegister && st.state.CandidateStakerCount(sender.Address()) > 0 {
+		return nil, 0, false, ErrVotesWithdrawing
+	}
+	// native actions run outside the EVM, see runNativeAction
+	var native func() error
 	switch msg.Action() {
 	case types.ActionCreateContract:
 		if len(st.data) == 0 {
@@ -231,10 +273,51 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 			log.Error("PublishAsset error", "from", st.from().Address().String(), "err", err)
 			return nil, 0, true, err
 		}
+	case types.ActionMintAsset, types.ActionBurnAsset:
+		native = st.issueAsset
+	case types.ActionBatchTransfer:
+		native = st.batchTransfer
+	case types.ActionUnregister:
+		native = st.unregister
+	case types.ActionSetCommission:
+		native = st.setCommission
+	case types.ActionClaimRewards:
+		native = st.claimRewards
+	case types.ActionUnjail:
+		native = st.unjail
+	case types.ActionDoubleSignEvidence:
+		native = st.submitEvidence
+	case types.ActionSetProducer:
+		native = st.setProducer
+	case types.ActionSetProxy:
+		native = st.setProxy
+	case types.ActionRevokeProxy:
+		native = st.revokeProxy
+	case types.ActionCreateMultisig:
+		native = st.createMultisig
+	case types.ActionReleaseVotes:
+		native = st.releaseVotes
 	default:
-
+		// the stake proxied to a voter follows its vote list
+		proxied := (msg.Action() == types.ActionAddVote || msg.Action() == types.ActionSubVote) && st.state.ProxiedStake(sender.Address()) > 0
+		if proxied {
+			if err = st.useGas(ProxySyncGas(st.state, sender.Address(), len(msg.Vote()))); err != nil {
+				return nil, 0, false, err
+			}
+		}
 		st.state.SetNonce(sender.Address(), st.state.GetNonce(sender.Address())+1)
+		// the vote rule follows the chain config the EVM runs with, not the
+		// chain context it was built from
+		evm.Vote = voteFunc(evm.ChainConfig(), evm.BlockNumber)
 		ret, st.gas, vmerr = evm.Call(sender, st.to().Address(), st.data, st.gas, msg.Action(), st.value, msg.Vote(), msg.Asset())
+		if proxied && vmerr == nil {
+			SyncProxy(st.state, sender.Address(), delegateCounts(*evm.DelegateList), evm.BlockNumber.Uint64())
+		}
+	}
+	if native != nil {
+		if err = st.runNativeAction(native); err != nil {
+			return nil, 0, true, err
+		}
 	}
 	if vmerr != nil {
 		log.Info("VM returned with error", "err", vmerr)
@@ -247,12 +330,42 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, failed bo
 		}
 	}
 
+	// votes on candidates that left the delegates are released once their voter
+	// changes its votes or its stake, ActionReleaseVotes releases those of others
+	switch msg.Action() {
+	case types.ActionAddVote, types.ActionSubVote, types.ActionSetProxy, types.ActionRevokeProxy:
+		if evm.ChainConfig().IsAthena(evm.BlockNumber) && evm.DelegateList != nil {
+			ReleaseStaleVotes(st.state, sender.Address(), *evm.DelegateList, unbondingRelease(evm.ChainConfig(), evm.BlockNumber))
+		}
+	}
+
 	st.refundGas()
-	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice)
+	st.state.AddBalance(st.evm.Coinbase, fee)
+	// the voters of the producer share the fee as soon as it is paid. The
+	// coinbase is both: it is the account of the delegate, a bound producer key
+	// only signs for it, see VerifyBlockSignature, and it was paid the fee.
+	if evm.ChainConfig().IsAthena(evm.BlockNumber) {
+		st.state.ShareReward(st.evm.Coinbase, st.evm.Coinbase, fee)
+	}
 
 	return ret, st.gasUsed(), vmerr != nil, err
 }
 
+// runNativeAction applies a native action and bumps the sender nonce. If the
+// action fails its changes are reverted and the nonce is left as it is.
+func (st *StateTransition) runNativeAction(apply func() error) error {
+	snapshot := st.state.Snapshot()
+	if err := apply(); err != nil {
+		st.state.RevertToSnapshot(snapshot, st.evm.ChainConfig().IsEpiphron(st.evm.BlockNumber))
+		log.Debug("Native action error", "from", st.from().Address().String(), "action", st.msg.Action(), "err", err)
+		return err
+	}
+	sender := st.from().Address()
+	st.state.SetNonce(sender, st.state.GetNonce(sender)+1)
+	return nil
+}
+
 func (st *StateTransition) refundGas() {
 
 	refund := st.gasUsed() / 2
@@ -261,10 +374,10 @@ func (st *StateTransition) refundGas() {
 	}
 	st.gas += refund
 
-	sender := st.from()
+	payer := st.gasPayer()
 
 	remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
-	st.state.AddBalance(sender.Address(), remaining)
+	st.state.AddBalance(payer.Address(), remaining)
 
 	st.gp.AddGas(st.gas)
 }
@@ -275,5 +388,243 @@ func (st *StateTransition) gasUsed() uint64 {
 
 func (st *StateTransition) publishAsset() error {
 	ai := st.msg.AssetInfo()
-	return st.state.PublishAsset(st.from().Address(), ai)
+	return st.state.PublishAsset(st.from().Address(), ai, st.evm.ChainConfig().IsHermes(st.evm.BlockNumber))
+}
+
+func (st *StateTransition) issueAsset() error {
+	asset := st.msg.Asset()
+	if asset == nil {
+		return errors.New("asset can not be nil")
+	}
+	if st.msg.Action() == types.ActionMintAsset {
+		if st.msg.To() == nil {
+			return ErrMintRecipient
+		}
+		return st.state.MintAsset(st.from().Address(), *asset, st.to().Address(), st.value)
+	}
+	return st.state.BurnAsset(st.from().Address(), *asset, st.value)
+}
+
+// batchTransfer applies every leg of a batch transfer. The caller reverts the state
+// if any leg fails so the batch is all-or-nothing.
+func (st *StateTransition) batchTransfer() error {
+	transfers, err := checkBatchTransfer(st.value, st.data)
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	for i, t := range transfers {
+		if !st.evm.CanTransfer(st.state, from, t.Asset, t.Amount) {
+			return fmt.Errorf("transfer %d: %v", i, vm.ErrInsufficientBalance)
+		}
+		st.evm.Transfer(st.state, from, t.To, t.Asset, t.Amount)
+		st.evm.RecordInnerTx(from, t.To, t.Asset, t.Amount)
+	}
+	return nil
+}
+
+// unregister removes the sender from the delegates. Every voter Unregister
+// withdraws at once costs params.UnregisterVoterGas.
+func (st *StateTransition) unregister() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	var jailedUntil uint64
+	if st.evm.JailedUntil != nil {
+		jailedUntil = st.evm.JailedUntil(from)
+	}
+	if err := checkUnregister(delegates, from, st.value, jailedUntil); err != nil {
+		return err
+	}
+	if err := st.useGas(UnregisterGas(st.state, from)); err != nil {
+		return err
+	}
+	Unregister(st.state, from, unbondingRelease(st.evm.ChainConfig(), st.evm.BlockNumber))
+	return st.recountProxies(from)
+}
+
+func (st *StateTransition) setCommission() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	commission, err := checkSetCommission(delegates, from, st.value, st.data)
+	if err != nil {
+		return err
+	}
+	st.state.SetCommission(from, commission)
+	return nil
+}
+
+// unjail checks that the jail period of the sender has passed. ApplyTransaction
+// releases it in the delegate state once the transaction succeeds.
+func (st *StateTransition) unjail() error {
+	if st.evm.JailedUntil == nil {
+		return errors.New("jail state is unavailable")
+	}
+	return checkUnjail(st.state, st.value, st.evm.JailedUntil(st.from().Address()))
+}
+
+// submitEvidence slashes the delegate that signed both headers of the evidence
+// and pays the sender its reward, also after the delegate left.
+func (st *StateTransition) submitEvidence() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	evidence, offender, err := checkDoubleSignEvidence(st.evm.ChainConfig(), st.state, from, st.value, st.data, st.evm.BlockNumber, st.evm.GetHash)
+	if err != nil {
+		return err
+	}
+	_, registered := delegates[offender]
+	if err := st.useGas(EvidenceGas(st.state, offender, registered)); err != nil {
+		return err
+	}
+	st.state.AddEvidence(offender, evidence.Number(), st.evm.BlockNumber.Uint64())
+	Slash(st.state, st.evm.ChainConfig(), st.evm.BlockNumber, offender, from, evidence.Number())
+	if !registered {
+		return nil
+	}
+	Unregister(st.state, offender, unbondingRelease(st.evm.ChainConfig(), st.evm.BlockNumber))
+	return st.recountProxies(offender)
+}
+
+// setProducer binds the key in the payload as the block signing key of the
+// sender from the next block on.
+func (st *StateTransition) setProducer() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	number := st.evm.BlockNumber.Uint64()
+	proof, err := checkSetProducer(st.state, delegates, from, st.value, st.data, st.evm.ChainConfig().ChainId, number)
+	if err != nil {
+		return err
+	}
+	st.state.BindProducer(from, proof.Producer, number)
+	return nil
+}
+
+// setProxy locks the value as stake of the sender voting with the vote list of
+// the recipient. Nominating the same proxy again adds to the stake.
+func (st *StateTransition) setProxy() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	units, err := checkSetProxy(st.state, from, st.msg.To(), st.value)
+	if err != nil {
+		return err
+	}
+	proxy := *st.msg.To()
+	if !st.evm.CanTransfer(st.state, from, nil, st.value) {
+		return vm.ErrInsufficientBalance
+	}
+	if err := st.useGas(ProxySyncGas(st.state, proxy, 0)); err != nil {
+		return err
+	}
+	_, stake := st.state.GetProxy(from)
+	st.state.SubBalance(from, st.value)
+	st.state.AddLockBalance(from, st.value)
+	st.state.SetProxy(from, proxy, stake+units)
+	SyncProxy(st.state, proxy, delegateCounts(delegates), st.evm.BlockNumber.Uint64())
+	return nil
+}
+
+// revokeProxy unlocks the stake the sender proxied. It is unbonding as withdrawn
+// votes are.
+func (st *StateTransition) revokeProxy() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	from := st.from().Address()
+	proxy, stake, err := checkRevokeProxy(st.state, from, st.value)
+	if err != nil {
+		return err
+	}
+	if err := st.useGas(ProxySyncGas(st.state, proxy, 0)); err != nil {
+		return err
+	}
+	st.state.SetProxy(from, common.Address{}, 0)
+	amount := new(big.Int).Mul(new(big.Int).SetUint64(stake), big.NewInt(params.Aoa))
+	unlockStake(st.state, from, amount, unbondingRelease(st.evm.ChainConfig(), st.evm.BlockNumber))
+	SyncProxy(st.state, proxy, delegateCounts(delegates), st.evm.BlockNumber.Uint64())
+	return nil
+}
+
+// createMultisig records the multisig account in the payload, which may send
+// transactions from then on.
+func (st *StateTransition) createMultisig() error {
+	m, err := checkCreateMultisig(st.state, st.msg.To(), st.value, st.data)
+	if err != nil {
+		return err
+	}
+	account := m.Address()
+	if !st.state.Exist(account) {
+		st.state.CreateAccount(account)
+	}
+	st.state.SetMultisig(account, m.Threshold, m.Owners)
+	return nil
+}
+
+// delegateList returns the delegates the EVM context was built with.
+func (st *StateTransition) delegateList() (map[common.Address]types.Candidate, error) {
+	if st.evm.DelegateList == nil {
+		return nil, ErrNoDelegateList
+	}
+	return *st.evm.DelegateList, nil
+}
+
+// recountProxies moves the shares the proxies had on delegate, which left the
+// delegates, to the rest of their vote lists.
+func (st *StateTransition) recountProxies(delegate common.Address) error {
+	counts := delegateCounts(*st.evm.DelegateList, delegate)
+	for _, proxy := range st.state.CandidateProxies(delegate) {
+		if err := st.useGas(ProxySyncGas(st.state, proxy, 0)); err != nil {
+			return err
+		}
+		SyncProxy(st.state, proxy, counts, st.evm.BlockNumber.Uint64())
+	}
+	return nil
+}
+
+// releaseVotes drops the stale votes of the voters in the payload, each at
+// params.UnregisterVoterGas.
+func (st *StateTransition) releaseVotes() error {
+	delegates, err := st.delegateList()
+	if err != nil {
+		return err
+	}
+	voters, gas, err := checkReleaseVotes(st.state, delegates, st.value, st.data)
+	if err != nil {
+		return err
+	}
+	if err := st.useGas(gas); err != nil {
+		return err
+	}
+	release := unbondingRelease(st.evm.ChainConfig(), st.evm.BlockNumber)
+	for _, voter := range voters {
+		ReleaseStaleVotes(st.state, voter, delegates, release)
+	}
+	return nil
+}
+
+func (st *StateTransition) claimRewards() error {
+	from := st.from().Address()
+	voteList, err := checkClaimRewards(st.state, from, st.value)
+	if err != nil {
+		return err
+	}
+	if err := st.useGas(uint64(len(voteList)) * params.ClaimRewardGas); err != nil {
+		return err
+	}
+	st.state.ClaimRewards(from, voteList)
+	return nil
 }
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// Tests that every action is enabled by its own fork and not by the order of
+// the action numbers.
+func TestActionFork(t *testing.T) {
+	config := &params.ChainConfig{HermesBlock: big.NewInt(10), AthenaBlock: big.NewInt(20), HestiaBlock: big.NewInt(30)}
+	enabled := func(action uint64, number int64) bool {
+		fork := actionFork(config, action)
+		return fork != nil && fork.Cmp(big.NewInt(number)) <= 0
+	}
+	tests := []struct {
+		action  uint64
+		number  int64
+		enabled bool
+	}{
+		{types.ActionTrans, 0, true},
+		{types.ActionMintAsset, 9, false},
+		{types.ActionMintAsset, 10, true},
+		{types.ActionUnregister, 19, false},
+		{types.ActionUnregister, 20, true},
+		{types.ActionReleaseVotes, 19, false},
+		{types.ActionReleaseVotes, 20, true},
+		{types.ActionCreateMultisig, 20, false},
+		{types.ActionCreateMultisig, 30, true},
+		{types.ActionSetProxy, 30, false}, // Hera is not scheduled
+		{types.ActionReleaseVotes + 1, 30, false},
+	}
+	for i, tt := range tests {
+		if have := enabled(tt.action, tt.number); have != tt.enabled {
+			t.Errorf("test %d: action %d at block %d enabled mismatch: have %v, want %v", i, tt.action, tt.number, have, tt.enabled)
+		}
+	}
+}
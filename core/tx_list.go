@@ -134,14 +134,16 @@ func (m *txSortedMap) Remove(nonce uint64) bool {
 	return true
 }
 
-func (m *txSortedMap) Ready(start uint64) types.Transactions {
+// Ready removes and returns the sequence of transactions from start on,
+// stopping at the first one opened reports false for.
+func (m *txSortedMap) Ready(start uint64, opened func(*types.Transaction) bool) types.Transactions {
 
 	if m.index.Len() == 0 || (*m.index)[0] > start {
 		return nil
 	}
 
 	var ready types.Transactions
-	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next; next++ {
+	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next && opened(m.items[next]); next++ {
 		ready = append(ready, m.items[next])
 		delete(m.items, next)
 		heap.Pop(m.index)
@@ -225,19 +227,32 @@ func (l *txList) Filter(costLimit *big.Int, gasLimit uint64) (types.Transactions
 	l.gascap = gasLimit
 
 	removed := l.txs.Filter(func(tx *types.Transaction) bool { return tx.AoaCost().Cmp(costLimit) > 0 || tx.Gas() > gasLimit })
+	return removed, l.invalidate(removed)
+}
 
-	var invalids types.Transactions
+// Expire removes the transactions whose window has expired at a block with the
+// given number and time, and in a strict list the ones after them.
+func (l *txList) Expire(number, time uint64) (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		window := tx.Window()
+		return window != nil && window.Expired(number, time)
+	})
+	return removed, l.invalidate(removed)
+}
 
-	if l.strict && len(removed) > 0 {
-		lowest := uint64(math.MaxUint64)
-		for _, tx := range removed {
-			if nonce := tx.Nonce(); lowest > nonce {
-				lowest = nonce
-			}
+// invalidate removes the transactions a strict list can not execute anymore
+// once removed are gone.
+func (l *txList) invalidate(removed types.Transactions) types.Transactions {
+	if !l.strict || len(removed) == 0 {
+		return nil
+	}
+	lowest := uint64(math.MaxUint64)
+	for _, tx := range removed {
+		if nonce := tx.Nonce(); lowest > nonce {
+			lowest = nonce
 		}
-		invalids = l.txs.Filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
 	}
-	return removed, invalids
+	return l.txs.Filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
 }
 
 func (l *txList) Cap(threshold int) types.Transactions {
@@ -257,8 +272,13 @@ func (l *txList) Remove(tx *types.Transaction) (bool, types.Transactions) {
 	return true, nil
 }
 
-func (l *txList) Ready(start uint64) types.Transactions {
-	return l.txs.Ready(start)
+// Ready removes and returns the sequence of transactions from start on that a
+// block with the given number and time may include.
+func (l *txList) Ready(start, number, time uint64) types.Transactions {
+	return l.txs.Ready(start, func(tx *types.Transaction) bool {
+		window := tx.Window()
+		return window == nil || window.Opened(number, time)
+	})
 }
 
 func (l *txList) Len() int {
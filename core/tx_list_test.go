@@ -1 +1,44 @@
 package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+func windowTx(nonce uint64, window *types.TxWindow) *types.Transaction {
+	tx, _ := types.NewTransferTx(types.TxOptions{Nonce: nonce, GasPrice: big.NewInt(1), Window: window}, common.Address{1}, big.NewInt(1), "")
+	return tx
+}
+
+func TestTxListWindows(t *testing.T) {
+	// expiring a pending transaction strands the ones after it
+	pending := newTxList(true)
+	pending.Add(windowTx(0, nil), 10)
+	pending.Add(windowTx(1, &types.TxWindow{ExpiresAt: 5}), 10)
+	pending.Add(windowTx(2, nil), 10)
+	if removed, invalids := pending.Expire(4, 0); len(removed) != 0 || len(invalids) != 0 {
+		t.Fatalf("expired before the window closed: %d removed, %d invalid", len(removed), len(invalids))
+	}
+	removed, invalids := pending.Expire(5, 0)
+	if len(removed) != 1 || removed[0].Nonce() != 1 {
+		t.Errorf("removed mismatch: have %d transactions, want nonce 1", len(removed))
+	}
+	if len(invalids) != 1 || invalids[0].Nonce() != 2 {
+		t.Errorf("invalids mismatch: have %d transactions, want nonce 2", len(invalids))
+	}
+
+	// queued transactions are promoted up to the first one not open yet
+	queue := newTxList(false)
+	queue.Add(windowTx(0, nil), 10)
+	queue.Add(windowTx(1, &types.TxWindow{NotBefore: 100, ByTime: true}), 10)
+	queue.Add(windowTx(2, nil), 10)
+	if ready := queue.Ready(0, 1, 99); len(ready) != 1 {
+		t.Errorf("ready mismatch: have %d transactions, want 1", len(ready))
+	}
+	if ready := queue.Ready(1, 1, 100); len(ready) != 2 {
+		t.Errorf("ready mismatch: have %d transactions, want 2", len(ready))
+	}
+}
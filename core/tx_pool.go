@@ -35,22 +35,50 @@ var (
 
 	ErrInvalidSender = errors.New("invalid sender")
 
+	ErrInvalidPayer = errors.New("invalid fee payer")
+
 	ErrFullPending = errors.New("transaction pool is full")
 
 	ErrVoteList = errors.New("Vote member not in delegate poll.")
 
+	ErrVoteAmount = errors.New("vote amount does not match the transaction value")
+
 	ErrNonceTooLow = errors.New("nonce too low")
 
 	ErrNickName = errors.New("Deligate nickname error")
 
 	ErrRegister = errors.New("already register delegate.")
 
+	ErrUnregister = errors.New("not a registered delegate")
+
+	ErrVotesWithdrawing = errors.New("votes of the previous registration are not withdrawn yet")
+
+	ErrNoRewards = errors.New("no rewards to claim")
+
+	ErrNoStaleVotes = errors.New("no stale votes to release")
+
+	ErrNotJailed = errors.New("delegate is not jailed")
+
+	ErrJailed = errors.New("delegate is jailed")
+
+	ErrDuplicateEvidence = errors.New("evidence already submitted")
+
+	ErrProducerBound = errors.New("producer key is bound to another delegate")
+
+	ErrProxySet = errors.New("account has a proxy already")
+
+	ErrNoProxy = errors.New("account has no proxy")
+
+	ErrProxyChain = errors.New("proxies can not be chained")
+
 	ErrUnderpriced = errors.New("transaction underpriced")
 
 	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
 
 	ErrInsufficientFunds = errors.New("insufficient funds of AOA")
 
+	ErrInsufficientPayerFunds = errors.New("insufficient funds of fee payer for gas")
+
 	ErrInsufficientAssetFunds = errors.New("insufficient funds of asset for transfer")
 
 	ErrIntrinsicGas = errors.New("intrinsic gas too low")
@@ -60,6 +88,12 @@ var (
 	ErrNegativeValue = errors.New("negative value")
 
 	ErrOversizedData = errors.New("oversized data")
+
+	ErrAssetNil = errors.New("asset can not be nil")
+
+	ErrAssetAmount = errors.New("asset amount must be greater then 0")
+
+	ErrMintRecipient = errors.New("mint recipient can not be nil")
 )
 
 var (
@@ -73,14 +107,17 @@ var (
 	pendingReplaceCounter   = metrics.NewCounter("txpool/pending/replace")
 	pendingRateLimitCounter = metrics.NewCounter("txpool/pending/ratelimit")
 	pendingNofundsCounter   = metrics.NewCounter("txpool/pending/nofunds")
+	pendingExpiredCounter   = metrics.NewCounter("txpool/pending/expired")
 
 	queuedDiscardCounter   = metrics.NewCounter("txpool/queued/discard")
 	queuedReplaceCounter   = metrics.NewCounter("txpool/queued/replace")
 	queuedRateLimitCounter = metrics.NewCounter("txpool/queued/ratelimit")
 	queuedNofundsCounter   = metrics.NewCounter("txpool/queued/nofunds")
+	queuedExpiredCounter   = metrics.NewCounter("txpool/queued/expired")
 
 	invalidTxCounter     = metrics.NewCounter("txpool/invalid")
 	underpricedTxCounter = metrics.NewCounter("txpool/underpriced")
+	payerNofundsCounter  = metrics.NewCounter("txpool/payer/nofunds")
 
 )
 
@@ -96,6 +133,7 @@ const (
 type blockChain interface {
 	CurrentBlock() *types.Block
 	GetBlock(hash common.Hash, number uint64) *types.Block
+	GetHeader(hash common.Hash, number uint64) *types.Header
 	StateAt(root common.Hash) (*state.StateDB, error)
 	DelegateStateAt(root common.Hash) (*delegatestate.DelegateDB, error)
 	GetDelegatePoll() (*map[common.Address]types.Candidate, error)
@@ -117,6 +155,10 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64
 
 	Lifetime time.Duration
+
+	Schedule        string
+	ScheduleWeights map[string]uint64
+	TypeCap         uint64
 }
 
 var DefaultTxPoolConfig = TxPoolConfig{
@@ -132,6 +174,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  10000,
 
 	Lifetime: 30 * time.Minute,
+
+	Schedule: ScheduleProportional,
 }
 
 func (config *TxPoolConfig) sanitize() TxPoolConfig {
@@ -148,6 +192,12 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
 		conf.PriceBump = DefaultTxPoolConfig.PriceBump
 	}
+	switch conf.Schedule {
+	case ScheduleProportional, ScheduleStrictPrice, ScheduleFair:
+	default:
+		log.Warn("Sanitizing invalid txpool schedule", "provided", conf.Schedule, "updated", DefaultTxPoolConfig.Schedule)
+		conf.Schedule = DefaultTxPoolConfig.Schedule
+	}
 	return conf
 }
 
@@ -163,9 +213,10 @@ type TxPool struct {
 	signer       types.Signer
 	mu           sync.RWMutex
 
-	currentState  *state.StateDB
-	pendingState  *state.ManagedState
-	currentMaxGas uint64
+	currentState     *state.StateDB
+	currentDelegates *delegatestate.DelegateDB
+	pendingState     *state.ManagedState
+	currentMaxGas    uint64
 
 	locals  *accountSet
 	journal *txJournal
@@ -176,6 +227,10 @@ type TxPool struct {
 	all     map[common.Hash]*types.Transaction
 	priced  *txTypeList
 
+	payerCosts map[common.Address]*big.Int // what every fee payer buys for its transactions in all
+
+	schedule TxSchedulePolicy
+
 	wg sync.WaitGroup
 
 	homestead bool
@@ -197,8 +252,10 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         make(map[common.Hash]*types.Transaction),
+		payerCosts:  make(map[common.Address]*big.Int),
 		chainHeadCh: make(chan ChainHeadEvent, chainHeadChanSize),
 		gasPrice:    new(big.Int).SetUint64(config.PriceLimit),
+		schedule:    newTxSchedulePolicy(&config),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	pool.priced = newTxTypeList(&pool.all)
@@ -369,7 +426,13 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 		log.Error("Failed to reset txpool state", "err", err)
 		return
 	}
+	delegatedb, err := pool.chain.DelegateStateAt(newHead.DelegateRoot)
+	if err != nil {
+		log.Error("Failed to reset txpool delegate state", "err", err)
+		return
+	}
 	pool.currentState = statedb
+	pool.currentDelegates = delegatedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
 
@@ -377,6 +440,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.addTxsLocked(reinject, false)
 
 	pool.demoteUnexecutables()
+	pool.filterPayers()
 
 	for addr, list := range pool.pending {
 		txs := list.Flatten()
@@ -488,68 +552,67 @@ func (pool *TxPool) Pending() (map[common.Address]types.Transactions, error) {
 	return pending, nil
 }
 
+// PendingTxsByPrice returns the transactions to offer for the next block, as
+// the scheduling policy of the pool splits the block space between the
+// transaction types.
 func (pool *TxPool) PendingTxsByPrice() (types.TxByPrice, error) {
 
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	var wg sync.WaitGroup
-
-	wholeTxNum := len(pool.all)
-	estimateTxNum := pool.currentMaxGas / params.TxGas
-	price := make(types.TxByPrice, 0, estimateTxNum)
-	contractNum := pool.priced.items.ContractNum()
-
-	wg.Add(len(*pool.priced.items))
-	for index, txTypeList := range *pool.priced.items {
-		txGasList := *txTypeList.value
-		txTypeWholeNum := int64(txGasList.Len())
-
-		if txTypeWholeNum == 0 {
-			pool.priced.items.Remove(index)
-			wg.Done()
-			continue
-		}
-		conNum := uint64(contractNum)
-		if conNum > maxContractNum {
-			conNum = maxContractNum
-		}
-		es := uint64(int64(-1.8*float64(conNum)) + 10000)
-		wholeTrxNum := wholeTxNum
-		if txTypeList.contract {
-			es = conNum
-			wholeTrxNum = contractNum
-		} else {
-			wholeTrxNum = wholeTxNum - contractNum
+	for i := len(*pool.priced.items) - 1; i >= 0; i-- {
+		if (*pool.priced.items)[i].GetValueLen() == 0 {
+			pool.priced.items.Remove(i)
 		}
+	}
 
-		go func(txGasList types.TxByPrice, txLen int64, wholeTxNum int, estimateTxNum uint64, kv *KV) {
-			defer wg.Done()
-
-			txsLen := new(big.Float).SetInt64(txLen)
-			wTxNum := new(big.Float).SetFloat64(float64(1) / float64(wholeTxNum))
-			esTxNum := new(big.Float).SetInt64(int64(estimateTxNum))
-			txSuggestNum := big.NewFloat(0).Mul(txsLen.Mul(txsLen, wTxNum), esTxNum)
-			txTypeSuggestNum, _ := txSuggestNum.Int64()
-
-			if txTypeSuggestNum == 0 {
-				txTypeSuggestNum = 1
-			}
-			sortTxs := types.SortByPriceAndNonce(pool.signer, txGasList)
-			if txTypeSuggestNum > int64(len(sortTxs)) {
-				txTypeSuggestNum = int64(len(sortTxs))
-			}
+	var wg sync.WaitGroup
 
-			price = append(price, sortTxs[:txTypeSuggestNum]...)
-		}(txGasList, txTypeWholeNum, wholeTrxNum, es, txTypeList)
+	groups := make([]TxGroup, len(*pool.priced.items))
+	wg.Add(len(groups))
+	for i, txTypeList := range *pool.priced.items {
+		groups[i] = TxGroup{Type: txTypeList.key, Contract: txTypeList.contract, Pooled: uint64(txTypeList.GetValueLen())}
+		go func(group *TxGroup, txGasList types.TxByPrice) {
+			defer wg.Done()
+			group.Txs = types.SortByPriceAndNonce(pool.signer, txGasList)
+		}(&groups[i], *txTypeList.value)
 	}
 	wg.Wait()
+	capTxGroups(groups, pool.config.TypeCap)
 
+	counts := pool.schedule.Schedule(groups, uint64(len(pool.all)), pool.currentMaxGas/params.TxGas)
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	price := make(types.TxByPrice, 0, total)
+	for i, group := range groups {
+		price = append(price, group.Txs[:counts[i]]...)
+	}
 	heap.Init(&price)
 
 	return price, nil
 }
 
+// nextBlock returns the number of the block the pool collects transactions for
+// and the current time, as the time of that block.
+func (pool *TxPool) nextBlock() (uint64, uint64) {
+	return pool.chain.CurrentBlock().NumberU64() + 1, uint64(time.Now().Unix())
+}
+
+// headHashFn returns the hashes of the blocks of the current chain by number.
+func (pool *TxPool) headHashFn() func(uint64) common.Hash {
+	head := pool.chain.CurrentBlock().Header()
+	return func(n uint64) common.Hash {
+		for header := head; header != nil && header.Number.Uint64() >= n; header = pool.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1) {
+			if header.Number.Uint64() == n {
+				return header.Hash()
+			}
+		}
+		return common.Hash{}
+	}
+}
+
 func (pool *TxPool) PoolSigner() types.Signer {
 	return pool.signer
 }
@@ -569,9 +632,30 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 
-	if tx.TxDataAction() > types.ActionCallContract {
+	next := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+	if fork := actionFork(pool.chainconfig, tx.TxDataAction()); fork == nil || fork.Cmp(next) > 0 {
 		return fmt.Errorf("Illegal action: %d", tx.TxDataAction())
 	}
+	if tx.HasExt() && !pool.chainconfig.IsTxExt(next) {
+		return ErrTxExtDisabled
+	}
+	if window := tx.Window(); window != nil {
+		if !pool.chainconfig.IsDemeter(next) {
+			return ErrTxWindowDisabled
+		}
+		if err := window.Validate(); err != nil {
+			return err
+		}
+		if number, now := pool.nextBlock(); window.Expired(number, now) {
+			return ErrTxExpired
+		}
+	}
+	if tx.Payer() != nil && !pool.chainconfig.IsDionysus(next) {
+		return ErrFeePayerDisabled
+	}
+	if tx.Multisig() != nil && !pool.chainconfig.IsHestia(next) {
+		return ErrMultisigDisabled
+	}
 
 	if tx.Size() > 32*1024 {
 		return ErrOversizedData
@@ -601,6 +685,11 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetNonce(from) > tx.Nonce() {
 		return ErrNonceTooLow
 	}
+	if tx.Multisig() != nil {
+		if threshold, _ := pool.currentState.GetMultisig(from); threshold == 0 {
+			return ErrUnknownMultisig
+		}
+	}
 
 	cost := tx.AoaCost()
 	delegates, err := pool.chain.GetDelegatePoll()
@@ -616,6 +705,76 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		if _, ok := delegateList[from]; ok {
 			return ErrRegister
 		}
+		if pool.currentState.CandidateStakerCount(from) > 0 {
+			return ErrVotesWithdrawing
+		}
+	case types.ActionUnregister:
+		if err := checkUnregister(delegateList, from, tx.Value(), pool.currentDelegates.GetJailedUntil(from)); err != nil {
+			return err
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+UnregisterGas(pool.currentState, from)+ProxyRecountGas(pool.currentState, from) {
+			return ErrIntrinsicGas
+		}
+	case types.ActionSetCommission:
+		if _, err := checkSetCommission(delegateList, from, tx.Value(), tx.Data()); err != nil {
+			return err
+		}
+	case types.ActionClaimRewards:
+		voteList, err := checkClaimRewards(pool.currentState, from, tx.Value())
+		if err != nil {
+			return err
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+uint64(len(voteList))*params.ClaimRewardGas {
+			return ErrIntrinsicGas
+		}
+	case types.ActionUnjail:
+		if err := checkUnjail(pool.currentState, tx.Value(), pool.currentDelegates.GetJailedUntil(from)); err != nil {
+			return err
+		}
+	case types.ActionDoubleSignEvidence:
+		_, offender, err := checkDoubleSignEvidence(pool.chainconfig, pool.currentState, from, tx.Value(), tx.Data(), next, pool.headHashFn())
+		if err != nil {
+			return err
+		}
+		_, registered := delegateList[offender]
+		gas := EvidenceGas(pool.currentState, offender, registered)
+		if registered {
+			gas += ProxyRecountGas(pool.currentState, offender)
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+gas {
+			return ErrIntrinsicGas
+		}
+	case types.ActionSetProducer:
+		if _, err := checkSetProducer(pool.currentState, delegateList, from, tx.Value(), tx.Data(), pool.chainconfig.ChainId, next.Uint64()); err != nil {
+			return err
+		}
+	case types.ActionSetProxy:
+		if _, err := checkSetProxy(pool.currentState, from, tx.To(), tx.Value()); err != nil {
+			return err
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+ProxySyncGas(pool.currentState, *tx.To(), 0) {
+			return ErrIntrinsicGas
+		}
+	case types.ActionCreateMultisig:
+		if _, err := checkCreateMultisig(pool.currentState, tx.To(), tx.Value(), tx.Data()); err != nil {
+			return err
+		}
+	case types.ActionReleaseVotes:
+		_, gas, err := checkReleaseVotes(pool.currentState, delegateList, tx.Value(), tx.Data())
+		if err != nil {
+			return err
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+gas {
+			return ErrIntrinsicGas
+		}
+	case types.ActionRevokeProxy:
+		proxy, _, err := checkRevokeProxy(pool.currentState, from, tx.Value())
+		if err != nil {
+			return err
+		}
+		if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+ProxySyncGas(pool.currentState, proxy, 0) {
+			return ErrIntrinsicGas
+		}
 	case types.ActionAddVote, types.ActionSubVote:
 		var voteCost *big.Int
 		if tx.TxDataAction() == types.ActionAddVote {
@@ -630,6 +789,29 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		if len(votes) == 0 {
 			return errors.New("empty vote list")
 		}
+		if pool.chainconfig.IsAthena(next) {
+			voteList, _, diff, err := ChangeStakeVoteList(pool.currentState, from, votes, delegateList)
+			if err != nil {
+				return err
+			}
+			if int64(len(voteList)) > maxElectDelegate {
+				return errors.New(fmt.Sprintf("vote exceeds %d delegate", maxElectDelegate))
+			}
+			if new(big.Int).Abs(diff).Cmp(tx.Value()) != 0 {
+				return ErrVoteAmount
+			}
+			if pool.currentState.ProxiedStake(from) > 0 {
+				if intrGas, _ := IntrinsicGas(tx.Data(), tx.TxDataAction()); tx.Gas() < intrGas+ProxySyncGas(pool.currentState, from, len(votes)) {
+					return ErrIntrinsicGas
+				}
+			}
+			break
+		}
+		for _, vote := range votes {
+			if vote.Amount != nil {
+				return errors.New("vote amount is not supported before stake weighted voting")
+			}
+		}
 		err = validateVote(pool.currentState.GetVoteList(from), votes, delegateList)
 		if err != nil {
 			return err
@@ -640,7 +822,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			return errors.New(fmt.Sprintf("vote exceeds %d delegate", maxElectDelegate))
 		}
 	case types.ActionPublishAsset:
-		err = pool.currentState.ValidateAsset(*tx.AssetInfo())
+		err = pool.currentState.ValidateAsset(*tx.AssetInfo(), pool.chainconfig.IsHermes(next))
 		if err != nil {
 			log.Error("ValidateAsset failed.", "err", err)
 			return err
@@ -649,9 +831,44 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		if len(tx.Data()) == 0 {
 			return errors.New("Create contract but data is nil")
 		}
+	case types.ActionMintAsset, types.ActionBurnAsset:
+		a := tx.Asset()
+		if a == nil {
+			return ErrAssetNil
+		}
+		if tx.Value().Sign() == 0 {
+			return ErrAssetAmount
+		}
+		if tx.TxDataAction() == types.ActionMintAsset && tx.To() == nil {
+			return ErrMintRecipient
+		}
+		if _, err := pool.currentState.ValidateAssetIssuer(*a, from); err != nil {
+			return err
+		}
+	case types.ActionBatchTransfer:
+		transfers, err := checkBatchTransfer(tx.Value(), tx.Data())
+		if err != nil {
+			return err
+		}
+		assetCost := make(map[common.Address]*big.Int)
+		for _, t := range transfers {
+			if t.Asset == nil {
+				cost = new(big.Int).Add(cost, t.Amount)
+				continue
+			}
+			if assetCost[*t.Asset] == nil {
+				assetCost[*t.Asset] = new(big.Int)
+			}
+			assetCost[*t.Asset].Add(assetCost[*t.Asset], t.Amount)
+		}
+		for asset, amount := range assetCost {
+			if pool.currentState.GetAssetBalance(from, asset).Cmp(amount) < 0 {
+				return ErrInsufficientAssetFunds
+			}
+		}
 	}
 	a := tx.Asset()
-	if a != nil && (*a != common.Address{}) {
+	if a != nil && (*a != common.Address{}) && tx.TxDataAction() != types.ActionMintAsset {
 		if pool.currentState.GetAssetBalance(from, *a).Cmp(tx.Value()) < 0 {
 			return ErrInsufficientAssetFunds
 		}
@@ -659,10 +876,28 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetBalance(from).Cmp(cost) < 0 {
 		return ErrInsufficientFunds
 	}
+	if tx.Payer() != nil {
+		payer, err := types.PayerSender(pool.signer, tx)
+		if err != nil {
+			return ErrInvalidPayer
+		}
+		gasCost := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+		if payer == from {
+			gasCost.Add(gasCost, cost)
+		}
+		// the payer also buys its other transactions in the pool
+		gasCost.Add(gasCost, pool.payerCost(payer, from, tx.Nonce()))
+		if pool.currentState.GetBalance(payer).Cmp(gasCost) < 0 {
+			return ErrInsufficientPayerFunds
+		}
+	}
 	intrGas, err := IntrinsicGas(tx.Data(), tx.TxDataAction())
 	if err != nil {
 		return err
 	}
+	if m := tx.Multisig(); m != nil {
+		intrGas += types.MultisigGas(m)
+	}
 	if tx.Gas() < intrGas {
 		return ErrIntrinsicGas
 	}
@@ -748,11 +983,11 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 
 		if old != nil {
-			delete(pool.all, old.Hash())
+			pool.removeFromAll(old.Hash())
 			pool.priced.RemoveByHash(old.GetTransactionType(), old.Hash())
 			pendingReplaceCounter.Inc(1)
 		}
-		pool.all[tx.Hash()] = tx
+		pool.addToAll(tx)
 		pool.priced.Put(tx, pool.currentState)
 		pool.journalTx(from, tx)
 
@@ -782,7 +1017,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 
 func IsContractTransaction(tx *types.Transaction, db *state.StateDB) bool {
 	switch tx.TxDataAction() {
-	case types.ActionRegister, types.ActionAddVote, types.ActionSubVote, types.ActionPublishAsset:
+	case types.ActionRegister, types.ActionAddVote, types.ActionSubVote, types.ActionPublishAsset, types.ActionMintAsset, types.ActionBurnAsset, types.ActionBatchTransfer, types.ActionUnregister, types.ActionSetCommission, types.ActionClaimRewards, types.ActionUnjail, types.ActionDoubleSignEvidence, types.ActionSetProducer, types.ActionSetProxy, types.ActionRevokeProxy, types.ActionCreateMultisig, types.ActionReleaseVotes:
 		return false
 	case types.ActionCreateContract, types.ActionCallContract:
 		return true
@@ -810,11 +1045,11 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	}
 
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.removeFromAll(old.Hash())
 		pool.priced.RemoveByHash(old.GetTransactionType(), old.Hash())
 		queuedReplaceCounter.Inc(1)
 	}
-	pool.all[hash] = tx
+	pool.addToAll(tx)
 	pool.priced.Put(tx, pool.currentState)
 	return old != nil, nil
 }
@@ -839,7 +1074,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	inserted, old := list.Add(tx, pool.config.PriceBump)
 	if !inserted {
 
-		delete(pool.all, hash)
+		pool.removeFromAll(hash)
 		pool.priced.RemoveByHash(tx.GetTransactionType(), hash)
 
 		pendingDiscardCounter.Inc(1)
@@ -847,14 +1082,14 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	}
 
 	if old != nil {
-		delete(pool.all, old.Hash())
+		pool.removeFromAll(old.Hash())
 		pool.priced.RemoveByHash(old.GetTransactionType(), old.Hash())
 
 		pendingReplaceCounter.Inc(1)
 	}
 
 	if pool.all[hash] == nil {
-		pool.all[hash] = tx
+		pool.addToAll(tx)
 		pool.priced.Put(tx, pool.currentState)
 	}
 
@@ -961,7 +1196,7 @@ func (pool *TxPool) removeTx(hash common.Hash) {
 	}
 	addr, _ := types.Sender(pool.signer, tx)
 
-	delete(pool.all, hash)
+	pool.removeFromAll(hash)
 	_, x := pool.priced.items.Get(tx.GetTransactionType())
 	log.Debug("Before Remove Trx", "trxNum", x.Len(), "type", tx.GetTransactionType().Hex())
 	pool.priced.RemoveByHash(tx.GetTransactionType(), hash)
@@ -995,6 +1230,114 @@ func (pool *TxPool) removeTx(hash common.Hash) {
 	}
 }
 
+// PayerCharge returns what the fee payer of the sponsored tx buys for it: the
+// gas, and the AOA tx spends as well when the payer sends it itself.
+func PayerCharge(signer types.Signer, tx *types.Transaction) *big.Int {
+	charge := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	if payer := tx.Payer(); payer != nil {
+		if from, err := types.Sender(signer, tx); err == nil && from == *payer {
+			charge.Add(charge, tx.AoaCost())
+			if tx.TxDataAction() == types.ActionBatchTransfer {
+				transfers, _ := types.BytesToBatchTransfers(tx.Data())
+				for _, t := range transfers {
+					if t.Asset == nil {
+						charge.Add(charge, t.Amount)
+					}
+				}
+			}
+		}
+	}
+	return charge
+}
+
+// addToAll adds tx to all and to the cost of its fee payer.
+func (pool *TxPool) addToAll(tx *types.Transaction) {
+	pool.all[tx.Hash()] = tx
+	if payer := tx.Payer(); payer != nil {
+		if pool.payerCosts[*payer] == nil {
+			pool.payerCosts[*payer] = new(big.Int)
+		}
+		pool.payerCosts[*payer].Add(pool.payerCosts[*payer], PayerCharge(pool.signer, tx))
+	}
+}
+
+// removeFromAll removes the transaction with hash from all and from the cost of
+// its fee payer.
+func (pool *TxPool) removeFromAll(hash common.Hash) {
+	tx, ok := pool.all[hash]
+	if !ok {
+		return
+	}
+	delete(pool.all, hash)
+	if payer := tx.Payer(); payer != nil && pool.payerCosts[*payer] != nil {
+		cost := pool.payerCosts[*payer]
+		if cost.Sub(cost, PayerCharge(pool.signer, tx)).Sign() <= 0 {
+			delete(pool.payerCosts, *payer)
+		}
+	}
+}
+
+// payerCost returns what payer buys for its transactions in the pool, leaving
+// out the one of from with nonce, which a new transaction would replace.
+func (pool *TxPool) payerCost(payer common.Address, from common.Address, nonce uint64) *big.Int {
+	cost := new(big.Int)
+	if c := pool.payerCosts[payer]; c != nil {
+		cost.Set(c)
+	}
+	for _, list := range []*txList{pool.pending[from], pool.queue[from]} {
+		if list == nil {
+			continue
+		}
+		if old := list.txs.Get(nonce); old != nil && old.Payer() != nil && *old.Payer() == payer {
+			cost.Sub(cost, PayerCharge(pool.signer, old))
+		}
+	}
+	return cost
+}
+
+// filterPayers removes the sponsored transactions whose payer can no longer buy
+// them together with its other transactions in the pool. The ones with the
+// highest price are kept, removeTx queues again the transactions after a
+// removed one in a pending list.
+func (pool *TxPool) filterPayers() {
+	sponsored := make(map[common.Address]types.TxByPrice)
+	for payer, cost := range pool.payerCosts {
+		if cost.Cmp(pool.currentState.GetBalance(payer)) > 0 {
+			sponsored[payer] = nil
+		}
+	}
+	if len(sponsored) == 0 {
+		return
+	}
+	for _, tx := range pool.all {
+		if payer := tx.Payer(); payer != nil {
+			if txs, ok := sponsored[*payer]; ok {
+				sponsored[*payer] = append(txs, tx)
+			}
+		}
+	}
+	for payer, txs := range sponsored {
+		balance := pool.currentState.GetBalance(payer)
+		sort.Sort(txs)
+
+		cost := new(big.Int)
+		for _, tx := range txs {
+			if pool.all[tx.Hash()] == nil {
+				// already dropped with an earlier transaction of its sender
+				continue
+			}
+			next := PayerCharge(pool.signer, tx)
+			if next.Add(next, cost).Cmp(balance) <= 0 {
+				cost = next
+				continue
+			}
+			log.Trace("Removed transaction of unpayable fee payer", "hash", tx.Hash(), "payer", payer)
+			pool.removeTx(tx.Hash())
+			payerNofundsCounter.Inc(1)
+		}
+	}
+}
+
 func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 	if accounts == nil {
@@ -1014,7 +1357,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			hash := tx.Hash()
 			txType := tx.GetTransactionType()
 			log.Trace("Removed old queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.RemoveByHash(txType, hash)
 
 		}
@@ -1024,13 +1367,24 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			hash := tx.Hash()
 			txType := tx.GetTransactionType()
 			log.Trace("Removed unpayable queued transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.RemoveByHash(txType, hash)
 			queuedNofundsCounter.Inc(1)
 
 		}
 
-		for _, tx := range list.Ready(pool.pendingState.GetNonce(addr)) {
+		number, now := pool.nextBlock()
+		expired, _ := list.Expire(number, now)
+		for _, tx := range expired {
+			hash := tx.Hash()
+			txType := tx.GetTransactionType()
+			log.Trace("Removed expired queued transaction", "hash", hash)
+			pool.removeFromAll(hash)
+			pool.priced.RemoveByHash(txType, hash)
+			queuedExpiredCounter.Inc(1)
+		}
+
+		for _, tx := range list.Ready(pool.pendingState.GetNonce(addr), number, now) {
 			hash := tx.Hash()
 			log.Trace("Promoting queued transaction", "hash", hash)
 			pool.promoteTx(addr, hash, tx)
@@ -1040,7 +1394,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 			for _, tx := range list.Cap(int(pool.config.AccountQueue)) {
 				hash := tx.Hash()
 				txType := tx.GetTransactionType()
-				delete(pool.all, hash)
+				pool.removeFromAll(hash)
 				pool.priced.RemoveByHash(txType, hash)
 				queuedRateLimitCounter.Inc(1)
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
@@ -1085,7 +1439,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 							hash := tx.Hash()
 							txType := tx.GetTransactionType()
-							delete(pool.all, hash)
+							pool.removeFromAll(hash)
 							pool.priced.RemoveByHash(txType, hash)
 
 							if nonce := tx.Nonce(); pool.pendingState.GetNonce(offenders[i]) > nonce {
@@ -1107,7 +1461,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 
 						hash := tx.Hash()
 						txType := tx.GetTransactionType()
-						delete(pool.all, hash)
+						pool.removeFromAll(hash)
 						pool.priced.RemoveByHash(txType, hash)
 
 						if nonce := tx.Nonce(); pool.pendingState.GetNonce(addr) > nonce {
@@ -1172,7 +1526,7 @@ func (pool *TxPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			txType := tx.GetTransactionType()
 			log.Trace("Removed old pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.RemoveByHash(txType, hash)
 
 		}
@@ -1182,12 +1536,24 @@ func (pool *TxPool) demoteUnexecutables() {
 			hash := tx.Hash()
 			txType := tx.GetTransactionType()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
-			delete(pool.all, hash)
+			pool.removeFromAll(hash)
 			pool.priced.RemoveByHash(txType, hash)
 			pendingNofundsCounter.Inc(1)
 
 		}
 
+		number, now := pool.nextBlock()
+		expired, stranded := list.Expire(number, now)
+		for _, tx := range expired {
+			hash := tx.Hash()
+			txType := tx.GetTransactionType()
+			log.Trace("Removed expired pending transaction", "hash", hash)
+			pool.removeFromAll(hash)
+			pool.priced.RemoveByHash(txType, hash)
+			pendingExpiredCounter.Inc(1)
+		}
+		invalids = append(invalids, stranded...)
+
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			log.Trace("Demoting pending transaction", "hash", hash)
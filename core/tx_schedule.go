@@ -0,0 +1,245 @@
+package core
+
+import (
+	"container/heap"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+// Block space scheduling policies of the transaction pool, see TxSchedulePolicy.
+const (
+	// ScheduleProportional gives each transaction type a share in proportion to
+	// its part of the pool, with contracts and the rest on separate budgets.
+	ScheduleProportional = "proportional"
+	// ScheduleStrictPrice fills the block with the best paying transactions
+	// whatever their type.
+	ScheduleStrictPrice = "price"
+	// ScheduleFair takes turns between the transaction types, weighted by the
+	// class of the type.
+	ScheduleFair = "fair"
+)
+
+// Classes of transaction types, as the weights of the fair policy are given.
+const (
+	TxClassAOA      = "aoa"
+	TxClassAsset    = "asset"
+	TxClassContract = "contract"
+	TxClassCreate   = "create"
+	TxClassRegister = "register"
+	TxClassVote     = "vote"
+	TxClassBatch    = "batch"
+	TxClassPublish  = "publish"
+)
+
+var txClasses = map[common.Address]string{
+	common.StringToAddress("aoa"):                    TxClassAOA,
+	common.StringToAddress(types.CreateContract):     TxClassCreate,
+	common.StringToAddress(types.RegisterAgent):      TxClassRegister,
+	common.StringToAddress(types.VoteAgent):          TxClassVote,
+	common.StringToAddress(types.BatchTransferAgent): TxClassBatch,
+	common.StringToAddress(types.PublishAsset):       TxClassPublish,
+}
+
+// TxGroup is the pooled transactions of one transaction type, see
+// Transaction.GetTransactionType, sorted by price and nonce. Pooled is their
+// number before Txs is capped, see capTxGroups.
+type TxGroup struct {
+	Type     common.Address
+	Contract bool
+	Txs      types.Transactions
+	Pooled   uint64
+}
+
+// Class returns the class of the transaction type of the group. Types that are
+// neither a contract nor one of the agents are assets.
+func (g *TxGroup) Class() string {
+	if g.Contract {
+		return TxClassContract
+	}
+	if class, ok := txClasses[g.Type]; ok {
+		return class
+	}
+	return TxClassAsset
+}
+
+// TxSchedulePolicy splits the space of a block between the transaction types
+// in the pool.
+type TxSchedulePolicy interface {
+	// Schedule returns how many transactions from the head of each group to
+	// offer for a block with room for about capacity transactions. pooled is
+	// the number of transactions in the pool, the queued ones included.
+	Schedule(groups []TxGroup, pooled, capacity uint64) []int
+}
+
+// newTxSchedulePolicy returns the policy config names, see sanitize.
+func newTxSchedulePolicy(config *TxPoolConfig) TxSchedulePolicy {
+	switch config.Schedule {
+	case ScheduleStrictPrice:
+		return strictPricePolicy{}
+	case ScheduleFair:
+		return fairPolicy{weights: config.ScheduleWeights}
+	default:
+		return proportionalPolicy{budget: 10000, maxContract: maxContractNum, minOther: 1000}
+	}
+}
+
+// capTxGroups cuts the groups of every asset and contract to at most limit
+// transactions. A zero limit leaves them whole.
+func capTxGroups(groups []TxGroup, limit uint64) {
+	if limit == 0 {
+		return
+	}
+	for i := range groups {
+		class := groups[i].Class()
+		if (class == TxClassAsset || class == TxClassContract) && uint64(len(groups[i].Txs)) > limit {
+			groups[i].Txs = groups[i].Txs[:limit]
+		}
+	}
+}
+
+// proportionalPolicy offers from each group in proportion to its part of the
+// transactions of its kind. The contract groups share a budget as large as the
+// pooled contract transactions, up to maxContract. The other groups share the
+// rest of budget, which shrinks linearly to minOther as the contract budget
+// grows. The parts are taken of the whole pool, the queued transactions
+// included, as the pool always did: a pool with many queued transactions offers
+// less. Every group offers at least one transaction; capacity is not used.
+type proportionalPolicy struct {
+	budget      uint64
+	maxContract uint64
+	minOther    uint64
+}
+
+func (p proportionalPolicy) Schedule(groups []TxGroup, pooled, capacity uint64) []int {
+	var contracts uint64
+	for _, group := range groups {
+		if group.Contract {
+			contracts += group.Pooled
+		}
+	}
+	others := uint64(0)
+	if pooled > contracts {
+		others = pooled - contracts
+	}
+	contractBudget := contracts
+	if contractBudget > p.maxContract {
+		contractBudget = p.maxContract
+	}
+	otherBudget := p.budget - contractBudget*(p.budget-p.minOther)/p.maxContract
+
+	counts := make([]int, len(groups))
+	for i, group := range groups {
+		if len(group.Txs) == 0 {
+			continue
+		}
+		budget, total := otherBudget, others
+		if group.Contract {
+			budget, total = contractBudget, contracts
+		}
+		var count uint64
+		if total > 0 {
+			count = group.Pooled * budget / total
+		}
+		if count == 0 {
+			count = 1
+		}
+		if count > uint64(len(group.Txs)) {
+			count = uint64(len(group.Txs))
+		}
+		counts[i] = int(count)
+	}
+	return counts
+}
+
+// strictPricePolicy offers the capacity best paying transactions of all the
+// groups, in the order of each group.
+type strictPricePolicy struct{}
+
+func (strictPricePolicy) Schedule(groups []TxGroup, pooled, capacity uint64) []int {
+	return fillByOrder(groups, capacity, func(a, b groupCursor) bool {
+		if cmp := groups[a.group].Txs[a.next].GasPrice().Cmp(groups[b.group].Txs[b.next].GasPrice()); cmp != 0 {
+			return cmp > 0
+		}
+		return a.group < b.group
+	}, nil)
+}
+
+// fairPolicy is weighted fair queueing of the groups: the groups take turns
+// offering a transaction, a group with weight w offering w transactions for
+// every one of a group with weight 1. The weights are given by the class of the
+// group, a missing class weighs 1 and a class of weight 0 is not offered.
+type fairPolicy struct {
+	weights map[string]uint64
+}
+
+func (p fairPolicy) weight(group *TxGroup) uint64 {
+	if weight, ok := p.weights[group.Class()]; ok {
+		return weight
+	}
+	return 1
+}
+
+func (p fairPolicy) Schedule(groups []TxGroup, pooled, capacity uint64) []int {
+	weights := make([]uint64, len(groups))
+	for i := range groups {
+		weights[i] = p.weight(&groups[i])
+	}
+	// the next transaction of a group finishes at (next+1)/weight
+	return fillByOrder(groups, capacity, func(a, b groupCursor) bool {
+		fa, fb := uint64(a.next+1)*weights[b.group], uint64(b.next+1)*weights[a.group]
+		if fa != fb {
+			return fa < fb
+		}
+		return a.group < b.group
+	}, func(i int) bool {
+		return weights[i] == 0
+	})
+}
+
+// groupCursor is the next transaction of a group to offer.
+type groupCursor struct {
+	group int
+	next  int
+}
+
+type cursorHeap struct {
+	cursors []groupCursor
+	less    func(a, b groupCursor) bool
+}
+
+func (h *cursorHeap) Len() int           { return len(h.cursors) }
+func (h *cursorHeap) Less(i, j int) bool { return h.less(h.cursors[i], h.cursors[j]) }
+func (h *cursorHeap) Swap(i, j int)      { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *cursorHeap) Push(x interface{}) { h.cursors = append(h.cursors, x.(groupCursor)) }
+func (h *cursorHeap) Pop() interface{} {
+	old := h.cursors
+	x := old[len(old)-1]
+	h.cursors = old[:len(old)-1]
+	return x
+}
+
+// fillByOrder offers up to capacity transactions, taking the next transaction
+// of the group that comes first by less each time. Groups skip reports true for
+// are not offered.
+func fillByOrder(groups []TxGroup, capacity uint64, less func(a, b groupCursor) bool, skip func(int) bool) []int {
+	counts := make([]int, len(groups))
+	h := &cursorHeap{less: less}
+	for i := range groups {
+		if len(groups[i].Txs) > 0 && (skip == nil || !skip(i)) {
+			h.cursors = append(h.cursors, groupCursor{group: i})
+		}
+	}
+	heap.Init(h)
+	for taken := uint64(0); taken < capacity && h.Len() > 0; taken++ {
+		cursor := &h.cursors[0]
+		counts[cursor.group]++
+		cursor.next++
+		if cursor.next == len(groups[cursor.group].Txs) {
+			heap.Pop(h)
+		} else {
+			heap.Fix(h, 0)
+		}
+	}
+	return counts
+}
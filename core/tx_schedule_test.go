@@ -0,0 +1,102 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+func scheduleGroup(txType common.Address, contract bool, prices ...int64) TxGroup {
+	group := TxGroup{Type: txType, Contract: contract, Pooled: uint64(len(prices))}
+	for i, price := range prices {
+		group.Txs = append(group.Txs, types.NewTransaction(uint64(i), txType, new(big.Int), 21000, big.NewInt(price), nil, types.ActionTrans, nil, nil, nil, nil, ""))
+	}
+	return group
+}
+
+// scheduleGroups is a pool with AOA transfers, transfers of an asset and calls
+// of a contract. The pool holds schedulePooled transactions, none queued.
+func scheduleGroups() []TxGroup {
+	return []TxGroup{
+		scheduleGroup(common.StringToAddress("aoa"), false, 5, 5, 5, 5),
+		scheduleGroup(common.Address{0xa5}, false, 9, 8, 7, 3, 2, 1),
+		scheduleGroup(common.Address{0xc0}, true, 6, 1),
+	}
+}
+
+const schedulePooled = 12
+
+func TestTxGroupClass(t *testing.T) {
+	tests := []struct {
+		group TxGroup
+		class string
+	}{
+		{TxGroup{Type: common.StringToAddress("aoa")}, TxClassAOA},
+		{TxGroup{Type: common.StringToAddress(types.VoteAgent)}, TxClassVote},
+		{TxGroup{Type: common.StringToAddress(types.RegisterAgent)}, TxClassRegister},
+		{TxGroup{Type: common.StringToAddress(types.CreateContract), Contract: true}, TxClassContract},
+		{TxGroup{Type: common.Address{0xa5}}, TxClassAsset},
+		{TxGroup{Type: common.Address{0xc0}, Contract: true}, TxClassContract},
+	}
+	for i, tt := range tests {
+		if class := tt.group.Class(); class != tt.class {
+			t.Errorf("test %d: class mismatch: have %s, want %s", i, class, tt.class)
+		}
+	}
+}
+
+func TestTxSchedulePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   TxSchedulePolicy
+		cap      uint64
+		capacity uint64
+		counts   []int
+	}{
+		// contracts get a budget of 2, the rest 10-2*9/5 = 7 split 4:6
+		{"proportional", proportionalPolicy{budget: 10, maxContract: 5, minOther: 1}, 0, 0, []int{2, 4, 2}},
+		{"proportional at defaults", newTxSchedulePolicy(&DefaultTxPoolConfig), 0, 0, []int{4, 6, 2}},
+		// the asset offers its share of 6 pooled, 6*7/10, cut to the cap of 3
+		{"proportional capped", proportionalPolicy{budget: 10, maxContract: 5, minOther: 1}, 3, 0, []int{2, 3, 2}},
+		// 9, 8, 7 of the asset, 6 of the contract, then the first 5
+		{"price", strictPricePolicy{}, 0, 5, []int{1, 3, 1}},
+		{"price with room for all", strictPricePolicy{}, 0, 100, []int{4, 6, 2}},
+		{"price capped", strictPricePolicy{}, 2, 5, []int{2, 2, 1}},
+		{"fair", fairPolicy{}, 0, 6, []int{2, 2, 2}},
+		{"fair exhausted", fairPolicy{}, 0, 10, []int{4, 4, 2}},
+		{"fair weighted", fairPolicy{weights: map[string]uint64{TxClassAsset: 2, TxClassContract: 0}}, 0, 6, []int{2, 4, 0}},
+		{"fair capped", fairPolicy{}, 1, 6, []int{4, 1, 1}},
+	}
+	for _, tt := range tests {
+		groups := scheduleGroups()
+		capTxGroups(groups, tt.cap)
+		if counts := tt.policy.Schedule(groups, schedulePooled, tt.capacity); !reflect.DeepEqual(counts, tt.counts) {
+			t.Errorf("%s: counts mismatch: have %v, want %v", tt.name, counts, tt.counts)
+		}
+	}
+}
+
+func TestTxScheduleSanitize(t *testing.T) {
+	config := DefaultTxPoolConfig
+	config.Schedule = "lottery"
+	if conf := config.sanitize(); conf.Schedule != ScheduleProportional {
+		t.Errorf("schedule mismatch: have %s, want %s", conf.Schedule, ScheduleProportional)
+	}
+	config.Schedule = ScheduleFair
+	if _, ok := newTxSchedulePolicy(&config).(fairPolicy); !ok {
+		t.Errorf("policy mismatch: have %T, want fairPolicy", newTxSchedulePolicy(&config))
+	}
+}
+
+// TestTxScheduleQueued checks that the proportional policy takes the shares of
+// the whole pool, as the pool always did, so queued transactions shrink them.
+func TestTxScheduleQueued(t *testing.T) {
+	policy := proportionalPolicy{budget: 10, maxContract: 5, minOther: 1}
+	// 8 queued transactions: the rest budget of 7 is split 4*7/18 and 6*7/18
+	if counts := policy.Schedule(scheduleGroups(), schedulePooled+8, 0); !reflect.DeepEqual(counts, []int{1, 2, 2}) {
+		t.Errorf("counts mismatch: have %v, want [1 2 2]", counts)
+	}
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+// MaxBatchTransfers is the most legs a batch transfer can hold. At
+// params.TxGasBatchTransferLeg each, a full batch stays within
+// params.MaxOneContractGasLimit.
+const MaxBatchTransfers = 40
+
+// BatchTransfer is a single leg of an ActionBatchTransfer transaction. A nil Asset
+// transfers AOA.
+type BatchTransfer struct {
+	To     common.Address  `json:"to"`
+	Asset  *common.Address `json:"asset" rlp:"nil"`
+	Amount *big.Int        `json:"amount"`
+}
+
+// BatchTransfersToBytes encodes the payload of an ActionBatchTransfer transaction.
+func BatchTransfersToBytes(transfers []BatchTransfer) ([]byte, error) {
+	return rlp.EncodeToBytes(transfers)
+}
+
+// BytesToBatchTransfers decodes the payload of an ActionBatchTransfer transaction.
+func BytesToBatchTransfers(enc []byte) ([]BatchTransfer, error) {
+	var transfers []BatchTransfer
+	if err := rlp.DecodeBytes(enc, &transfers); err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 || len(transfers) > MaxBatchTransfers {
+		return nil, fmt.Errorf("batch transfer must contain 1 to %d transfers", MaxBatchTransfers)
+	}
+	for i, t := range transfers {
+		if t.Amount == nil || t.Amount.Sign() <= 0 {
+			return nil, fmt.Errorf("amount of transfer %d must be greater then 0", i)
+		}
+	}
+	return transfers, nil
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+func TestBatchTransfersEncoding(t *testing.T) {
+	asset := common.HexToAddress("0x1337")
+	transfers := []BatchTransfer{
+		{To: common.HexToAddress("0x01"), Amount: big.NewInt(100)},
+		{To: common.HexToAddress("0x02"), Asset: &asset, Amount: big.NewInt(5)},
+	}
+	enc, err := BatchTransfersToBytes(transfers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := BytesToBatchTransfers(enc)
+	if err != nil {
+		t.Fatalf("failed to decode transfers: %v", err)
+	}
+	if len(dec) != 2 || dec[0].Asset != nil || *dec[1].Asset != asset || dec[1].To != transfers[1].To || dec[0].Amount.Cmp(transfers[0].Amount) != 0 {
+		t.Errorf("decoded transfers mismatch: have %+v, want %+v", dec, transfers)
+	}
+
+	if enc, _ := BatchTransfersToBytes(nil); enc != nil {
+		if _, err := BytesToBatchTransfers(enc); err == nil {
+			t.Errorf("empty batch should be rejected")
+		}
+	}
+	enc, _ = BatchTransfersToBytes([]BatchTransfer{{To: common.HexToAddress("0x01"), Amount: new(big.Int)}})
+	if _, err := BytesToBatchTransfers(enc); err == nil {
+		t.Errorf("zero amount should be rejected")
+	}
+	enc, _ = BatchTransfersToBytes(make([]BatchTransfer, MaxBatchTransfers+1))
+	if _, err := BytesToBatchTransfers(enc); err == nil {
+		t.Errorf("oversized batch should be rejected")
+	}
+}
+
+func TestBatchTransferGas(t *testing.T) {
+	// the largest legs a batch can hold must fit in one transaction
+	max := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	asset := common.BytesToAddress(max.Bytes())
+	transfers := make([]BatchTransfer, MaxBatchTransfers)
+	for i := range transfers {
+		transfers[i] = BatchTransfer{To: asset, Asset: &asset, Amount: max.Big()}
+	}
+	enc, err := BatchTransfersToBytes(transfers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gas, err := IntrinsicGas(enc, ActionBatchTransfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gas > params.MaxOneContractGasLimit {
+		t.Errorf("full batch needs %d gas, over the limit of %d", gas, params.MaxOneContractGasLimit)
+	}
+	if min := uint64(MaxBatchTransfers) * params.TxGasBatchTransferLeg; gas < min {
+		t.Errorf("full batch gas mismatch: have %d, want at least %d", gas, min)
+	}
+}
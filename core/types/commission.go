@@ -0,0 +1,27 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+// MaxCommission is a commission of 100% in basis points.
+const MaxCommission = 10000
+
+// CommissionToBytes encodes the payload of an ActionSetCommission transaction.
+func CommissionToBytes(commission uint64) ([]byte, error) {
+	return rlp.EncodeToBytes(commission)
+}
+
+// BytesToCommission decodes the payload of an ActionSetCommission transaction.
+func BytesToCommission(enc []byte) (uint64, error) {
+	var commission uint64
+	if err := rlp.DecodeBytes(enc, &commission); err != nil {
+		return 0, err
+	}
+	if commission > MaxCommission {
+		return 0, fmt.Errorf("commission must not exceed %d basis points", MaxCommission)
+	}
+	return commission, nil
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+var (
+	ErrEvidenceHeight    = errors.New("evidence headers are not of the same height")
+	ErrEvidenceSameBlock = errors.New("evidence headers are the same block")
+	ErrEvidenceSigner    = errors.New("evidence headers are not signed by the same delegate")
+	ErrEvidenceChain     = errors.New("evidence headers are not of this chain")
+)
+
+// SignedHeader is a block header with the signature its producer put on the
+// block, i.e. on the header hash.
+type SignedHeader struct {
+	Header    *Header       `json:"header"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// DoubleSignEvidence is the payload of an ActionDoubleSignEvidence transaction:
+// two different headers of the same height signed by the same producer.
+type DoubleSignEvidence struct {
+	First  SignedHeader `json:"first"`
+	Second SignedHeader `json:"second"`
+}
+
+// EvidenceToBytes encodes the payload of an ActionDoubleSignEvidence transaction.
+func EvidenceToBytes(evidence *DoubleSignEvidence) ([]byte, error) {
+	return rlp.EncodeToBytes(evidence)
+}
+
+// BytesToEvidence decodes the payload of an ActionDoubleSignEvidence transaction.
+func BytesToEvidence(enc []byte) (*DoubleSignEvidence, error) {
+	evidence := new(DoubleSignEvidence)
+	if err := rlp.DecodeBytes(enc, evidence); err != nil {
+		return nil, err
+	}
+	if evidence.First.Header == nil || evidence.Second.Header == nil {
+		return nil, errors.New("evidence header is nil")
+	}
+	return evidence, nil
+}
+
+// Hash identifies the evidence whatever the order of its headers.
+func (e *DoubleSignEvidence) Hash() common.Hash {
+	first, second := e.First.Header.Hash(), e.Second.Header.Hash()
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+	return crypto.Keccak256Hash(first[:], second[:])
+}
+
+// Number returns the height both headers claim.
+func (e *DoubleSignEvidence) Number() uint64 {
+	return e.First.Header.Number.Uint64()
+}
+
+// Verify checks that the headers are two different blocks of the same height
+// and delegate.
+func (e *DoubleSignEvidence) Verify() error {
+	first, second := e.First.Header, e.Second.Header
+	if first.Number == nil || second.Number == nil || first.Number.Cmp(second.Number) != 0 {
+		return ErrEvidenceHeight
+	}
+	if first.Hash() == second.Hash() {
+		return ErrEvidenceSameBlock
+	}
+	if first.Coinbase != second.Coinbase {
+		return ErrEvidenceSigner
+	}
+	return nil
+}
+
+// VerifyChain checks that both headers extend the block of this chain below
+// them, so that the evidence can not be made up of blocks of another chain.
+// hashOf returns the hash of the block of this chain at a height.
+func (e *DoubleSignEvidence) VerifyChain(hashOf func(number uint64) common.Hash) error {
+	for _, header := range []*Header{e.First.Header, e.Second.Header} {
+		if header.Number == nil || header.Number.Sign() == 0 || header.ParentHash != hashOf(header.Number.Uint64()-1) {
+			return ErrEvidenceChain
+		}
+	}
+	return nil
+}
+
+// Offender checks the evidence and returns the delegate that signed both
+// headers. signerOf returns the key a delegate signed the blocks of a height
+// with; if it is nil blocks are signed by their coinbase.
+func (e *DoubleSignEvidence) Offender(signerOf func(delegate common.Address, number *big.Int) common.Address) (common.Address, error) {
+	if err := e.Verify(); err != nil {
+		return common.Address{}, err
+	}
+	delegate := e.First.Header.Coinbase
+	signer := delegate
+	if signerOf != nil {
+		signer = signerOf(delegate, e.First.Header.Number)
+	}
+	for _, signed := range []SignedHeader{e.First, e.Second} {
+		pub, err := crypto.SigToPub(signed.Header.Hash().Bytes(), signed.Signature)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if crypto.PubkeyToAddress(*pub) != signer {
+			return common.Address{}, ErrEvidenceSigner
+		}
+	}
+	return delegate, nil
+}
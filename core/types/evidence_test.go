@@ -0,0 +1,81 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+func TestDoubleSignEvidence(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	producer := crypto.PubkeyToAddress(key.PublicKey)
+
+	sign := func(root common.Hash, number int64) SignedHeader {
+		header := &Header{Coinbase: producer, Root: root, Number: big.NewInt(number), Time: big.NewInt(100), ShuffleBlockNumber: big.NewInt(0)}
+		sig, _ := crypto.Sign(header.Hash().Bytes(), key)
+		return SignedHeader{Header: header, Signature: sig}
+	}
+	resign := func(signed SignedHeader, key *ecdsa.PrivateKey) SignedHeader {
+		signed.Signature, _ = crypto.Sign(signed.Header.Hash().Bytes(), key)
+		return signed
+	}
+	first, second := sign(common.Hash{1}, 7), sign(common.Hash{2}, 7)
+
+	enc, err := EvidenceToBytes(&DoubleSignEvidence{First: first, Second: second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	evidence, err := BytesToEvidence(enc)
+	if err != nil {
+		t.Fatalf("failed to decode evidence: %v", err)
+	}
+	if offender, err := evidence.Offender(nil); err != nil || offender != producer {
+		t.Errorf("offender mismatch: have %x (%v), want %x", offender, err, producer)
+	}
+	if evidence.Hash() != (&DoubleSignEvidence{First: second, Second: first}).Hash() {
+		t.Errorf("evidence hash depends on the header order")
+	}
+
+	if _, err := (&DoubleSignEvidence{First: first, Second: first}).Offender(nil); err != ErrEvidenceSameBlock {
+		t.Errorf("same block: have %v, want %v", err, ErrEvidenceSameBlock)
+	}
+	if _, err := (&DoubleSignEvidence{First: first, Second: sign(common.Hash{2}, 8)}).Offender(nil); err != ErrEvidenceHeight {
+		t.Errorf("different heights: have %v, want %v", err, ErrEvidenceHeight)
+	}
+	forged := resign(sign(common.Hash{3}, 7), other)
+	if _, err := (&DoubleSignEvidence{First: first, Second: forged}).Offender(nil); err != ErrEvidenceSigner {
+		t.Errorf("foreign signature: have %v, want %v", err, ErrEvidenceSigner)
+	}
+
+	// blocks signed with a producer key bound to the delegate
+	signerOf := func(delegate common.Address, number *big.Int) common.Address {
+		return crypto.PubkeyToAddress(other.PublicKey)
+	}
+	bound := &DoubleSignEvidence{First: resign(first, other), Second: resign(second, other)}
+	if offender, err := bound.Offender(signerOf); err != nil || offender != producer {
+		t.Errorf("producer key offender mismatch: have %x (%v), want %x", offender, err, producer)
+	}
+	if _, err := evidence.Offender(signerOf); err != ErrEvidenceSigner {
+		t.Errorf("delegate key with bound producer key: have %v, want %v", err, ErrEvidenceSigner)
+	}
+
+	// both headers have to extend the block of this chain below them
+	parentOf := func(parent common.Hash) func(uint64) common.Hash {
+		return func(number uint64) common.Hash {
+			if number != 6 {
+				return common.Hash{9}
+			}
+			return parent
+		}
+	}
+	if err := evidence.VerifyChain(parentOf(common.Hash{})); err != nil {
+		t.Errorf("evidence of this chain rejected: %v", err)
+	}
+	if err := evidence.VerifyChain(parentOf(common.Hash{5})); err != ErrEvidenceChain {
+		t.Errorf("evidence of another chain: have %v, want %v", err, ErrEvidenceChain)
+	}
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+)
+
+var (
+	ErrInvalidPayerSig = errors.New("invalid fee payer signature")
+	errNoPayer         = errors.New("transaction has no fee payer")
+	errUnsignedSender  = errors.New("fee payer signs after the sender")
+)
+
+// FeePayer is the account buying the gas of a sponsored transaction. The sender
+// signs the address of the payer, the payer signs PayerHash of the transaction
+// as the sender signed it. Value, asset and nonce stay with the sender.
+type FeePayer struct {
+	Address common.Address `json:"address"`
+	V       *big.Int       `json:"v"`
+	R       *big.Int       `json:"r"`
+	S       *big.Int       `json:"s"`
+}
+
+// Payer returns the account the transaction names to buy its gas, or nil if the
+// sender buys it.
+func (tx *Transaction) Payer() *common.Address {
+	if ext := tx.ext(); ext != nil && ext.Payer != nil {
+		payer := ext.Payer.Address
+		return &payer
+	}
+	return nil
+}
+
+// WithPayer returns a copy of the unsigned transaction whose gas is bought by
+// payer. The payer co-signs it once the sender signed it.
+func (tx *Transaction) WithPayer(payer common.Address) (*Transaction, error) {
+	return tx.withExt(func(ext *txext) {
+		ext.Payer = &FeePayer{Address: payer, V: new(big.Int), R: new(big.Int), S: new(big.Int)}
+	})
+}
+
+// PayerHash returns the hash the fee payer of tx signs. It covers the signature
+// of the sender, so the payer sponsors that sender only.
+func PayerHash(s Signer, tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{s.Hash(tx), tx.data.V, tx.data.R, tx.data.S})
+}
+
+// WithPayerSignature returns a copy of the transaction with the signature of
+// its fee payer, in the [R || S || V] format with V 0 or 1.
+func (tx *Transaction) WithPayerSignature(s Signer, sig []byte) (*Transaction, error) {
+	ext := tx.ext()
+	if ext == nil || ext.Payer == nil {
+		return nil, errNoPayer
+	}
+	if tx.data.R.Sign() == 0 && tx.data.S.Sign() == 0 {
+		return nil, errUnsignedSender
+	}
+	r, sv, v, err := signatureValues(tx, sig)
+	if err != nil {
+		return nil, err
+	}
+	cosigned := *ext
+	cosigned.Payer = &FeePayer{Address: ext.Payer.Address, V: v, R: r, S: sv}
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Ext = []txext{cosigned}
+	return cpy, nil
+}
+
+// SignPayer co-signs tx as its fee payer with prv.
+func SignPayer(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := PayerHash(s, tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(s, sig)
+}
+
+// PayerSender returns the fee payer of tx, checking that it signed the
+// transaction.
+func PayerSender(s Signer, tx *Transaction) (common.Address, error) {
+	ext := tx.ext()
+	if ext == nil || ext.Payer == nil {
+		return common.Address{}, errNoPayer
+	}
+	payer := ext.Payer
+	if payer.V == nil || payer.R == nil || payer.S == nil {
+		return common.Address{}, ErrInvalidPayerSig
+	}
+	addr, err := recoverPlain(PayerHash(s, tx), payer.R, payer.S, payer.V, true)
+	if err != nil || addr != payer.Address {
+		return common.Address{}, ErrInvalidPayerSig
+	}
+	return addr, nil
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+func TestFeePayerSigning(t *testing.T) {
+	senderKey, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	signer := NewAuroraSigner(big.NewInt(1))
+
+	tx, err := NewTransferTx(TxOptions{Nonce: 1, GasPrice: big.NewInt(2), Payer: &payer}, common.Address{1}, big.NewInt(10), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := tx.Payer(); p == nil || *p != payer {
+		t.Fatalf("payer mismatch: have %v, want %x", p, payer)
+	}
+	if tx.AoaCost().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("cost mismatch: have %v, want 10", tx.AoaCost())
+	}
+	if _, err := SignPayer(tx, signer, payerKey); err != errUnsignedSender {
+		t.Errorf("error mismatch: have %v, want %v", err, errUnsignedSender)
+	}
+	tx, _ = SignTx(tx, signer, senderKey)
+	if _, err := PayerSender(signer, tx); err != ErrInvalidPayerSig {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrInvalidPayerSig)
+	}
+	cosigned, err := SignPayer(tx, signer, payerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, _ := rlp.EncodeToBytes(cosigned)
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if from, err := Sender(signer, &dec); err != nil || from != sender {
+		t.Errorf("sender mismatch: have %x, %v", from, err)
+	}
+	if p, err := PayerSender(signer, &dec); err != nil || p != payer {
+		t.Errorf("payer mismatch: have %x, %v", p, err)
+	}
+	msg, err := dec.AsMessage(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := msg.Payer(); p == nil || *p != payer {
+		t.Errorf("message payer mismatch: have %v", p)
+	}
+
+	// the payer signature does not carry over to another sender signature
+	otherKey, _ := crypto.GenerateKey()
+	unsigned, _ := NewTransferTx(TxOptions{Nonce: 1, GasPrice: big.NewInt(2), Payer: &payer}, common.Address{1}, big.NewInt(10), "")
+	other, _ := SignTx(unsigned, signer, otherKey)
+	v, r, s := cosigned.ext().Payer.V, cosigned.ext().Payer.R, cosigned.ext().Payer.S
+	other.data.Ext[0].Payer = &FeePayer{Address: payer, V: v, R: r, S: s}
+	if _, err := PayerSender(signer, other); err != ErrInvalidPayerSig {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrInvalidPayerSig)
+	}
+
+	// the sender signs the payer address
+	swapped, _ := NewTransferTx(TxOptions{Nonce: 1, GasPrice: big.NewInt(2), Payer: &common.Address{2}}, common.Address{1}, big.NewInt(10), "")
+	if signer.Hash(swapped) == signer.Hash(unsigned) {
+		t.Error("payer is not signed by the sender")
+	}
+}
@@ -0,0 +1,247 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+// MaxMultisigOwners bounds the owners of a multisig account.
+const MaxMultisigOwners = 16
+
+var (
+	ErrMultisigSignatures = errors.New("multisig transaction does not carry exactly threshold signatures")
+	ErrNotMultisigOwner   = errors.New("signer is not an owner of the multisig account")
+	errMultisigThreshold  = fmt.Errorf("multisig threshold must be between 1 and the number of owners, at most %d", MaxMultisigOwners)
+	errMultisigOwners     = errors.New("multisig owners must be distinct non-zero addresses in ascending order")
+	errNoMultisig         = errors.New("transaction is not sent from a multisig account")
+	errMultisigSigned     = errors.New("owner signed the transaction already")
+	errMultisigComplete   = errors.New("multisig transaction carries its threshold of signatures already")
+)
+
+// Multisig defines an M-of-N account: a transaction sent from it needs the
+// signatures of Threshold of its Owners. The address of the account is derived
+// from the definition, so it is the same before and after it is created on
+// chain with ActionCreateMultisig.
+type Multisig struct {
+	Threshold uint64           `json:"threshold"`
+	Owners    []common.Address `json:"owners"`
+}
+
+// NewMultisig returns the multisig account of threshold of owners, given in
+// any order.
+func NewMultisig(threshold uint64, owners []common.Address) (*Multisig, error) {
+	sorted := make([]common.Address, len(owners))
+	copy(sorted, owners)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+	m := &Multisig{Threshold: threshold, Owners: sorted}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Validate checks the threshold and that the owners are in their canonical
+// order, which the address is derived from.
+func (m *Multisig) Validate() error {
+	if m.Threshold == 0 || m.Threshold > uint64(len(m.Owners)) || len(m.Owners) > MaxMultisigOwners {
+		return errMultisigThreshold
+	}
+	for i, owner := range m.Owners {
+		if owner == (common.Address{}) || (i > 0 && bytes.Compare(m.Owners[i-1][:], owner[:]) >= 0) {
+			return errMultisigOwners
+		}
+	}
+	return nil
+}
+
+// Address returns the address of the multisig account.
+func (m *Multisig) Address() common.Address {
+	return common.BytesToAddress(rlpHash(m).Bytes()[12:])
+}
+
+// IsOwner reports whether addr is one of the owners.
+func (m *Multisig) IsOwner(addr common.Address) bool {
+	for _, owner := range m.Owners {
+		if owner == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// MultisigGas returns the gas of checking the signatures of a transaction sent
+// from m, paid on top of its intrinsic gas.
+func MultisigGas(m *Multisig) uint64 {
+	return m.Threshold * params.MultisigSigGas
+}
+
+// MultisigToBytes encodes the payload of an ActionCreateMultisig transaction.
+func MultisigToBytes(m *Multisig) ([]byte, error) {
+	return rlp.EncodeToBytes(m)
+}
+
+// BytesToMultisig decodes the payload of an ActionCreateMultisig transaction.
+func BytesToMultisig(enc []byte) (*Multisig, error) {
+	m := new(Multisig)
+	if err := rlp.DecodeBytes(enc, m); err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// multisigSig is the signature of an owner besides the one in V, R and S of
+// the transaction.
+type multisigSig struct {
+	V *big.Int `json:"v"`
+	R *big.Int `json:"r"`
+	S *big.Int `json:"s"`
+}
+
+// txMultisig is the extension of a transaction sent from a multisig account.
+// The owner signing first signs in V, R and S of the transaction, the others
+// follow in ascending order of their address.
+type txMultisig struct {
+	Multisig
+	Sigs []multisigSig `json:"sigs"`
+}
+
+// Multisig returns the multisig account the transaction is sent from, or nil.
+func (tx *Transaction) Multisig() *Multisig {
+	if ext := tx.ext(); ext != nil && ext.Multisig != nil {
+		m := Multisig{Threshold: ext.Multisig.Threshold, Owners: make([]common.Address, len(ext.Multisig.Owners))}
+		copy(m.Owners, ext.Multisig.Owners)
+		return &m
+	}
+	return nil
+}
+
+// WithMultisig returns a copy of the unsigned transaction sent from the
+// multisig account m. Its owners sign it with WithMultisigSignature.
+func (tx *Transaction) WithMultisig(m Multisig) (*Transaction, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return tx.withExt(func(ext *txext) { ext.Multisig = &txMultisig{Multisig: m} })
+}
+
+// multisigSigners recovers the owners that signed the transaction so far.
+func multisigSigners(hash common.Hash, first common.Address, ms *txMultisig) ([]common.Address, error) {
+	if err := ms.Validate(); err != nil {
+		return nil, err
+	}
+	if !ms.IsOwner(first) {
+		return nil, ErrNotMultisigOwner
+	}
+	signers := []common.Address{first}
+	var prev common.Address
+	for _, sig := range ms.Sigs {
+		if sig.V == nil || sig.R == nil || sig.S == nil {
+			return nil, ErrInvalidSig
+		}
+		owner, err := recoverPlain(hash, sig.R, sig.S, sig.V, true)
+		if err != nil {
+			return nil, err
+		}
+		if !ms.IsOwner(owner) {
+			return nil, ErrNotMultisigOwner
+		}
+		if owner == first || bytes.Compare(prev[:], owner[:]) >= 0 {
+			return nil, errMultisigOwners
+		}
+		signers, prev = append(signers, owner), owner
+	}
+	if uint64(len(signers)) > ms.Threshold {
+		return nil, ErrMultisigSignatures
+	}
+	return signers, nil
+}
+
+// MultisigSigners returns the owners that signed the multisig transaction so
+// far, none if it is unsigned.
+func MultisigSigners(s Signer, tx *Transaction) ([]common.Address, error) {
+	ext := tx.ext()
+	if ext == nil || ext.Multisig == nil {
+		return nil, errNoMultisig
+	}
+	if tx.data.R.Sign() == 0 && tx.data.S.Sign() == 0 {
+		return nil, nil
+	}
+	// V holds the chain id, which the signature hash covers, and the recovery id
+	v := new(big.Int).Sub(tx.data.V, new(big.Int).Mul(tx.ChainId(), big.NewInt(2)))
+	first, err := recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, v.Sub(v, big8), true)
+	if err != nil {
+		return nil, err
+	}
+	return multisigSigners(s.Hash(tx), first, ext.Multisig)
+}
+
+// WithMultisigSignature returns a copy of the multisig transaction with the
+// signature of one more owner, in the [R || S || V] format with V 0 or 1. The
+// first owner signs in V, R and S of the transaction.
+func (tx *Transaction) WithMultisigSignature(s Signer, sig []byte) (*Transaction, error) {
+	ext := tx.ext()
+	if ext == nil || ext.Multisig == nil {
+		return nil, errNoMultisig
+	}
+	r, sv, v, err := signatureValues(tx, sig)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := recoverPlain(s.Hash(tx), r, sv, v, true)
+	if err != nil {
+		return nil, err
+	}
+	if !ext.Multisig.IsOwner(owner) {
+		return nil, ErrNotMultisigOwner
+	}
+	signers, err := MultisigSigners(s, tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return tx.WithSignature(s, sig)
+	}
+	if uint64(len(signers)) == ext.Multisig.Threshold {
+		return nil, errMultisigComplete
+	}
+	for _, signer := range signers {
+		if signer == owner {
+			return nil, errMultisigSigned
+		}
+	}
+	// keep the co-signatures ordered by owner, signers[1:] is in their order
+	pos := sort.Search(len(signers)-1, func(i int) bool { return bytes.Compare(signers[i+1][:], owner[:]) > 0 })
+	sigs := make([]multisigSig, 0, len(ext.Multisig.Sigs)+1)
+	sigs = append(sigs, ext.Multisig.Sigs[:pos]...)
+	sigs = append(sigs, multisigSig{V: v, R: r, S: sv})
+	sigs = append(sigs, ext.Multisig.Sigs[pos:]...)
+
+	cosigned := *ext
+	cosigned.Multisig = &txMultisig{Multisig: ext.Multisig.Multisig, Sigs: sigs}
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Ext = []txext{cosigned}
+	return cpy, nil
+}
+
+// SignMultisig adds the signature of the owner with key prv to the multisig
+// transaction tx.
+func SignMultisig(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := s.Hash(tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithMultisigSignature(s, sig)
+}
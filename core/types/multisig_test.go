@@ -0,0 +1,116 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+func TestMultisigDefinition(t *testing.T) {
+	a, b, c := common.Address{1}, common.Address{2}, common.Address{3}
+	m, err := NewMultisig(2, []common.Address{c, a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, _ := NewMultisig(2, []common.Address{a, b, c}); same.Address() != m.Address() {
+		t.Error("address depends on the order of the owners")
+	}
+	if other, _ := NewMultisig(3, []common.Address{a, b, c}); other.Address() == m.Address() {
+		t.Error("address does not depend on the threshold")
+	}
+	for _, bad := range []struct {
+		threshold uint64
+		owners    []common.Address
+	}{{0, []common.Address{a}}, {2, []common.Address{a}}, {1, []common.Address{a, a}}, {1, []common.Address{{}}}} {
+		if _, err := NewMultisig(bad.threshold, bad.owners); err == nil {
+			t.Errorf("%d of %x validated", bad.threshold, bad.owners)
+		}
+	}
+	if err := (&Multisig{Threshold: 1, Owners: []common.Address{b, a}}).Validate(); err != errMultisigOwners {
+		t.Errorf("error mismatch: have %v, want %v", err, errMultisigOwners)
+	}
+
+	tx, err := NewCreateMultisigTx(TxOptions{GasPrice: big.NewInt(1)}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *tx.To() != m.Address() || tx.TxDataAction() != ActionCreateMultisig {
+		t.Errorf("create multisig mismatch: to %x, action %d", tx.To(), tx.TxDataAction())
+	}
+	if dec, err := BytesToMultisig(tx.Data()); err != nil || dec.Address() != m.Address() {
+		t.Errorf("payload mismatch: have %v, %v", dec, err)
+	}
+}
+
+func TestMultisigSigning(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 3)
+	owners := make([]common.Address, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		owners[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	m, _ := NewMultisig(2, owners)
+	signer := NewAuroraSigner(big.NewInt(1))
+
+	tx, err := NewTransferTx(TxOptions{GasPrice: big.NewInt(1), Multisig: m}, common.Address{1}, big.NewInt(10), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Gas() != params.TxGas+2*params.MultisigSigGas {
+		t.Errorf("gas mismatch: have %d, want %d", tx.Gas(), params.TxGas+2*params.MultisigSigGas)
+	}
+	stranger, _ := crypto.GenerateKey()
+	if _, err := SignMultisig(tx, signer, stranger); err != ErrNotMultisigOwner {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrNotMultisigOwner)
+	}
+
+	// the owners sign out of the order of their addresses
+	tx, err = SignMultisig(tx, signer, keys[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sender(signer, tx); err != ErrMultisigSignatures {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrMultisigSignatures)
+	}
+	if _, err := SignMultisig(tx, signer, keys[2]); err != errMultisigSigned {
+		t.Errorf("error mismatch: have %v, want %v", err, errMultisigSigned)
+	}
+	tx, err = SignMultisig(tx, signer, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SignMultisig(tx, signer, keys[1]); err != errMultisigComplete {
+		t.Errorf("error mismatch: have %v, want %v", err, errMultisigComplete)
+	}
+
+	enc, _ := rlp.EncodeToBytes(tx)
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if from, err := Sender(signer, &dec); err != nil || from != m.Address() {
+		t.Errorf("sender mismatch: have %x, %v, want %x", from, err, m.Address())
+	}
+	signers, err := MultisigSigners(signer, &dec)
+	if err != nil || len(signers) != 2 || signers[0] != owners[2] || signers[1] != owners[0] {
+		t.Errorf("signers mismatch: have %x, %v", signers, err)
+	}
+	if msg, err := dec.AsMessage(signer); err != nil || msg.From() != m.Address() || msg.Multisig() == nil {
+		t.Errorf("message mismatch: have %x, %v", msg.From(), err)
+	}
+
+	// a signature of a stranger does not count for an owner
+	forged, _ := NewTransferTx(TxOptions{GasPrice: big.NewInt(1), Multisig: m}, common.Address{1}, big.NewInt(10), "")
+	forged, _ = SignMultisig(forged, signer, keys[0])
+	forgedSig, _ := crypto.Sign(signer.Hash(forged).Bytes(), stranger)
+	r, s, v, _ := signatureValues(forged, forgedSig)
+	forged.data.Ext[0].Multisig.Sigs = []multisigSig{{V: v, R: r, S: s}}
+	if _, err := Sender(signer, forged); err != ErrNotMultisigOwner {
+		t.Errorf("error mismatch: have %v, want %v", err, ErrNotMultisigOwner)
+	}
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+var (
+	errZeroProducer  = errors.New("producer key must not be the zero address")
+	errProducerProof = errors.New("producer signature is not from the producer key")
+)
+
+// ProducerProof is the payload of an ActionSetProducer transaction: the key the
+// delegate signs its blocks with and the signature of that key over
+// ProducerProofHash, which proves the delegate holds it. Without it a delegate
+// could bind the key of another node and have its blocks rejected.
+type ProducerProof struct {
+	Producer  common.Address
+	Signature []byte
+}
+
+// ProducerProofHash is the hash the producer key signs to be bound to delegate
+// on the chain with chainId.
+func ProducerProofHash(chainId *big.Int, delegate common.Address) common.Hash {
+	return crypto.Keccak256Hash([]byte("aoa producer"), common.BigToHash(chainId).Bytes(), delegate.Bytes())
+}
+
+// SignProducerProof signs the binding of key to delegate on the chain with
+// chainId.
+func SignProducerProof(key *ecdsa.PrivateKey, chainId *big.Int, delegate common.Address) (*ProducerProof, error) {
+	sig, err := crypto.Sign(ProducerProofHash(chainId, delegate).Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	return &ProducerProof{Producer: crypto.PubkeyToAddress(key.PublicKey), Signature: sig}, nil
+}
+
+// Verify checks that the proof is signed by the producer key for delegate on
+// the chain with chainId.
+func (p *ProducerProof) Verify(chainId *big.Int, delegate common.Address) error {
+	if p.Producer == (common.Address{}) {
+		return errZeroProducer
+	}
+	pub, err := crypto.SigToPub(ProducerProofHash(chainId, delegate).Bytes(), p.Signature)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(*pub) != p.Producer {
+		return errProducerProof
+	}
+	return nil
+}
+
+// ProducerToBytes encodes the payload of an ActionSetProducer transaction.
+func ProducerToBytes(proof *ProducerProof) ([]byte, error) {
+	return rlp.EncodeToBytes(proof)
+}
+
+// BytesToProducer decodes the payload of an ActionSetProducer transaction.
+func BytesToProducer(enc []byte) (*ProducerProof, error) {
+	proof := new(ProducerProof)
+	if err := rlp.DecodeBytes(enc, proof); err != nil {
+		return nil, err
+	}
+	if proof.Producer == (common.Address{}) {
+		return nil, errZeroProducer
+	}
+	return proof, nil
+}
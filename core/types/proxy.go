@@ -0,0 +1,25 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+var errZeroProxy = errors.New("proxy must not be the zero address")
+
+// ProxyStakeUnits returns the whole AOA stake locks for a proxy, the value of an
+// ActionSetProxy transaction.
+func ProxyStakeUnits(stake *big.Int) (uint64, error) {
+	aoa := big.NewInt(params.Aoa)
+	if stake == nil || stake.Sign() <= 0 || new(big.Int).Mod(stake, aoa).Sign() != 0 {
+		return 0, fmt.Errorf("proxied stake %v is not a positive multiple of %v", stake, aoa)
+	}
+	units := new(big.Int).Div(stake, aoa)
+	if !units.IsUint64() {
+		return 0, fmt.Errorf("proxied stake %v too large", stake)
+	}
+	return units.Uint64(), nil
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+// MaxReleaseVoters is the most voters an ActionReleaseVotes transaction can
+// release the stale votes of.
+const MaxReleaseVoters = 128
+
+// VotersToBytes encodes the payload of an ActionReleaseVotes transaction.
+func VotersToBytes(voters []common.Address) ([]byte, error) {
+	return rlp.EncodeToBytes(voters)
+}
+
+// BytesToVoters decodes the payload of an ActionReleaseVotes transaction.
+func BytesToVoters(enc []byte) ([]common.Address, error) {
+	var voters []common.Address
+	if err := rlp.DecodeBytes(enc, &voters); err != nil {
+		return nil, err
+	}
+	if len(voters) == 0 || len(voters) > MaxReleaseVoters {
+		return nil, fmt.Errorf("release votes must contain 1 to %d voters", MaxReleaseVoters)
+	}
+	return voters, nil
+}
@@ -26,13 +26,38 @@ const (
 	ActionPublishAsset
 	ActionCreateContract
 	ActionCallContract
+	ActionMintAsset
+	ActionBurnAsset
+	ActionBatchTransfer
+	ActionUnregister
+	ActionSetCommission
+	ActionClaimRewards
+	ActionUnjail
+	ActionDoubleSignEvidence
+	ActionSetProducer
+	ActionSetProxy
+	ActionRevokeProxy
+	ActionCreateMultisig
+	ActionReleaseVotes
 )
 
+// IsDelegateAction reports whether the action reads or changes the delegates,
+// the EVM context of such a transaction carries the delegate list.
+func IsDelegateAction(action uint64) bool {
+	switch action {
+	case ActionRegister, ActionAddVote, ActionSubVote, ActionUnregister, ActionSetCommission,
+		ActionDoubleSignEvidence, ActionSetProducer, ActionSetProxy, ActionRevokeProxy, ActionReleaseVotes:
+		return true
+	}
+	return false
+}
+
 const (
-	RegisterAgent  = "Register Agent"
-	VoteAgent      = "Vote Agent"
-	CreateContract = "Create Contract"
-	PublishAsset   = "Publish Asset"
+	RegisterAgent      = "Register Agent"
+	VoteAgent          = "Vote Agent"
+	CreateContract     = "Create Contract"
+	PublishAsset       = "Publish Asset"
+	BatchTransferAgent = "Batch Transfer"
 )
 
 var (
@@ -59,6 +84,24 @@ type Transaction struct {
 type Vote struct {
 	Candidate *common.Address `json:"candidate"`
 	Operation uint            `json:"operation"` 
+	Amount    *big.Int        `json:"amount,omitempty"`
+}
+
+// StakeUnits returns the whole AOA the vote stakes. A vote without an amount
+// stakes one AOA, which is what every vote locked before stake weighting.
+func (v Vote) StakeUnits() (uint64, error) {
+	if v.Amount == nil {
+		return 1, nil
+	}
+	aoa := big.NewInt(params.Aoa)
+	if v.Amount.Sign() <= 0 || new(big.Int).Mod(v.Amount, aoa).Sign() != 0 {
+		return 0, fmt.Errorf("vote amount %v is not a positive multiple of %v", v.Amount, aoa)
+	}
+	units := new(big.Int).Div(v.Amount, aoa)
+	if !units.IsUint64() {
+		return 0, fmt.Errorf("vote amount %v too large", v.Amount)
+	}
+	return units.Uint64(), nil
 }
 
 func VoteToBytes(vote []Vote) ([]byte, error) {
@@ -121,6 +164,9 @@ type txdata struct {
 	S *big.Int `json:"s" gencodec:"required"`
 
 	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// at most one, after the signature so that it can be left out
+	Ext []txext `json:"ext,omitempty" rlp:"tail"`
 }
 
 type txdataMarshaling struct {
@@ -188,14 +234,16 @@ func (tx *Transaction) GetTransactionType() common.Address {
 		} else {
 			return *a
 		}
-	case ActionRegister:
+	case ActionRegister, ActionUnregister, ActionSetCommission, ActionUnjail, ActionDoubleSignEvidence, ActionSetProducer, ActionCreateMultisig:
 		return common.StringToAddress(RegisterAgent)
-	case ActionAddVote, ActionSubVote:
+	case ActionAddVote, ActionSubVote, ActionClaimRewards, ActionSetProxy, ActionRevokeProxy, ActionReleaseVotes:
 		return common.StringToAddress(VoteAgent)
 	case ActionCreateContract:
 		return common.StringToAddress(CreateContract)
 	case ActionCallContract:
 		return *tx.To()
+	case ActionBatchTransfer:
+		return common.StringToAddress(BatchTransferAgent)
 	default:
 		return common.StringToAddress(PublishAsset)
 	}
@@ -208,6 +256,12 @@ func (tx *Transaction) EncodeRLP(w io.Writer) error {
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	_, size, _ := s.Kind()
 	err := s.Decode(&tx.data)
+	if err == nil {
+		err = checkExts(tx.data.Ext)
+	}
+	if err == nil && len(tx.data.Ext) == 0 {
+		tx.data.Ext = nil
+	}
 	if err == nil {
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
 	}
@@ -234,6 +288,9 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
 		return ErrInvalidSig
 	}
+	if err := checkExts(dec.Ext); err != nil {
+		return err
+	}
 	*tx = Transaction{data: dec}
 	return nil
 }
@@ -338,6 +395,16 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 		asset:      tx.data.Asset,
 		subAddress: tx.data.SubAddress,
 		abi:        tx.data.Abi,
+		window:     tx.Window(),
+		multisig:   tx.Multisig(),
+		ext:        tx.HasExt(),
+	}
+	if tx.Payer() != nil {
+		payer, err := PayerSender(s, tx)
+		if err != nil {
+			return msg, err
+		}
+		msg.payer = &payer
 	}
 	if len(tx.data.AssetInfo) > 0 {
 		assetInfo, err := BytesToAssetInfo(tx.data.AssetInfo)
@@ -365,7 +432,11 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 
 func (tx *Transaction) AoaCost() *big.Int {
 	log.Info("Transaction|AoaCost,", "transaction", tx.data)
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	total := new(big.Int)
+	// the gas of a sponsored transaction is bought by its payer
+	if tx.Payer() == nil {
+		total.Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	}
 
 	if tx.data.Action == ActionRegister {
 		registerCost := new(big.Int)
@@ -680,6 +751,10 @@ type Message struct {
 	assetInfo  *AssetInfo
 	subAddress string
 	abi        string
+	window     *TxWindow
+	payer      *common.Address
+	multisig   *Multisig
+	ext        bool
 }
 
 func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool, action uint64, vote []Vote, asset *common.Address, assetInfo *AssetInfo, subAddress string, abi string) Message {
@@ -720,3 +795,7 @@ func (m Message) AssetInfo() AssetInfo {
 	return AssetInfo{}
 }
 func (m Message) Abi() string { return m.abi }
+func (m Message) Window() *TxWindow { return m.window }
+func (m Message) Payer() *common.Address { return m.payer }
+func (m Message) Multisig() *Multisig { return m.multisig }
+func (m Message) HasExt() bool { return m.ext }
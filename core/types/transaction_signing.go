@@ -93,11 +93,26 @@ func (s AuroraSigner) Sender(tx *Transaction) (common.Address, error) {
 	V := new(big.Int).Sub(tx.data.V, s.chainIdMul)
 	V.Sub(V, big8)
 	log.Debug("AuroraSigner|Sender","V",V.Int64())
-	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	addr, err := recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	// a multisig account sends with the signatures of threshold of its owners
+	if ext := tx.ext(); ext != nil && ext.Multisig != nil {
+		signers, err := multisigSigners(s.Hash(tx), addr, ext.Multisig)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if uint64(len(signers)) != ext.Multisig.Threshold {
+			return common.Address{}, ErrMultisigSignatures
+		}
+		return ext.Multisig.Address(), nil
+	}
+	return addr, nil
 }
 
 func (s AuroraSigner) Hash(tx *Transaction) common.Hash  {
-	return rlpHash([]interface{}{
+	fields := []interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -112,7 +127,12 @@ func (s AuroraSigner) Hash(tx *Transaction) common.Hash  {
 		tx.data.SubAddress,
 		tx.data.Abi,
 		s.chainId, uint(0), uint(0),
-	})
+	}
+	// the optional parts are signed too, transactions without them hash as before
+	if ext := tx.ext(); ext != nil {
+		fields = append(fields, ext.signed())
+	}
+	return rlpHash(fields)
 }
 
 func (s AuroraSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
@@ -209,10 +209,10 @@ func TestTransaction_DecodeRLP(t *testing.T) {
 }
 
 func TestTxDifference(t *testing.T) {
-	v1 := Vote{nil, 1}
+	v1 := Vote{Operation: 1}
 	json.Marshal(v1)
-	v2 := Vote{nil, 2}
-	v3 := Vote{nil, 3}
+	v2 := Vote{Operation: 2}
+	v3 := Vote{Operation: 3}
 
 	buf := new(bytes.Buffer)
 	var data = []interface{}{
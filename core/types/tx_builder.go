@@ -0,0 +1,379 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+const (
+	MaxNicknameLength = 64
+	MaxTxSize         = 32 * 1024
+)
+
+var (
+	ErrGasUintOverflow  = errors.New("gas uint64 overflow")
+	ErrTxNegativeValue  = errors.New("negative value")
+	ErrTxGasPrice       = errors.New("negative gas price")
+	ErrTxGasOverLimit   = errors.New("gas over limit")
+	ErrTxIntrinsicGas   = errors.New("intrinsic gas too low")
+	ErrTxOversizedData  = errors.New("oversized data")
+	ErrTxGasRequired    = errors.New("gas limit is required for contract transactions")
+	ErrTxNickname       = errors.New("nickname must be 1 to 64 bytes")
+	ErrTxAssetNil       = errors.New("asset is nil")
+	ErrTxAssetAmount    = errors.New("asset amount must be greater then 0")
+	ErrTxEmptyVote      = errors.New("empty vote list")
+	ErrTxEmptyContract  = errors.New("create contract but data is nil")
+	ErrTxEmptyTransfers = errors.New("empty transfer list")
+)
+
+// IntrinsicGas computes the gas a transaction of the given action and payload
+// pays before any execution.
+func IntrinsicGas(data []byte, action uint64) (uint64, error) {
+	var gas uint64
+	switch action {
+	case ActionTrans, ActionRegister, ActionAddVote, ActionSubVote, ActionCallContract, ActionUnregister, ActionSetCommission, ActionClaimRewards, ActionUnjail, ActionSetProducer, ActionSetProxy, ActionRevokeProxy, ActionCreateMultisig, ActionReleaseVotes:
+		gas = params.TxGas
+	case ActionPublishAsset:
+		gas = params.TxGasAssetPublish
+	case ActionCreateContract:
+		gas = params.TxGasContractCreation
+	case ActionMintAsset:
+		gas = params.TxGasAssetMint
+	case ActionBurnAsset:
+		gas = params.TxGasAssetBurn
+	case ActionDoubleSignEvidence:
+		gas = params.TxGasEvidence
+	case ActionBatchTransfer:
+		transfers, err := BytesToBatchTransfers(data)
+		if err != nil {
+			return 0, err
+		}
+		gas = params.TxGas + uint64(len(transfers))*params.TxGasBatchTransferLeg
+	}
+
+	if len(data) > 0 {
+		var nz uint64
+		for _, byt := range data {
+			if byt != 0 {
+				nz++
+			}
+		}
+		if (math.MaxUint64-gas)/params.TxDataNonZeroGas < nz {
+			return 0, ErrGasUintOverflow
+		}
+		gas += nz * params.TxDataNonZeroGas
+
+		z := uint64(len(data)) - nz
+		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
+			return 0, ErrGasUintOverflow
+		}
+		gas += z * params.TxDataZeroGas
+	}
+	return gas, nil
+}
+
+// TxOptions holds the fields shared by every transaction built with the
+// per-action constructors below. A zero GasLimit is replaced by the intrinsic
+// gas of the transaction, except for contract transactions which need an
+// explicit limit. A Window restricts the blocks the transaction is valid in,
+// a Payer buys its gas in place of the sender. A Multisig sends it from that
+// multisig account, the gas of checking its signatures is added to the
+// intrinsic gas.
+type TxOptions struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	GasLimit uint64
+	Window   *TxWindow
+	Payer    *common.Address
+	Multisig *Multisig
+}
+
+// NewTransferTx builds an AOA transfer.
+func NewTransferTx(opts TxOptions, to common.Address, amount *big.Int, subAddress string) (*Transaction, error) {
+	return buildTx(opts, &to, amount, nil, ActionTrans, nil, nil, nil, nil, subAddress, "")
+}
+
+// NewAssetTransferTx builds a transfer of the given asset.
+func NewAssetTransferTx(opts TxOptions, to common.Address, asset common.Address, amount *big.Int, subAddress string) (*Transaction, error) {
+	if (asset == common.Address{}) {
+		return nil, ErrTxAssetNil
+	}
+	return buildTx(opts, &to, amount, nil, ActionTrans, nil, nil, &asset, nil, subAddress, "")
+}
+
+// NewRegisterTx builds a delegate registration for from.
+func NewRegisterTx(opts TxOptions, from common.Address, nickname string) (*Transaction, error) {
+	if len(strings.TrimSpace(nickname)) == 0 || len(nickname) > MaxNicknameLength {
+		return nil, ErrTxNickname
+	}
+	return buildTx(opts, &from, nil, nil, ActionRegister, nil, []byte(nickname), nil, nil, "", "")
+}
+
+// NewUnregisterTx builds the unregistration of the delegate from. Every vote
+// cast for from is withdrawn as well, which costs params.UnregisterVoterGas per
+// voter on top of the intrinsic gas, so opts.GasLimit should cover it.
+func NewUnregisterTx(opts TxOptions, from common.Address) (*Transaction, error) {
+	return buildTx(opts, &from, nil, nil, ActionUnregister, nil, nil, nil, nil, "", "")
+}
+
+// NewSetCommissionTx builds the change of the commission the delegate from
+// keeps of its block rewards, in basis points.
+func NewSetCommissionTx(opts TxOptions, from common.Address, commission uint64) (*Transaction, error) {
+	if commission > MaxCommission {
+		return nil, fmt.Errorf("commission must not exceed %d basis points", MaxCommission)
+	}
+	data, err := CommissionToBytes(commission)
+	if err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, data, ActionSetCommission, nil, nil, nil, nil, "", "")
+}
+
+// NewClaimRewardsTx builds the claim of the rewards from has earned by voting.
+func NewClaimRewardsTx(opts TxOptions, from common.Address) (*Transaction, error) {
+	return buildTx(opts, &from, nil, nil, ActionClaimRewards, nil, nil, nil, nil, "", "")
+}
+
+// NewUnjailTx builds the release of the delegate from from jail. It is only
+// accepted once the jail period of from has passed.
+func NewUnjailTx(opts TxOptions, from common.Address) (*Transaction, error) {
+	return buildTx(opts, &from, nil, nil, ActionUnjail, nil, nil, nil, nil, "", "")
+}
+
+// NewEvidenceTx builds the report of the delegate that signed both headers of
+// evidence. The reporter from is paid part of the stake slashed from it.
+func NewEvidenceTx(opts TxOptions, from common.Address, evidence *DoubleSignEvidence) (*Transaction, error) {
+	if err := evidence.Verify(); err != nil {
+		return nil, err
+	}
+	data, err := EvidenceToBytes(evidence)
+	if err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, data, ActionDoubleSignEvidence, nil, nil, nil, nil, "", "")
+}
+
+// NewSetProducerTx binds the key of proof as the key the delegate from signs
+// its blocks with from the next block on, replacing the key bound before. The
+// proof has to be signed for from, see SignProducerProof.
+func NewSetProducerTx(opts TxOptions, from common.Address, proof *ProducerProof) (*Transaction, error) {
+	if proof.Producer == (common.Address{}) {
+		return nil, errZeroProducer
+	}
+	data, err := ProducerToBytes(proof)
+	if err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, data, ActionSetProducer, nil, nil, nil, nil, "", "")
+}
+
+// NewSetProxyTx builds the nomination of proxy by from. stake is locked and
+// votes as the vote list of proxy until from revokes it. The stake counted for
+// each candidate of proxy costs params.ProxyShareGas on top of the intrinsic
+// gas, so opts.GasLimit should cover it.
+func NewSetProxyTx(opts TxOptions, proxy common.Address, stake *big.Int) (*Transaction, error) {
+	if proxy == (common.Address{}) {
+		return nil, errZeroProxy
+	}
+	if _, err := ProxyStakeUnits(stake); err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &proxy, stake, nil, ActionSetProxy, nil, nil, nil, nil, "", "")
+}
+
+// NewRevokeProxyTx builds the revocation of the proxy of from. The proxied
+// stake is unlocked as withdrawn votes are.
+func NewRevokeProxyTx(opts TxOptions, from common.Address) (*Transaction, error) {
+	return buildTx(opts, &from, nil, nil, ActionRevokeProxy, nil, nil, nil, nil, "", "")
+}
+
+// NewCreateMultisigTx creates the multisig account m on chain, after which it
+// can send transactions.
+func NewCreateMultisigTx(opts TxOptions, m *Multisig) (*Transaction, error) {
+	data, err := MultisigToBytes(m)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := BytesToMultisig(data); err != nil {
+		return nil, err
+	}
+	to := m.Address()
+	return buildTx(opts, &to, nil, data, ActionCreateMultisig, nil, nil, nil, nil, "", "")
+}
+
+// NewReleaseVotesTx drops the votes of voters on candidates that are no longer
+// delegates. Anyone can send it, on behalf of voters who do not come back
+// themselves.
+func NewReleaseVotesTx(opts TxOptions, from common.Address, voters []common.Address) (*Transaction, error) {
+	data, err := VotersToBytes(voters)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := BytesToVoters(data); err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, data, ActionReleaseVotes, nil, nil, nil, nil, "", "")
+}
+
+// NewVoteTx builds a vote change for from. Votes with operation 0 add a
+// candidate and votes with operation 1 remove one, each staking its amount or
+// one AOA without it. The action and the value follow from the net change of
+// the stake.
+func NewVoteTx(opts TxOptions, from common.Address, votes []Vote) (*Transaction, error) {
+	if len(votes) == 0 {
+		return nil, ErrTxEmptyVote
+	}
+	seen := make(map[common.Address]bool)
+	diff := new(big.Int)
+	for _, vote := range votes {
+		if vote.Candidate == nil {
+			return nil, errors.New("vote candidate is nil")
+		}
+		if seen[*vote.Candidate] {
+			return nil, fmt.Errorf("candidate %s appears twice in the vote list", vote.Candidate.Hex())
+		}
+		seen[*vote.Candidate] = true
+		units, err := vote.StakeUnits()
+		if err != nil {
+			return nil, err
+		}
+		switch vote.Operation {
+		case 0:
+			diff.Add(diff, new(big.Int).SetUint64(units))
+		case 1:
+			diff.Sub(diff, new(big.Int).SetUint64(units))
+		default:
+			return nil, fmt.Errorf("Vote candidate %s Operation error!", vote.Candidate.Hex())
+		}
+	}
+	action := uint64(ActionAddVote)
+	if diff.Sign() <= 0 {
+		action = ActionSubVote
+		diff.Neg(diff)
+	}
+	enc, err := VoteToBytes(votes)
+	if err != nil {
+		return nil, err
+	}
+	value := diff.Mul(diff, big.NewInt(params.Aoa))
+	return buildTx(opts, &from, value, nil, action, enc, nil, nil, nil, "", "")
+}
+
+// NewPublishAssetTx builds the publication of a new asset by from.
+func NewPublishAssetTx(opts TxOptions, from common.Address, info *AssetInfo) (*Transaction, error) {
+	if err := IsAssetInfoValid(info); err != nil {
+		return nil, err
+	}
+	enc, err := AssetInfoToBytes(*info)
+	if err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, nil, ActionPublishAsset, nil, nil, nil, enc, "", "")
+}
+
+// NewMintAssetTx builds a mint of amount units of asset credited to to.
+func NewMintAssetTx(opts TxOptions, to common.Address, asset common.Address, amount *big.Int) (*Transaction, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrTxAssetAmount
+	}
+	return buildTx(opts, &to, amount, nil, ActionMintAsset, nil, nil, &asset, nil, "", "")
+}
+
+// NewBurnAssetTx builds a burn of amount units of asset held by from.
+func NewBurnAssetTx(opts TxOptions, from common.Address, asset common.Address, amount *big.Int) (*Transaction, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrTxAssetAmount
+	}
+	return buildTx(opts, &from, amount, nil, ActionBurnAsset, nil, nil, &asset, nil, "", "")
+}
+
+// NewBatchTransferTx builds an atomic batch of transfers sent by from.
+func NewBatchTransferTx(opts TxOptions, from common.Address, transfers []BatchTransfer) (*Transaction, error) {
+	if len(transfers) == 0 {
+		return nil, ErrTxEmptyTransfers
+	}
+	data, err := BatchTransfersToBytes(transfers)
+	if err != nil {
+		return nil, err
+	}
+	// decode again so the legs are checked exactly like the state transition does
+	if _, err := BytesToBatchTransfers(data); err != nil {
+		return nil, err
+	}
+	return buildTx(opts, &from, nil, data, ActionBatchTransfer, nil, nil, nil, nil, "", "")
+}
+
+// NewContractCreationTx builds a contract deployment. A non-nil asset endows the
+// contract with amount units of that asset instead of AOA.
+func NewContractCreationTx(opts TxOptions, code []byte, abi string, asset *common.Address, amount *big.Int) (*Transaction, error) {
+	if len(code) == 0 {
+		return nil, ErrTxEmptyContract
+	}
+	if opts.GasLimit == 0 {
+		return nil, ErrTxGasRequired
+	}
+	return buildTx(opts, nil, amount, code, ActionCreateContract, nil, nil, asset, nil, "", abi)
+}
+
+// NewContractCallTx builds a call of contract with the given input.
+func NewContractCallTx(opts TxOptions, contract common.Address, input []byte, asset *common.Address, amount *big.Int) (*Transaction, error) {
+	if opts.GasLimit == 0 {
+		return nil, ErrTxGasRequired
+	}
+	return buildTx(opts, &contract, amount, input, ActionCallContract, nil, nil, asset, nil, "", "")
+}
+
+func buildTx(opts TxOptions, to *common.Address, amount *big.Int, data []byte, action uint64, vote []byte, nickname []byte, asset *common.Address, assetInfo []byte, subAddress string, abi string) (*Transaction, error) {
+	if amount != nil && amount.Sign() < 0 {
+		return nil, ErrTxNegativeValue
+	}
+	if opts.GasPrice != nil && opts.GasPrice.Sign() < 0 {
+		return nil, ErrTxGasPrice
+	}
+	intrGas, err := IntrinsicGas(data, action)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Multisig != nil {
+		if err := opts.Multisig.Validate(); err != nil {
+			return nil, err
+		}
+		intrGas += MultisigGas(opts.Multisig)
+	}
+	gas := opts.GasLimit
+	if gas == 0 {
+		gas = intrGas
+	}
+	if gas < intrGas {
+		return nil, ErrTxIntrinsicGas
+	}
+	if gas > params.MaxOneContractGasLimit {
+		return nil, ErrTxGasOverLimit
+	}
+	tx := newTransaction(opts.Nonce, to, amount, gas, opts.GasPrice, data, action, vote, nickname, asset, assetInfo, subAddress, abi)
+	if opts.Window != nil {
+		if tx, err = tx.WithWindow(*opts.Window); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Payer != nil {
+		if tx, err = tx.WithPayer(*opts.Payer); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Multisig != nil {
+		if tx, err = tx.WithMultisig(*opts.Multisig); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Size() > MaxTxSize {
+		return nil, ErrTxOversizedData
+	}
+	return tx, nil
+}
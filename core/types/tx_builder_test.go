@@ -0,0 +1,166 @@
+package types
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+var (
+	builderFrom = common.HexToAddress("0x71562b71999873db5b286df957af199ec94617f7")
+	builderOpts = TxOptions{Nonce: 3, GasPrice: big.NewInt(1)}
+)
+
+func TestTxBuilderVote(t *testing.T) {
+	a, b, c := common.HexToAddress("0x0a"), common.HexToAddress("0x0b"), common.HexToAddress("0x0c")
+
+	tx, err := NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a}, {Candidate: &b}, {Candidate: &c, Operation: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxDataAction() != ActionAddVote || *tx.To() != builderFrom || tx.Value().Cmp(big.NewInt(params.Aoa)) != 0 {
+		t.Errorf("add vote mismatch: action %d to %x value %v", tx.TxDataAction(), tx.To(), tx.Value())
+	}
+	votes, err := BytesToVote(tx.Vote())
+	if err != nil || len(votes) != 3 || *votes[2].Candidate != c || votes[2].Operation != 1 {
+		t.Errorf("vote encoding mismatch: have %+v (%v)", votes, err)
+	}
+	if tx.Gas() != params.TxGas+uint64(len(tx.Data()))*params.TxDataNonZeroGas {
+		t.Errorf("default gas mismatch: have %d", tx.Gas())
+	}
+
+	tx, err = NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a, Operation: 1}, {Candidate: &b, Operation: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxDataAction() != ActionSubVote || tx.Value().Cmp(new(big.Int).Mul(big.NewInt(2), big.NewInt(params.Aoa))) != 0 {
+		t.Errorf("sub vote mismatch: action %d value %v", tx.TxDataAction(), tx.Value())
+	}
+
+	stake := new(big.Int).Mul(big.NewInt(25), big.NewInt(params.Aoa))
+	tx, err = NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a, Amount: stake}, {Candidate: &b, Operation: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxDataAction() != ActionAddVote || tx.Value().Cmp(new(big.Int).Mul(big.NewInt(24), big.NewInt(params.Aoa))) != 0 {
+		t.Errorf("stake vote mismatch: action %d value %v", tx.TxDataAction(), tx.Value())
+	}
+	if _, err := NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a, Amount: big.NewInt(params.Aoa / 2)}}); err == nil {
+		t.Errorf("stake of a fraction of an AOA should be rejected")
+	}
+
+	if _, err := NewVoteTx(builderOpts, builderFrom, nil); err != ErrTxEmptyVote {
+		t.Errorf("empty vote list: have %v, want %v", err, ErrTxEmptyVote)
+	}
+	if _, err := NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a}, {Candidate: &a, Operation: 1}}); err == nil {
+		t.Errorf("duplicate candidate should be rejected")
+	}
+	if _, err := NewVoteTx(builderOpts, builderFrom, []Vote{{Candidate: &a, Operation: 2}}); err == nil {
+		t.Errorf("unknown operation should be rejected")
+	}
+}
+
+func TestTxBuilderPublishAsset(t *testing.T) {
+	info := &AssetInfo{Name: "Test", Symbol: "TST", Supply: big.NewInt(1000)}
+	tx, err := NewPublishAssetTx(builderOpts, builderFrom, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxDataAction() != ActionPublishAsset || *tx.To() != builderFrom || tx.Gas() != params.TxGasAssetPublish {
+		t.Errorf("publish mismatch: action %d to %x gas %d", tx.TxDataAction(), tx.To(), tx.Gas())
+	}
+	if dec := tx.AssetInfo(); dec == nil || dec.Symbol != "TST" || dec.Supply.Cmp(info.Supply) != 0 {
+		t.Errorf("asset info encoding mismatch: have %+v", dec)
+	}
+	if _, err := NewPublishAssetTx(builderOpts, builderFrom, &AssetInfo{Name: "Test", Symbol: "TST"}); err == nil {
+		t.Errorf("asset without supply should be rejected")
+	}
+}
+
+func TestTxBuilderStaticChecks(t *testing.T) {
+	to := common.HexToAddress("0x01")
+
+	if _, err := NewTransferTx(builderOpts, to, big.NewInt(-1), ""); err != ErrTxNegativeValue {
+		t.Errorf("negative value: have %v, want %v", err, ErrTxNegativeValue)
+	}
+	if _, err := NewTransferTx(TxOptions{GasPrice: big.NewInt(1), GasLimit: params.TxGas - 1}, to, big.NewInt(1), ""); err != ErrTxIntrinsicGas {
+		t.Errorf("low gas: have %v, want %v", err, ErrTxIntrinsicGas)
+	}
+	if _, err := NewTransferTx(TxOptions{GasPrice: big.NewInt(1), GasLimit: params.MaxOneContractGasLimit + 1}, to, big.NewInt(1), ""); err != ErrTxGasOverLimit {
+		t.Errorf("high gas: have %v, want %v", err, ErrTxGasOverLimit)
+	}
+	if _, err := NewRegisterTx(builderOpts, builderFrom, ""); err != ErrTxNickname {
+		t.Errorf("empty nickname: have %v, want %v", err, ErrTxNickname)
+	}
+	if _, err := NewRegisterTx(builderOpts, builderFrom, strings.Repeat("a", MaxNicknameLength+1)); err != ErrTxNickname {
+		t.Errorf("long nickname: have %v, want %v", err, ErrTxNickname)
+	}
+	if _, err := NewContractCreationTx(TxOptions{GasPrice: big.NewInt(1), GasLimit: 90000}, nil, "", nil, nil); err != ErrTxEmptyContract {
+		t.Errorf("empty contract: have %v, want %v", err, ErrTxEmptyContract)
+	}
+	if _, err := NewContractCallTx(builderOpts, to, []byte{1}, nil, nil); err != ErrTxGasRequired {
+		t.Errorf("contract call without gas: have %v, want %v", err, ErrTxGasRequired)
+	}
+	if tx, err := NewUnregisterTx(builderOpts, builderFrom); err != nil || tx.TxDataAction() != ActionUnregister || *tx.To() != builderFrom || tx.Gas() != params.TxGas {
+		t.Errorf("unregister mismatch: %v", err)
+	}
+	if _, err := NewSetCommissionTx(builderOpts, builderFrom, MaxCommission+1); err == nil {
+		t.Errorf("commission over 100%% should be rejected")
+	}
+	if tx, err := NewSetCommissionTx(builderOpts, builderFrom, 1500); err != nil {
+		t.Errorf("set commission: %v", err)
+	} else if commission, err := BytesToCommission(tx.Data()); err != nil || commission != 1500 {
+		t.Errorf("commission encoding mismatch: have %d (%v)", commission, err)
+	}
+	if tx, err := NewUnjailTx(builderOpts, builderFrom); err != nil || tx.TxDataAction() != ActionUnjail || tx.GetTransactionType() != common.StringToAddress(RegisterAgent) {
+		t.Errorf("unjail mismatch: %v", err)
+	}
+	if _, err := NewSetProducerTx(builderOpts, builderFrom, &ProducerProof{}); err == nil {
+		t.Errorf("zero producer key should be rejected")
+	}
+	key, _ := crypto.GenerateKey()
+	proof, err := SignProducerProof(key, big.NewInt(1), builderFrom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx, err := NewSetProducerTx(builderOpts, builderFrom, proof); err != nil || tx.TxDataAction() != ActionSetProducer {
+		t.Errorf("set producer: %v", err)
+	} else if decoded, err := BytesToProducer(tx.Data()); err != nil || decoded.Producer != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("producer encoding mismatch: have %+v (%v)", decoded, err)
+	} else if err := decoded.Verify(big.NewInt(1), builderFrom); err != nil {
+		t.Errorf("producer proof: %v", err)
+	}
+	if err := proof.Verify(big.NewInt(2), builderFrom); err == nil {
+		t.Errorf("producer proof of another chain should be rejected")
+	}
+	if err := proof.Verify(big.NewInt(1), to); err == nil {
+		t.Errorf("producer proof of another delegate should be rejected")
+	}
+	if _, err := NewSetProxyTx(builderOpts, to, big.NewInt(params.Aoa+1)); err == nil {
+		t.Errorf("fractional proxied stake should be rejected")
+	}
+	if _, err := NewSetProxyTx(builderOpts, common.Address{}, big.NewInt(params.Aoa)); err == nil {
+		t.Errorf("zero proxy should be rejected")
+	}
+	if tx, err := NewSetProxyTx(builderOpts, to, big.NewInt(2*params.Aoa)); err != nil || tx.TxDataAction() != ActionSetProxy || *tx.To() != to || tx.GetTransactionType() != common.StringToAddress(VoteAgent) {
+		t.Errorf("set proxy mismatch: %v", err)
+	}
+	if tx, err := NewRevokeProxyTx(builderOpts, builderFrom); err != nil || tx.TxDataAction() != ActionRevokeProxy || tx.Value().Sign() != 0 {
+		t.Errorf("revoke proxy mismatch: %v", err)
+	}
+	if _, err := NewBurnAssetTx(builderOpts, builderFrom, to, new(big.Int)); err != ErrTxAssetAmount {
+		t.Errorf("zero burn: have %v, want %v", err, ErrTxAssetAmount)
+	}
+
+	tx, err := NewContractCreationTx(TxOptions{GasPrice: big.NewInt(1), GasLimit: 90000}, []byte{0x60, 0x00}, "[]", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.To() != nil || tx.TxDataAction() != ActionCreateContract || tx.Abi() != "[]" {
+		t.Errorf("contract creation mismatch: to %x action %d abi %q", tx.To(), tx.TxDataAction(), tx.Abi())
+	}
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/Aurorachain/go-Aurora/common"
+)
+
+var (
+	errTxExts      = errors.New("transaction has more than one extension")
+	errEmptyTxExt  = errors.New("transaction extension is empty")
+	errSignedTxExt = errors.New("extension of a signed transaction can not change")
+)
+
+// txext holds the optional parts of a transaction. It is encoded after the
+// signature values, so that transactions without it keep their encoding and
+// hash.
+type txext struct {
+	Window   *TxWindow   `json:"window,omitempty" rlp:"nil"`
+	Payer    *FeePayer   `json:"feePayer,omitempty" rlp:"nil"`
+	Multisig *txMultisig `json:"multisig,omitempty" rlp:"nil"`
+}
+
+// signed returns the part of the extension the sender signs: everything but
+// the signatures of the payer and of the owners of a multisig sender.
+func (ext *txext) signed() []interface{} {
+	var (
+		payer    *common.Address
+		multisig *Multisig
+	)
+	if ext.Payer != nil {
+		payer = &ext.Payer.Address
+	}
+	if ext.Multisig != nil {
+		multisig = &ext.Multisig.Multisig
+	}
+	return []interface{}{ext.Window, payer, multisig}
+}
+
+// checkExts rejects what no transaction built by this node carries: more than
+// one extension or one without any part. Nodes that predate extensions can not
+// decode either, so they must not be gossiped before the forks.
+func checkExts(exts []txext) error {
+	if len(exts) > 1 {
+		return errTxExts
+	}
+	if len(exts) == 1 && exts[0].Window == nil && exts[0].Payer == nil && exts[0].Multisig == nil {
+		return errEmptyTxExt
+	}
+	return nil
+}
+
+// HasExt reports whether the transaction carries an extension.
+func (tx *Transaction) HasExt() bool { return len(tx.data.Ext) != 0 }
+
+func (tx *Transaction) ext() *txext {
+	if len(tx.data.Ext) == 0 {
+		return nil
+	}
+	return &tx.data.Ext[0]
+}
+
+// withExt returns a copy of the unsigned transaction with its extension
+// changed by update.
+func (tx *Transaction) withExt(update func(*txext)) (*Transaction, error) {
+	if tx.data.R.Sign() != 0 || tx.data.S.Sign() != 0 {
+		return nil, errSignedTxExt
+	}
+	var ext txext
+	if cur := tx.ext(); cur != nil {
+		ext = *cur
+	}
+	update(&ext)
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Ext = []txext{ext}
+	return cpy, nil
+}
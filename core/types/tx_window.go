@@ -0,0 +1,66 @@
+package types
+
+import "errors"
+
+var (
+	ErrTxWindow    = errors.New("transaction window expires before it opens")
+	errEmptyWindow = errors.New("transaction window has no bounds")
+)
+
+// TxWindow bounds the blocks a transaction may be included in: from NotBefore
+// on and before ExpiresAt. The bounds are block numbers, or unix times of the
+// block if ByTime is set. A zero bound is open.
+type TxWindow struct {
+	NotBefore uint64 `json:"notBefore"`
+	ExpiresAt uint64 `json:"expiresAt"`
+	ByTime    bool   `json:"byTime"`
+}
+
+// Validate checks that the window has a bound and does not close before it
+// opens.
+func (w *TxWindow) Validate() error {
+	if w.NotBefore == 0 && w.ExpiresAt == 0 {
+		return errEmptyWindow
+	}
+	if w.ExpiresAt != 0 && w.ExpiresAt <= w.NotBefore {
+		return ErrTxWindow
+	}
+	return nil
+}
+
+func (w *TxWindow) at(number, time uint64) uint64 {
+	if w.ByTime {
+		return time
+	}
+	return number
+}
+
+// Opened reports whether a block with the given number and time is at or past
+// NotBefore.
+func (w *TxWindow) Opened(number, time uint64) bool {
+	return w.at(number, time) >= w.NotBefore
+}
+
+// Expired reports whether a block with the given number and time is at or past
+// ExpiresAt.
+func (w *TxWindow) Expired(number, time uint64) bool {
+	return w.ExpiresAt != 0 && w.at(number, time) >= w.ExpiresAt
+}
+
+// Window returns the window of the transaction, or nil if it may be included
+// in any block.
+func (tx *Transaction) Window() *TxWindow {
+	if ext := tx.ext(); ext != nil && ext.Window != nil {
+		w := *ext.Window
+		return &w
+	}
+	return nil
+}
+
+// WithWindow returns a copy of the unsigned transaction restricted to window.
+func (tx *Transaction) WithWindow(window TxWindow) (*Transaction, error) {
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	return tx.withExt(func(ext *txext) { ext.Window = &window })
+}
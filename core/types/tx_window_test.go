@@ -0,0 +1,124 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+func TestTxWindowBounds(t *testing.T) {
+	blocks := TxWindow{NotBefore: 10, ExpiresAt: 20}
+	times := TxWindow{NotBefore: 1000, ByTime: true}
+	tests := []struct {
+		window          TxWindow
+		number, time    uint64
+		opened, expired bool
+	}{
+		{blocks, 9, 5000, false, false},
+		{blocks, 10, 0, true, false},
+		{blocks, 19, 0, true, false},
+		{blocks, 20, 0, true, true},
+		{times, 5000, 999, false, false},
+		{times, 0, 1000, true, false},
+		{times, 0, 1 << 40, true, false},
+	}
+	for i, tt := range tests {
+		if opened := tt.window.Opened(tt.number, tt.time); opened != tt.opened {
+			t.Errorf("test %d: opened mismatch: have %v, want %v", i, opened, tt.opened)
+		}
+		if expired := tt.window.Expired(tt.number, tt.time); expired != tt.expired {
+			t.Errorf("test %d: expired mismatch: have %v, want %v", i, expired, tt.expired)
+		}
+	}
+	for _, w := range []TxWindow{{}, {ByTime: true}, {NotBefore: 5, ExpiresAt: 5}, {NotBefore: 6, ExpiresAt: 5}} {
+		if err := w.Validate(); err == nil {
+			t.Errorf("window %+v validated", w)
+		}
+	}
+}
+
+func TestTxWindowEncoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := NewAuroraSigner(big.NewInt(1))
+	plain, err := NewTransferTx(TxOptions{Nonce: 3, GasPrice: big.NewInt(1)}, common.Address{1}, big.NewInt(10), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	windowed, err := NewTransferTx(TxOptions{Nonce: 3, GasPrice: big.NewInt(1), Window: &TxWindow{ExpiresAt: 100}}, common.Address{1}, big.NewInt(10), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.Hash(plain) == signer.Hash(windowed) {
+		t.Fatal("window is not signed")
+	}
+	plain, _ = SignTx(plain, signer, key)
+	windowed, _ = SignTx(windowed, signer, key)
+
+	// a transaction without a window encodes as before windows existed
+	enc, _ := rlp.EncodeToBytes(plain)
+	legacy, _ := rlp.EncodeToBytes([]interface{}{
+		plain.data.AccountNonce, plain.data.Price, plain.data.GasLimit, plain.data.Recipient, plain.data.Amount, plain.data.Payload,
+		plain.data.Action, plain.data.Vote, plain.data.Nickname, plain.data.Asset, plain.data.AssetInfo, plain.data.SubAddress, plain.data.Abi,
+		plain.data.V, plain.data.R, plain.data.S,
+	})
+	if !bytes.Equal(enc, legacy) {
+		t.Fatalf("encoding changed: have %x, want %x", enc, legacy)
+	}
+	var dec Transaction
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Window() != nil || dec.Hash() != plain.Hash() {
+		t.Error("plain transaction changed in decoding")
+	}
+
+	enc, _ = rlp.EncodeToBytes(windowed)
+	dec = Transaction{}
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if w := dec.Window(); w == nil || *w != (TxWindow{ExpiresAt: 100}) {
+		t.Errorf("window mismatch: have %+v", w)
+	}
+	if from, err := Sender(signer, &dec); err != nil || from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("sender mismatch: have %x, %v", from, err)
+	}
+	if dec.Hash() == plain.Hash() {
+		t.Error("window does not change the hash")
+	}
+
+	js, _ := json.Marshal(windowed)
+	var fromJSON Transaction
+	if err := json.Unmarshal(js, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON.Hash() != windowed.Hash() {
+		t.Error("window lost in JSON")
+	}
+
+	if _, err := windowed.WithWindow(TxWindow{NotBefore: 1}); err != errSignedTxExt {
+		t.Errorf("error mismatch: have %v, want %v", err, errSignedTxExt)
+	}
+}
+
+func TestTxExtEmpty(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := NewAuroraSigner(big.NewInt(1))
+	tx, _ := NewTransferTx(TxOptions{Nonce: 3, GasPrice: big.NewInt(1)}, common.Address{1}, big.NewInt(10), "")
+	tx, _ = SignTx(tx, signer, key)
+	tx.data.Ext = []txext{{}}
+
+	enc, _ := rlp.EncodeToBytes(tx)
+	if err := rlp.DecodeBytes(enc, new(Transaction)); err != errEmptyTxExt {
+		t.Errorf("RLP error mismatch: have %v, want %v", err, errEmptyTxExt)
+	}
+	js, _ := json.Marshal(tx)
+	if err := json.Unmarshal(js, new(Transaction)); err != errEmptyTxExt {
+		t.Errorf("JSON error mismatch: have %v, want %v", err, errEmptyTxExt)
+	}
+}
@@ -0,0 +1,145 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+// TransferFrame is the type of the frame of a transfer no opcode made, a leg of
+// a batch transfer.
+const TransferFrame = "TRANSFER"
+
+// FrameTracer is a Tracer that follows the call frames of every depth, and
+// the native transfers and stake changes that run no code.
+type FrameTracer interface {
+	Tracer
+	// CaptureEnter opens a CALL, CALLCODE, DELEGATECALL, STATICCALL or CREATE
+	// frame. asset is nil for AOA.
+	CaptureEnter(op OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int, asset *common.Address)
+	// CaptureExit closes the frame entered last.
+	CaptureExit(output []byte, gasUsed uint64, err error)
+	// CaptureTransfer reports a transfer of TRANSFERASSET or SENDASSET, typed by
+	// the opcode, or a leg of a batch transfer, typed TransferFrame.
+	CaptureTransfer(typ string, from common.Address, to common.Address, asset *common.Address, value *big.Int)
+	// CaptureStake reports the votes cast by account or its registration as a
+	// delegate, with its vote list after them and the change of its locked
+	// balance.
+	CaptureStake(account common.Address, votes []types.Vote, voteList []common.Address, lockDelta *big.Int)
+}
+
+// CallFrame is a call, create or transfer traced by CallTracer.
+type CallFrame struct {
+	Type      string           `json:"type"`
+	From      common.Address   `json:"from"`
+	To        common.Address   `json:"to"`
+	Asset     *common.Address  `json:"asset,omitempty"`
+	Value     *hexutil.Big     `json:"value,omitempty"`
+	Gas       hexutil.Uint64   `json:"gas"`
+	GasUsed   hexutil.Uint64   `json:"gasUsed"`
+	Input     hexutil.Bytes    `json:"input,omitempty"`
+	Output    hexutil.Bytes    `json:"output,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Votes     []types.Vote     `json:"votes,omitempty"`
+	VoteList  []common.Address `json:"voteList,omitempty"`
+	LockDelta *hexutil.Big     `json:"lockDelta,omitempty"`
+	Calls     []*CallFrame     `json:"calls,omitempty"`
+}
+
+// CallTracer records the call tree of a transaction. The frames of a
+// reverted call are kept, with the error in the frame that failed.
+type CallTracer struct {
+	frames []*CallFrame
+	stack  []*CallFrame
+}
+
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// Frames returns the outermost frames of the transaction, usually the one call
+// or create of it. Transactions not running the EVM have none, batch
+// transfers one per transfer.
+func (t *CallTracer) Frames() []*CallFrame {
+	return t.frames
+}
+
+func (t *CallTracer) push(frame *CallFrame) {
+	if len(t.stack) == 0 {
+		t.frames = append(t.frames, frame)
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		parent.Calls = append(parent.Calls, frame)
+	}
+}
+
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+func (t *CallTracer) CaptureEnter(op OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int, asset *common.Address) {
+	frame := &CallFrame{
+		Type:  op.String(),
+		From:  from,
+		To:    to,
+		Asset: asset,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+	t.push(frame)
+	t.stack = append(t.stack, frame)
+}
+
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = hexutil.Uint64(gasUsed)
+	frame.Output = common.CopyBytes(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+func (t *CallTracer) CaptureTransfer(typ string, from common.Address, to common.Address, asset *common.Address, value *big.Int) {
+	t.push(&CallFrame{
+		Type:  typ,
+		From:  from,
+		To:    to,
+		Asset: asset,
+		Value: (*hexutil.Big)(new(big.Int).Set(value)),
+	})
+}
+
+func (t *CallTracer) CaptureStake(account common.Address, votes []types.Vote, voteList []common.Address, lockDelta *big.Int) {
+	if len(t.stack) == 0 {
+		return
+	}
+	frame := t.stack[len(t.stack)-1]
+	frame.Votes = votes
+	if votes != nil {
+		frame.VoteList = append([]common.Address{}, voteList...)
+	}
+	frame.LockDelta = (*hexutil.Big)(new(big.Int).Set(lockDelta))
+}
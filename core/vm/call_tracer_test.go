@@ -0,0 +1,183 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+)
+
+// traceStateDB keeps the balances and code of the accounts of a test, calls to
+// the methods the test does not reach panic on the nil StateDB.
+type traceStateDB struct {
+	StateDB
+	balances map[common.Address]*big.Int
+	assets   map[common.Address]*big.Int
+	locks    map[common.Address]*big.Int
+	code     map[common.Address][]byte
+}
+
+func newTraceStateDB() *traceStateDB {
+	return &traceStateDB{
+		balances: make(map[common.Address]*big.Int),
+		assets:   make(map[common.Address]*big.Int),
+		locks:    make(map[common.Address]*big.Int),
+		code:     make(map[common.Address][]byte),
+	}
+}
+
+func (db *traceStateDB) balance(m map[common.Address]*big.Int, addr common.Address) *big.Int {
+	if m[addr] == nil {
+		m[addr] = new(big.Int)
+	}
+	return m[addr]
+}
+
+func (db *traceStateDB) GetBalance(addr common.Address) *big.Int {
+	return db.balance(db.balances, addr)
+}
+func (db *traceStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	db.balance(db.balances, addr).Add(db.balances[addr], amount)
+}
+func (db *traceStateDB) SubBalance(addr common.Address, amount *big.Int) {
+	db.balance(db.balances, addr).Sub(db.balances[addr], amount)
+}
+func (db *traceStateDB) GetAssetBalance(addr common.Address, asset common.Address) *big.Int {
+	return db.balance(db.assets, addr)
+}
+func (db *traceStateDB) AddAssetBalance(addr common.Address, asset common.Address, amount *big.Int) {
+	db.balance(db.assets, addr).Add(db.assets[addr], amount)
+}
+func (db *traceStateDB) SubAssetBalance(addr common.Address, asset common.Address, amount *big.Int) bool {
+	db.balance(db.assets, addr).Sub(db.assets[addr], amount)
+	return true
+}
+func (db *traceStateDB) GetLockBalance(addr common.Address) *big.Int {
+	return db.balance(db.locks, addr)
+}
+func (db *traceStateDB) AddLockBalance(addr common.Address, amount *big.Int) {
+	db.balance(db.locks, addr).Add(db.locks[addr], amount)
+}
+func (db *traceStateDB) SetRegistrationStake(common.Address, *big.Int) {}
+func (db *traceStateDB) GetCode(addr common.Address) []byte            { return db.code[addr] }
+func (db *traceStateDB) GetCodeHash(addr common.Address) common.Hash {
+	if db.code[addr] == nil {
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(db.code[addr])
+}
+func (db *traceStateDB) Exist(addr common.Address) bool { return true }
+func (db *traceStateDB) Empty(addr common.Address) bool { return false }
+func (db *traceStateDB) Snapshot() int                  { return 0 }
+func (db *traceStateDB) RevertToSnapshot(int, bool)     {}
+
+func newTracedEVM(tracer Tracer) (*EVM, *traceStateDB) {
+	ctx := Context{
+		CanTransfer: func(db StateDB, addr common.Address, asset *common.Address, amount *big.Int) bool {
+			if asset == nil {
+				return db.GetBalance(addr).Cmp(amount) >= 0
+			}
+			return db.GetAssetBalance(addr, *asset).Cmp(amount) >= 0
+		},
+		Transfer: func(db StateDB, sender, recipient common.Address, asset *common.Address, amount *big.Int) {
+			if asset == nil {
+				db.SubBalance(sender, amount)
+				db.AddBalance(recipient, amount)
+			} else {
+				db.SubAssetBalance(sender, *asset, amount)
+				db.AddAssetBalance(recipient, *asset, amount)
+			}
+		},
+		BlockNumber:  big.NewInt(1),
+		DelegateList: &map[common.Address]types.Candidate{},
+	}
+	config := &params.ChainConfig{ChainId: big.NewInt(1), ByzantiumBlock: big.NewInt(0), AthenaBlock: big.NewInt(0), MaxElectDelegate: big.NewInt(101)}
+	statedb := newTraceStateDB()
+	return NewEVM(ctx, statedb, config, Config{Debug: true, Tracer: tracer}), statedb
+}
+
+func TestCallTracer(t *testing.T) {
+	var (
+		tracer   = NewCallTracer()
+		evm, db  = newTracedEVM(tracer)
+		origin   = common.Address{1}
+		contract = common.Address{2}
+		payee    = common.Address{3}
+		asset    = common.Address{4}
+	)
+	db.AddAssetBalance(contract, asset, big.NewInt(10))
+	db.AddBalance(contract, big.NewInt(10))
+
+	// transfer 7 of asset to payee
+	code := append([]byte{byte(PUSH20)}, payee.Bytes()...)
+	code = append(code, byte(PUSH20))
+	code = append(code, asset.Bytes()...)
+	code = append(code, byte(PUSH1), 7, byte(TRANSFERASSET), byte(POP))
+	// call payee with 5 AOA
+	code = append(code, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 0, byte(PUSH1), 5, byte(PUSH20))
+	code = append(code, payee.Bytes()...)
+	code = append(code, byte(PUSH2), 0xff, 0xff, byte(CALL), byte(STOP))
+	db.code[contract] = code
+
+	if _, _, err := evm.Call(AccountRef(origin), contract, nil, 1000000, types.ActionCallContract, new(big.Int)); err != nil {
+		t.Fatal(err)
+	}
+	frames := tracer.Frames()
+	if len(frames) != 1 || frames[0].Type != "CALL" || frames[0].To != contract || frames[0].GasUsed == 0 {
+		t.Fatalf("root frame mismatch: %+v", frames)
+	}
+	calls := frames[0].Calls
+	if len(calls) != 2 {
+		t.Fatalf("inner frame count mismatch: have %d, want 2", len(calls))
+	}
+	if calls[0].Type != "TRANSFERASSET" || calls[0].To != payee || calls[0].Asset == nil || *calls[0].Asset != asset || calls[0].Value.ToInt().Int64() != 7 {
+		t.Errorf("asset transfer mismatch: %+v", calls[0])
+	}
+	if calls[1].Type != "CALL" || calls[1].From != contract || calls[1].Asset != nil || calls[1].Value.ToInt().Int64() != 5 {
+		t.Errorf("inner call mismatch: %+v", calls[1])
+	}
+}
+
+func TestCallTracerRegister(t *testing.T) {
+	var (
+		tracer  = NewCallTracer()
+		evm, db = newTracedEVM(tracer)
+		origin  = common.Address{1}
+		cost, _ = new(big.Int).SetString(params.TxGasAgentCreation, 10)
+	)
+	db.AddBalance(origin, cost)
+
+	if _, _, err := evm.Call(AccountRef(origin), origin, nil, 100000, types.ActionRegister, new(big.Int)); err != nil {
+		t.Fatal(err)
+	}
+	frames := tracer.Frames()
+	if len(frames) != 1 || frames[0].LockDelta == nil || frames[0].LockDelta.ToInt().Cmp(cost) != 0 {
+		t.Fatalf("lock delta mismatch: %+v", frames)
+	}
+}
+
+func TestCallTracerBatchTransfer(t *testing.T) {
+	var (
+		tracer = NewCallTracer()
+		evm, _ = newTracedEVM(tracer)
+		asset  = common.Address{4}
+	)
+	evm.RecordInnerTx(common.Address{1}, common.Address{2}, nil, big.NewInt(3))
+	evm.RecordInnerTx(common.Address{1}, common.Address{3}, &asset, big.NewInt(5))
+
+	frames := tracer.Frames()
+	if len(frames) != 2 {
+		t.Fatalf("frame count mismatch: have %d, want 2", len(frames))
+	}
+	for i, frame := range frames {
+		if frame.Type != TransferFrame {
+			t.Errorf("leg %d type mismatch: have %s, want %s", i, frame.Type, TransferFrame)
+		}
+	}
+	if frames[1].Asset == nil || *frames[1].Asset != asset || frames[1].Value.ToInt().Int64() != 5 {
+		t.Errorf("asset leg mismatch: %+v", frames[1])
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/common/math"
+	"github.com/Aurorachain/go-Aurora/core/types"
 	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/crypto/bn256"
 	"github.com/Aurorachain/go-Aurora/params"
@@ -27,8 +28,25 @@ var PrecompiledContracts = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{6}): &bn256Add{},
 	common.BytesToAddress([]byte{7}): &bn256ScalarMul{},
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
+	common.BytesToAddress([]byte{9}): &assetInfo{},
 }
 
+// apolloPrecompiles are the precompiled contracts only callable from the Apollo fork on.
+var apolloPrecompiles = map[common.Address]bool{
+	common.BytesToAddress([]byte{9}): true,
+}
+
+// statefulPrecompiledContract is a precompiled contract that reads the state of the
+// calling EVM. The size of its output depends on the state, so OutputGas is
+// charged for it on top of RequiredGas once it has run.
+type statefulPrecompiledContract interface {
+	PrecompiledContract
+	RunWithState(evm *EVM, input []byte) ([]byte, error)
+	OutputGas(output []byte) uint64
+}
+
+var errPrecompileNeedsState = errors.New("precompiled contract requires state access")
+
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
 	if contract.UseGas(gas) {
@@ -37,6 +55,21 @@ func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contr
 	return nil, ErrOutOfGas
 }
 
+func runStatefulPrecompiledContract(evm *EVM, p statefulPrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
+	gas := p.RequiredGas(input)
+	if !contract.UseGas(gas) {
+		return nil, ErrOutOfGas
+	}
+	ret, err = p.RunWithState(evm, input)
+	if err != nil {
+		return nil, err
+	}
+	if !contract.UseGas(p.OutputGas(ret)) {
+		return nil, ErrOutOfGas
+	}
+	return ret, nil
+}
+
 type ecrecover struct{}
 
 func (c *ecrecover) RequiredGas(input []byte) uint64 {
@@ -308,3 +341,65 @@ func (c *bn256Pairing) Run(input []byte) ([]byte, error) {
 	}
 	return false32Byte, nil
 }
+
+// assetInfo returns the metadata of the native asset given as a 32 byte word. The
+// result is ABI encoded as (address issuer, string name, string symbol,
+// uint256 supply, string desc); unknown assets return empty output.
+type assetInfo struct{}
+
+func (c *assetInfo) RequiredGas(input []byte) uint64 {
+	return params.AssetInfoBaseGas
+}
+
+func (c *assetInfo) OutputGas(output []byte) uint64 {
+	return uint64(len(output)+31) / 32 * params.AssetInfoPerWordGas
+}
+
+func (c *assetInfo) Run(input []byte) ([]byte, error) {
+	return nil, errPrecompileNeedsState
+}
+
+func (c *assetInfo) RunWithState(evm *EVM, input []byte) ([]byte, error) {
+	asset := common.BytesToAddress(getData(input, 0, 32))
+	if !evm.StateDB.Exist(asset) {
+		return nil, nil
+	}
+	info, err := evm.StateDB.GetAssetInfo(asset)
+	if err != nil {
+		return nil, nil
+	}
+	return packAssetInfo(info), nil
+}
+
+func packAssetInfo(info *types.AssetInfo) []byte {
+	var issuer common.Address
+	if info.Issuer != nil {
+		issuer = *info.Issuer
+	}
+	supply := info.Supply
+	if supply == nil {
+		supply = new(big.Int)
+	}
+	var (
+		name   = packString(info.Name)
+		symbol = packString(info.Symbol)
+		desc   = packString(info.Desc)
+		offset = uint64(5 * 32)
+	)
+	ret := make([]byte, 0, offset+uint64(len(name)+len(symbol)+len(desc)))
+	ret = append(ret, common.LeftPadBytes(issuer.Bytes(), 32)...)
+	ret = append(ret, math.PaddedBigBytes(new(big.Int).SetUint64(offset), 32)...)
+	offset += uint64(len(name))
+	ret = append(ret, math.PaddedBigBytes(new(big.Int).SetUint64(offset), 32)...)
+	offset += uint64(len(symbol))
+	ret = append(ret, math.PaddedBigBytes(supply, 32)...)
+	ret = append(ret, math.PaddedBigBytes(new(big.Int).SetUint64(offset), 32)...)
+	ret = append(ret, name...)
+	ret = append(ret, symbol...)
+	return append(ret, desc...)
+}
+
+func packString(s string) []byte {
+	ret := math.PaddedBigBytes(big.NewInt(int64(len(s))), 32)
+	return append(ret, common.RightPadBytes([]byte(s), (len(s)+31)/32*32)...)
+}
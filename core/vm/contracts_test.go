@@ -3,9 +3,13 @@ package vm
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/Aurorachain/go-Aurora/accounts/abi"
 	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
 )
 
 type precompiledTest struct {
@@ -445,3 +449,36 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+func TestPackAssetInfo(t *testing.T) {
+	const definition = `[{"name":"assetInfo","constant":true,"inputs":[],"outputs":[{"name":"issuer","type":"address"},{"name":"name","type":"string"},{"name":"symbol","type":"string"},{"name":"supply","type":"uint256"},{"name":"desc","type":"string"}]}]`
+	issuer := common.HexToAddress("0x1337")
+	info := &types.AssetInfo{
+		Issuer: &issuer,
+		Name:   "Test Dollar",
+		Symbol: "TUSD",
+		Supply: big.NewInt(1000000),
+		Desc:   strings.Repeat("a stable coin used in tests ", 3),
+	}
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Issuer common.Address
+		Name   string
+		Symbol string
+		Supply *big.Int
+		Desc   string
+	}
+	if err := parsed.Unpack(&out, "assetInfo", packAssetInfo(info)); err != nil {
+		t.Fatalf("failed to unpack asset info: %v", err)
+	}
+	if out.Issuer != issuer || out.Name != info.Name || out.Symbol != info.Symbol || out.Supply.Cmp(info.Supply) != 0 || out.Desc != info.Desc {
+		t.Errorf("asset info mismatch: have %+v, want %+v", out, info)
+	}
+	// 5 head words, the name and the symbol take 2 words each, the description 4
+	if gas := new(assetInfo).OutputGas(packAssetInfo(info)); gas != 13*params.AssetInfoPerWordGas {
+		t.Errorf("output gas mismatch: have %d, want %d", gas, 13*params.AssetInfoPerWordGas)
+	}
+}
@@ -22,12 +22,18 @@ type (
 
 	GetHashFunc func(uint64) common.Hash
 	VoteFunc    func(StateDB, common.Address, *big.Int, []types.Vote, *map[common.Address]types.Candidate, int64) error
+
+	// JailedUntilFunc returns the round a jailed delegate is released in, or 0
+	// when it is not jailed.
+	JailedUntilFunc func(common.Address) uint64
 )
 
 func run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContracts
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
+			if sp, ok := p.(statefulPrecompiledContract); ok {
+				return runStatefulPrecompiledContract(evm, sp, input, contract)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -44,6 +50,8 @@ type Context struct {
 
 	Vote VoteFunc
 
+	JailedUntil JailedUntilFunc
+
 	Origin   common.Address
 	GasPrice *big.Int
 
@@ -96,6 +104,13 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 	return evm
 }
 
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	if apolloPrecompiles[addr] && !evm.chainRules.IsApollo {
+		return nil
+	}
+	return PrecompiledContracts[addr]
+}
+
 func (evm *EVM) Cancel() {
 	atomic.StoreInt32(&evm.abort, 1)
 }
@@ -138,9 +153,13 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
 	)
+	tracer := evm.frameTracer()
+	if tracer != nil {
+		tracer.CaptureEnter(CALL, caller.Address(), addr, input, gas, value, asset)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContracts
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && value.Sign() == 0 {
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
 				evm.vmConfig.Tracer.CaptureEnd(ret, 0, 0, nil)
@@ -149,6 +168,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		}
 		evm.StateDB.CreateAccount(addr)
 	}
+	var lockBalance *big.Int
+	if tracer != nil && (action == types.ActionAddVote || action == types.ActionSubVote || action == types.ActionRegister) {
+		lockBalance = evm.lockBalance(caller.Address())
+	}
 	if action == types.ActionAddVote || action == types.ActionSubVote {
 		if len(voteList) == 0 {
 			return nil, gas, errors.New("empty vote list")
@@ -159,11 +182,23 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 			evm.StateDB.RevertToSnapshot(snapshot, evm.chainConfig.IsEpiphron(evm.BlockNumber))
 			return nil, gas, err
 		}
+		if tracer != nil {
+			tracer.CaptureStake(caller.Address(), voteList, evm.StateDB.GetVoteList(caller.Address()), new(big.Int).Sub(evm.lockBalance(caller.Address()), lockBalance))
+		}
 	} else if action == types.ActionRegister {
 
 		if _, ok := (*evm.DelegateList)[caller.Address()]; ok {
 			return nil, gas, errors.New("Address " + caller.Address().Hex() + " have already register delegate")
 		}
+		if evm.chainConfig.IsAthena(evm.BlockNumber) {
+			// the registration cost stays locked until the delegate unregisters
+			evm.StateDB.SubBalance(caller.Address(), registerCost)
+			evm.StateDB.AddLockBalance(caller.Address(), registerCost)
+			evm.StateDB.SetRegistrationStake(caller.Address(), registerCost)
+		}
+		if tracer != nil {
+			tracer.CaptureStake(caller.Address(), nil, nil, new(big.Int).Sub(evm.lockBalance(caller.Address()), lockBalance))
+		}
 	} else {
 		evm.Transfer(evm.StateDB, caller.Address(), to.Address(), asset, value)
 	}
@@ -208,6 +243,10 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		snapshot = evm.StateDB.Snapshot()
 		to       = AccountRef(caller.Address())
 	)
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CALLCODE, caller.Address(), addr, input, gas, value, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	contract := NewContract(caller, to, nil, value, gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
@@ -235,6 +274,10 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		snapshot = evm.StateDB.Snapshot()
 		to       = AccountRef(caller.Address())
 	)
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(DELEGATECALL, caller.Address(), addr, input, gas, nil, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	contract := NewContract(caller, to, nil, nil, gas).AsDelegate()
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
@@ -267,6 +310,10 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
 	)
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(STATICCALL, caller.Address(), addr, input, gas, nil, nil)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 
 	contract := NewContract(caller, to, nil, new(big.Int), gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
@@ -300,6 +347,10 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, asset *commo
 	}
 
 	snapshot := evm.StateDB.Snapshot()
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureEnter(CREATE, caller.Address(), contractAddr, code, gas, value, asset)
+		defer func() { tracer.CaptureExit(ret, gas-leftOverGas, err) }()
+	}
 	evm.StateDB.CreateAccount(contractAddr)
 
 	evm.Transfer(evm.StateDB, caller.Address(), contractAddr, asset, value)
@@ -351,6 +402,32 @@ func (evm *EVM) ChainConfig() *params.ChainConfig { return evm.chainConfig }
 
 func (evm *EVM) Interpreter() *Interpreter { return evm.interpreter }
 
+// RecordInnerTx records a value transfer made outside the interpreter as an inner
+// transaction when inner transaction watching is on.
+func (evm *EVM) RecordInnerTx(from common.Address, to common.Address, asset *common.Address, value *big.Int) {
+	if tracer := evm.frameTracer(); tracer != nil {
+		tracer.CaptureTransfer(TransferFrame, from, to, asset, value)
+	}
+	evm.watchInnerTx(from, to, asset, value)
+}
+
+// lockBalance returns a copy of the locked balance of addr.
+func (evm *EVM) lockBalance(addr common.Address) *big.Int {
+	if lock := evm.StateDB.GetLockBalance(addr); lock != nil {
+		return new(big.Int).Set(lock)
+	}
+	return new(big.Int)
+}
+
+// frameTracer returns the tracer when it follows call frames.
+func (evm *EVM) frameTracer() FrameTracer {
+	if !evm.vmConfig.Debug {
+		return nil
+	}
+	tracer, _ := evm.vmConfig.Tracer.(FrameTracer)
+	return tracer
+}
+
 func (evm *EVM) watchInnerTx(from common.Address, to common.Address, asset *common.Address, value *big.Int) {
 	if evm.WatchInnerTx && evm.vmConfig.WatchInnerTx && big.NewInt(0).Cmp(value) < 0 {
 		itx := types.InnerTx{From: from, To: to, AssetID: asset, Value: new(big.Int).Set(value)}
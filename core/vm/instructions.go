@@ -855,6 +855,9 @@ func opTransferAsset(pc *uint64, evm *EVM, contract *Contract, memory *Memory, s
 		evm.Transfer(evm.StateDB, contract.Address(), toaddr, &asset, value)
 
 		evm.watchInnerTx(contract.Address(),toaddr,&asset,value)
+		if tracer := evm.frameTracer(); tracer != nil {
+			tracer.CaptureTransfer(contract.GetOp(*pc).String(), contract.Address(), toaddr, &asset, value)
+		}
 
 		stack.push(evm.interpreter.intPool.get().SetUint64(1))
 	} else {
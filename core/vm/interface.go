@@ -23,9 +23,11 @@ type StateDB interface {
 	AddAssetBalance(addr common.Address, asset common.Address, amount *big.Int)
 	GetAssets(addr common.Address) []types.Asset
 
-	ValidateAsset(assetInfo types.AssetInfo) error
-	PublishAsset(addr common.Address, assetInfo types.AssetInfo) error
+	ValidateAsset(assetInfo types.AssetInfo, isHermes bool) error
+	PublishAsset(addr common.Address, assetInfo types.AssetInfo, isHermes bool) error
 	GetAssetInfo(common.Address) (*types.AssetInfo, error)
+	MintAsset(issuer common.Address, asset common.Address, to common.Address, amount *big.Int) error
+	BurnAsset(issuer common.Address, asset common.Address, amount *big.Int) error
 
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64)
@@ -40,6 +42,37 @@ type StateDB interface {
 
 	GetVoteList(addr common.Address) []common.Address
 	SetVoteList(addr common.Address, voteList []common.Address)
+	GetVoteStake(voter, candidate common.Address) uint64
+	SetVoteStake(voter, candidate common.Address, stake uint64)
+	CandidateStakerCount(candidate common.Address) uint64
+	CandidateStakers(candidate common.Address, max uint64) []common.Address
+	QueueDeparted(delegate common.Address)
+	GetRegistrationStake(delegate common.Address) *big.Int
+	SetRegistrationStake(delegate common.Address, stake *big.Int)
+	SetCommission(delegate common.Address, commission uint64)
+	ShareReward(payer, delegate common.Address, reward *big.Int) *big.Int
+	GetPendingRewards(voter common.Address, candidates []common.Address) *big.Int
+	ClaimRewards(voter common.Address, candidates []common.Address) *big.Int
+	CurrentRound() uint64
+	HasEvidence(offender common.Address, number uint64) bool
+	AddEvidence(offender common.Address, number uint64, block uint64)
+	BindProducer(delegate, key common.Address, number uint64)
+	GetProducer(delegate common.Address) (common.Address, uint64)
+	ProducerAt(delegate common.Address, number uint64) common.Address
+	ProducerOwner(key common.Address) common.Address
+	GetProxy(nominator common.Address) (common.Address, uint64)
+	SetProxy(nominator, proxy common.Address, stake uint64)
+	ProxiedStake(proxy common.Address) uint64
+	ProxyNominators(proxy common.Address) []common.Address
+	GetProxyShare(proxy, candidate common.Address) uint64
+	SetProxyShare(proxy, candidate common.Address, share uint64)
+	ProxyShares(proxy common.Address) []common.Address
+	CandidateProxies(candidate common.Address) []common.Address
+	SetMultisig(account common.Address, threshold uint64, owners []common.Address)
+	GetMultisig(account common.Address) (uint64, []common.Address)
+	AddUnbonding(addr common.Address, amount *big.Int, release uint64)
+	UnbondingCount(addr common.Address) uint64
+	SlashUnbonding(addr common.Address, after uint64, cut func(amount *big.Int) *big.Int) *big.Int
 
 	SetLockBalance(addr common.Address, amount *big.Int)
 
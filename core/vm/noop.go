@@ -57,3 +57,49 @@ func (NoopStateDB) Snapshot() int
 func (NoopStateDB) AddLog(*types.Log)                                                  {}
 func (NoopStateDB) AddPreimage(common.Hash, []byte)                                    {}
 func (NoopStateDB) ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) {}
+
+func (NoopStateDB) GetVoteStake(voter, candidate common.Address) uint64          { return 0 }
+func (NoopStateDB) SetVoteStake(voter, candidate common.Address, stake uint64)   {}
+func (NoopStateDB) CandidateStakerCount(candidate common.Address) uint64         { return 0 }
+func (NoopStateDB) GetRegistrationStake(delegate common.Address) *big.Int        { return nil }
+func (NoopStateDB) SetRegistrationStake(delegate common.Address, stake *big.Int) {}
+func (NoopStateDB) SetCommission(delegate common.Address, commission uint64)     {}
+func (NoopStateDB) QueueDeparted(delegate common.Address)                        {}
+func (NoopStateDB) CandidateStakers(candidate common.Address, max uint64) []common.Address {
+	return nil
+}
+func (NoopStateDB) ShareReward(payer, delegate common.Address, reward *big.Int) *big.Int {
+	return nil
+}
+func (NoopStateDB) GetPendingRewards(voter common.Address, candidates []common.Address) *big.Int {
+	return nil
+}
+func (NoopStateDB) ClaimRewards(voter common.Address, candidates []common.Address) *big.Int {
+	return nil
+}
+func (NoopStateDB) GetProducer(delegate common.Address) (common.Address, uint64) {
+	return common.Address{}, 0
+}
+func (NoopStateDB) GetProxy(nominator common.Address) (common.Address, uint64) {
+	return common.Address{}, 0
+}
+func (NoopStateDB) CurrentRound() uint64                                              { return 0 }
+func (NoopStateDB) HasEvidence(offender common.Address, number uint64) bool           { return false }
+func (NoopStateDB) AddEvidence(offender common.Address, number uint64, block uint64)  {}
+func (NoopStateDB) BindProducer(delegate, key common.Address, number uint64)          {}
+func (NoopStateDB) ProducerAt(delegate common.Address, number uint64) common.Address  { return delegate }
+func (NoopStateDB) ProducerOwner(key common.Address) common.Address                   { return common.Address{} }
+func (NoopStateDB) SetProxy(nominator, proxy common.Address, stake uint64)            {}
+func (NoopStateDB) ProxiedStake(proxy common.Address) uint64                          { return 0 }
+func (NoopStateDB) ProxyNominators(proxy common.Address) []common.Address             { return nil }
+func (NoopStateDB) GetProxyShare(proxy, candidate common.Address) uint64              { return 0 }
+func (NoopStateDB) SetProxyShare(proxy, candidate common.Address, share uint64)       {}
+func (NoopStateDB) ProxyShares(proxy common.Address) []common.Address                 { return nil }
+func (NoopStateDB) CandidateProxies(candidate common.Address) []common.Address        { return nil }
+func (NoopStateDB) SetMultisig(common.Address, uint64, []common.Address)              {}
+func (NoopStateDB) GetMultisig(common.Address) (uint64, []common.Address)             { return 0, nil }
+func (NoopStateDB) AddUnbonding(addr common.Address, amount *big.Int, release uint64) {}
+func (NoopStateDB) UnbondingCount(addr common.Address) uint64                         { return 0 }
+func (NoopStateDB) SlashUnbonding(addr common.Address, after uint64, cut func(amount *big.Int) *big.Int) *big.Int {
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/log"
 	"github.com/Aurorachain/go-Aurora/params"
+	"math"
 	"math/big"
 	"strings"
 	"github.com/Aurorachain/go-Aurora/consensus/delegatestate"
@@ -23,7 +24,9 @@ const (
 var ErrInvalidSig = errors.New("invalid transaction v, r, s values")
 var big8 = big.NewInt(8)
 
-func CountBlockVote(block *types.Block, delegateList map[string]types.Candidate, db *state.StateDB) types.CandidateWrapper {
+// CountBlockVote sums the vote changes of the block per candidate. Once voting is
+// stake weighted every vote counts with its stake in whole AOA instead of one.
+func CountBlockVote(block *types.Block, delegateList map[string]types.Candidate, db *state.StateDB, stakeWeighted bool) types.CandidateWrapper {
 	log.Info("Start CountBlockVote", "block", block.NumberU64())
 	txs := block.Transactions()
 	candidates := make([]types.VoteCandidate, 0)
@@ -40,38 +43,59 @@ func CountBlockVote(block *types.Block, delegateList map[string]types.Candidate,
 				continue
 			}
 			for _, vote := range votes {
+				weight, err := voteWeight(vote, stakeWeighted)
+				if err != nil {
+					log.Info("Vote_Util stake error:", "err", err)
+					continue
+				}
 				address := strings.ToLower(vote.Candidate.Hex())
 				operation := vote.Operation
 				if operation == 0 {
 					if _, ok := candidateVotes[address]; ok {
-						candidateVotes[address] += 1
+						candidateVotes[address] += weight
 					} else {
-						candidateVotes[address] = 1
+						candidateVotes[address] = weight
 					}
 				} else if operation == 1 {
 					if _, ok := candidateVotes[address]; ok {
-						candidateVotes[address] -= 1
+						candidateVotes[address] -= weight
 					} else {
-						candidateVotes[address] = -1
+						candidateVotes[address] = -weight
 					}
 				}
 			}
 		case types.ActionRegister:
 			candidate := types.VoteCandidate{Address: from, Vote: 0, Nickname: string(tx.Nickname()), Action: register}
 			candidates = append(candidates, candidate)
+		case types.ActionUnregister:
+			candidates = append(candidates, types.VoteCandidate{Address: from, Action: cancel})
+		case types.ActionDoubleSignEvidence:
+			candidates = append(candidates, evidenceCandidates(db, tx.Hash(), func(addr common.Address) bool {
+				_, ok := delegateList[strings.ToLower(addr.Hex())]
+				return ok
+			})...)
 		default:
 			if _, ok := delegateList[from]; ok {
-				registerCost := new(big.Int)
-				registerCost.SetString(params.TxGasAgentCreation, 10)
-				log.Info("VoteUtil deal cancel", "address balance", db.GetBalance(common.HexToAddress(from)), "compare", registerCost)
-				if db.GetBalance(common.HexToAddress(from)).Cmp(registerCost) < 0 {
+				log.Info("VoteUtil deal cancel", "address balance", db.GetBalance(common.HexToAddress(from)))
+				if registrationLapsed(db, common.HexToAddress(from)) {
 					candidate := types.VoteCandidate{Address: from, Action: cancel}
 					candidates = append(candidates, candidate)
 				}
 			}
 		}
+		for candidate, vote := range proxyVotes(db, tx.Hash()) {
+			address := strings.ToLower(candidate.Hex())
+			if _, ok := delegateList[address]; !ok && vote < 0 {
+				continue
+			}
+			candidateVotes[address] += vote
+		}
 	}
+	dropCancelled(candidateVotes, candidates)
 	for address, vote := range candidateVotes {
+		if vote == 0 {
+			continue
+		}
 		var action int
 		if vote < 0 {
 			action = subVote
@@ -87,7 +111,7 @@ func CountBlockVote(block *types.Block, delegateList map[string]types.Candidate,
 	return candidateWrapper
 }
 
-func CountTrxVote(from string, tx *types.Transaction, statedb *state.StateDB, db *delegatestate.DelegateDB) ([]types.VoteCandidate, error) {
+func CountTrxVote(from string, tx *types.Transaction, statedb *state.StateDB, db *delegatestate.DelegateDB, stakeWeighted bool) ([]types.VoteCandidate, error) {
 	candidates := make([]types.VoteCandidate, 0)
 	candidateVotes := make(map[string]int64, 0)
 	switch tx.TxDataAction() {
@@ -98,27 +122,48 @@ func CountTrxVote(from string, tx *types.Transaction, statedb *state.StateDB, db
 			return candidates, err
 		}
 		for _, vote := range votes {
+			weight, err := voteWeight(vote, stakeWeighted)
+			if err != nil {
+				log.Error("Vote_Util stake error:", "err", err)
+				return candidates, err
+			}
 			address := strings.ToLower(vote.Candidate.Hex())
 			operation := vote.Operation
 			if operation == 0 {
 				if _, ok := candidateVotes[address]; ok {
-					candidateVotes[address] += 1
+					candidateVotes[address] += weight
 				} else {
-					candidateVotes[address] = 1
+					candidateVotes[address] = weight
 				}
 			} else if operation == 1 {
 				if _, ok := candidateVotes[address]; ok {
-					candidateVotes[address] -= 1
+					candidateVotes[address] -= weight
 				} else {
-					candidateVotes[address] = -1
+					candidateVotes[address] = -weight
 				}
 			}
 		}
 	case types.ActionRegister:
 		candidate := types.VoteCandidate{Address: from, Vote: 0, Nickname: string(tx.Nickname()), Action: register}
 		candidates = append(candidates, candidate)
+	case types.ActionUnregister:
+		candidates = append(candidates, types.VoteCandidate{Address: from, Action: cancel})
+	case types.ActionDoubleSignEvidence:
+		candidates = append(candidates, evidenceCandidates(statedb, tx.Hash(), db.Exist)...)
+	}
+	// the stake proxied to a voter moves with the vote list in the same tally
+	for candidate, vote := range proxyVotes(statedb, tx.Hash()) {
+		// a cancelled candidate has no tally to take the share from
+		if vote < 0 && !db.Exist(candidate) {
+			continue
+		}
+		candidateVotes[strings.ToLower(candidate.Hex())] += vote
 	}
+	dropCancelled(candidateVotes, candidates)
 	for address, vote := range candidateVotes {
+		if vote == 0 {
+			continue
+		}
 		var action int
 		if vote < 0 {
 			action = subVote
@@ -129,11 +174,8 @@ func CountTrxVote(from string, tx *types.Transaction, statedb *state.StateDB, db
 		candidate := types.VoteCandidate{Address: address, Vote: uint64(vote), Action: action}
 		candidates = append(candidates, candidate)
 	}
-	if db.Exist(common.HexToAddress(from)) {
-		registerCost := new(big.Int)
-		registerCost.SetString(params.TxGasAgentCreation, 10)
-
-		if statedb.GetBalance(common.HexToAddress(from)).Cmp(registerCost) < 0 {
+	if tx.TxDataAction() != types.ActionUnregister && db.Exist(common.HexToAddress(from)) {
+		if registrationLapsed(statedb, common.HexToAddress(from)) {
 			candidate := types.VoteCandidate{Address: from, Action: cancel}
 			candidates = append(candidates, candidate)
 		}
@@ -141,6 +183,42 @@ func CountTrxVote(from string, tx *types.Transaction, statedb *state.StateDB, db
 	return candidates, nil
 }
 
+// dropCancelled removes the vote changes of the candidates that are cancelled,
+// their tally goes with them.
+func dropCancelled(candidateVotes map[string]int64, candidates []types.VoteCandidate) {
+	for _, candidate := range candidates {
+		if candidate.Action == cancel {
+			delete(candidateVotes, candidate.Address)
+		}
+	}
+}
+
+// registrationLapsed reports whether a delegate registered without a locked
+// stake no longer holds the registration cost. Delegates with a locked stake
+// only leave by unregistering.
+func registrationLapsed(statedb *state.StateDB, delegate common.Address) bool {
+	if statedb.GetRegistrationStake(delegate).Sign() > 0 {
+		return false
+	}
+	registerCost := new(big.Int)
+	registerCost.SetString(params.TxGasAgentCreation, 10)
+	return statedb.GetBalance(delegate).Cmp(registerCost) < 0
+}
+
+func voteWeight(vote types.Vote, stakeWeighted bool) (int64, error) {
+	if !stakeWeighted {
+		return 1, nil
+	}
+	units, err := vote.StakeUnits()
+	if err != nil {
+		return 0, err
+	}
+	if units > math.MaxInt64 {
+		return 0, errors.New("vote stake too large")
+	}
+	return int64(units), nil
+}
+
 func recoverPlainPubKey(signHash common.Hash, R, S, Vb *big.Int, homestead bool) ([]byte, error) {
 	if Vb.BitLen() > 8 {
 		return nil, ErrInvalidSig
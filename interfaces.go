@@ -45,6 +45,34 @@ type ChainStateReader interface {
 	GetDelegateList(ctx context.Context, blockNumber *big.Int) ([]types.Candidate, error)
 }
 
+type AssetReader interface {
+	AssetBalanceAt(ctx context.Context, account common.Address, asset common.Address, blockNumber *big.Int) (*big.Int, error)
+	DetailBalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (map[string]string, error)
+	AssetInfo(ctx context.Context, asset common.Address) (*types.AssetInfo, error)
+}
+
+type DelegateReader interface {
+	TopDelegateList(ctx context.Context, blockNumber *big.Int) ([]types.Candidate, error)
+	Delegate(ctx context.Context, account common.Address) (*types.Candidate, error)
+	VotesNumberAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	CandidateVotesAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*VotesNumber, error)
+}
+
+// VotesNumber is the weight of a candidate, i.e. the whole AOA staked on it,
+// and the number of accounts voting for it.
+type VotesNumber struct {
+	Weight uint64 `json:"weight"`
+	Voters uint64 `json:"voters"`
+}
+
+type ContractAbiReader interface {
+	AbiAt(ctx context.Context, account common.Address, blockNumber *big.Int) (string, error)
+}
+
+type InnerTransactionReader interface {
+	InnerTransactions(ctx context.Context, txHash common.Hash) ([]*types.InnerTx, error)
+}
+
 type SyncProgress struct {
 	StartingBlock uint64
 	CurrentBlock  uint64
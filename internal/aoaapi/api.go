@@ -9,13 +9,16 @@ import (
 	"time"
 
 	"bytes"
+	"github.com/Aurorachain/go-Aurora"
 	"github.com/Aurorachain/go-Aurora/accounts"
 	"github.com/Aurorachain/go-Aurora/accounts/keystore"
 	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/common/hexutil"
 	"github.com/Aurorachain/go-Aurora/common/math"
 	"github.com/Aurorachain/go-Aurora/common/ntp"
+	"github.com/Aurorachain/go-Aurora/consensus/delegatestate"
 	"github.com/Aurorachain/go-Aurora/core"
+	"github.com/Aurorachain/go-Aurora/core/state"
 	"github.com/Aurorachain/go-Aurora/core/types"
 	"github.com/Aurorachain/go-Aurora/core/vm"
 	"github.com/Aurorachain/go-Aurora/crypto"
@@ -357,6 +360,28 @@ func (s *PrivateAccountAPI) SignTransaction(ctx context.Context, args SendTxArgs
 	return &SignTransactionResult{data, signed}, nil
 }
 
+// SignMultisig adds the signature of owner, unlocked with passwd, to a
+// transaction of a multisig account. The first owner signs the transaction
+// built by aoa_signTransaction, the others the transaction the one before
+// them returned.
+func (s *PrivateAccountAPI) SignMultisig(ctx context.Context, encodedTx hexutil.Bytes, owner common.Address, passwd string) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	signed, err := addMultisigSignature(s.am, s.b.ChainConfig().ChainId, tx, owner, func(wallet accounts.Wallet, account accounts.Account, hash []byte) ([]byte, error) {
+		return wallet.SignHashWithPassphrase(account, passwd, hash)
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signed}, nil
+}
+
 func signHash(data []byte) []byte {
 	msg := fmt.Sprintf("\x19Aurora Signed Message:\n%d%s", len(data), data)
 	return crypto.Keccak256([]byte(msg))
@@ -444,7 +469,12 @@ func (s *PublicBlockChainAPI) GetDetailBalance(ctx context.Context, address comm
 	result["AOA_balance"] = balance.String()
 	lockBalance := state.GetLockBalance(address)
 	result["AOA_lockBalance"] = lockBalance.String()
+	unbonding := state.GetUnbondingBalance(address)
+	result["AOA_unbonding"] = unbonding.String()
+	// the registration stake is part of the locked balance
+	result["AOA_registrationStake"] = state.GetRegistrationStake(address).String()
 	totalBalance := balance.Add(balance, lockBalance)
+	totalBalance.Add(totalBalance, unbonding)
 	result["AOA_totalBalance"] = totalBalance.String()
 
 	alist := state.GetAssets(address)
@@ -458,6 +488,49 @@ func (s *PublicBlockChainAPI) GetDetailBalance(ctx context.Context, address comm
 }
 
 func (s *PublicBlockChainAPI) GetDelegateList(ctx context.Context, blockNr rpc.BlockNumber) (interface{}, error) {
+	return s.sortedDelegates(ctx, blockNr)
+}
+
+// RPCCandidate is a delegate as reported by the API. Vote is the weight the
+// delegate is ranked by, i.e. the whole AOA staked on it, Voters the number of
+// accounts voting for it, Commission the part of its block rewards it keeps,
+// in basis points, and JailedUntil the round from which a jailed delegate may
+// unjail, zero if it is not jailed. Producer is the key the delegate signs
+// its blocks with and ProducerRotated the block it was bound in, zero if the
+// delegate signs with its account key.
+type RPCCandidate struct {
+	types.Candidate
+	Voters          uint64         `json:"voters"`
+	Commission      uint64         `json:"commission"`
+	JailedUntil     uint64         `json:"jailedUntil"`
+	Producer        common.Address `json:"producer"`
+	ProducerRotated uint64         `json:"producerRotated"`
+}
+
+// delegateStateAt opens the delegate state of the block, which holds the jail
+// status of the delegates.
+func delegateStateAt(b Backend, block *types.Block) (*delegatestate.DelegateDB, error) {
+	return delegatestate.New(block.DelegateRoot(), delegatestate.NewDatabase(b.ChainDb()))
+}
+
+func newRPCCandidate(statedb *state.StateDB, delegatedb *delegatestate.DelegateDB, delegate types.Candidate) RPCCandidate {
+	address := common.HexToAddress(delegate.Address)
+	producer, rotated := statedb.GetProducer(address)
+	if producer == (common.Address{}) {
+		producer = address
+	}
+	return RPCCandidate{
+		Candidate:       delegate,
+		Voters:          statedb.CandidateVoters(address, delegate.Vote),
+		Commission:      statedb.GetCommission(address),
+		JailedUntil:     delegatedb.GetJailedUntil(address),
+		Producer:        producer,
+		ProducerRotated: rotated,
+	}
+}
+
+// sortedDelegates returns the delegates ranked by their weight.
+func (s *PublicBlockChainAPI) sortedDelegates(ctx context.Context, blockNr rpc.BlockNumber) ([]RPCCandidate, error) {
 	block, err := s.b.BlockByNumber(ctx, blockNr)
 	if err != nil {
 		return nil, err
@@ -466,28 +539,41 @@ func (s *PublicBlockChainAPI) GetDelegateList(ctx context.Context, blockNr rpc.B
 	if err != nil {
 		return nil, err
 	}
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	delegatedb, err := delegateStateAt(s.b, block)
+	if err != nil {
+		return nil, err
+	}
 	delegateL := make([]types.Candidate, 0)
 	for _, v := range *delegateList {
 		delegateL = append(delegateL, v)
 	}
 	sort.Sort(types.CandidateSlice(delegateL))
-	return delegateL, nil
+	result := make([]RPCCandidate, len(delegateL))
+	for i, v := range delegateL {
+		result[i] = newRPCCandidate(statedb, delegatedb, v)
+	}
+	return result, statedb.Error()
 }
 
 func (s *PublicBlockChainAPI) GetTopDelegateList(ctx context.Context, blockNr rpc.BlockNumber) (interface{}, error) {
-	block, err := s.b.BlockByNumber(ctx, blockNr)
+	delegateL, err := s.sortedDelegates(ctx, blockNr)
 	if err != nil {
 		return nil, err
 	}
-	delegateList, err := s.b.GetDelegatePoll(block)
-	if err != nil {
-		return nil, err
+	// jailed delegates are not elected unless every delegate is jailed
+	electable := make([]RPCCandidate, 0, len(delegateL))
+	for _, v := range delegateL {
+		if v.JailedUntil == 0 {
+			electable = append(electable, v)
+		}
 	}
-	delegateL := make([]types.Candidate, 0)
-	for _, v := range *delegateList {
-		delegateL = append(delegateL, v)
+	if len(electable) > 0 {
+		delegateL = electable
 	}
-	sort.Sort(types.CandidateSlice(delegateL))
 	if int64(len(delegateL)) < s.b.ChainConfig().MaxElectDelegate.Int64() {
 		return delegateL, nil
 	} else {
@@ -495,17 +581,28 @@ func (s *PublicBlockChainAPI) GetTopDelegateList(ctx context.Context, blockNr rp
 	}
 }
 
-func (s *PublicBlockChainAPI) GetDelegate(address common.Address) interface{} {
-	delegateList, err := s.b.GetDelegatePoll(s.b.CurrentBlock())
+func (s *PublicBlockChainAPI) GetDelegate(ctx context.Context, address common.Address) interface{} {
+	block := s.b.CurrentBlock()
+	delegateList, err := s.b.GetDelegatePoll(block)
 	if err != nil {
 		return err
 	}
 	if delegate, ok := (*delegateList)[address]; ok {
-		return delegate
+		statedb, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(block.Number().Int64()))
+		if statedb == nil || err != nil {
+			return delegate
+		}
+		delegatedb, err := delegateStateAt(s.b, block)
+		if err != nil {
+			return delegate
+		}
+		return newRPCCandidate(statedb, delegatedb, delegate)
 	}
 	return nil
 }
 
+// GetVotesNumber returns the whole AOA the account has locked in votes, which is
+// the weight it adds to the candidates it votes for.
 func (s *PublicBlockChainAPI) GetVotesNumber(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*big.Int, error) {
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
@@ -516,6 +613,143 @@ func (s *PublicBlockChainAPI) GetVotesNumber(ctx context.Context, address common
 	return res, state.Error()
 }
 
+// GetCandidateVotes returns the weight and the voter count of the candidate,
+// both zero if the account is not a candidate.
+func (s *PublicBlockChainAPI) GetCandidateVotes(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*aurora.VotesNumber, error) {
+	block, err := s.b.BlockByNumber(ctx, blockNr)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	delegateList, err := s.b.GetDelegatePoll(block)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	votes := new(aurora.VotesNumber)
+	if delegate, ok := (*delegateList)[address]; ok {
+		votes.Weight = delegate.Vote
+		votes.Voters = statedb.CandidateVoters(address, delegate.Vote)
+	}
+	return votes, statedb.Error()
+}
+
+type RPCUnbonding struct {
+	Amount  *hexutil.Big   `json:"amount"`
+	Release hexutil.Uint64 `json:"release"`
+}
+
+// GetUnbonding returns the withdrawn vote stake of the account that is not
+// spendable yet, with the block at which each part is released.
+func (s *PublicBlockChainAPI) GetUnbonding(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) ([]RPCUnbonding, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	entries := make([]RPCUnbonding, 0)
+	for _, e := range state.GetUnbonding(address) {
+		entries = append(entries, RPCUnbonding{Amount: (*hexutil.Big)(e.Amount), Release: hexutil.Uint64(e.Release)})
+	}
+	return entries, state.Error()
+}
+
+type RPCVoteStake struct {
+	Candidate common.Address `json:"candidate"`
+	Stake     *hexutil.Big   `json:"stake"`
+}
+
+// GetVoteStakes returns the stake the account has on each candidate it votes for.
+func (s *PublicBlockChainAPI) GetVoteStakes(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) ([]RPCVoteStake, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	stakes := make([]RPCVoteStake, 0)
+	for _, candidate := range state.GetVoteList(address) {
+		units := state.GetVoteStake(address, candidate)
+		if units == 0 {
+			units = 1
+		}
+		stake := new(big.Int).Mul(new(big.Int).SetUint64(units), big.NewInt(params.Aoa))
+		stakes = append(stakes, RPCVoteStake{Candidate: candidate, Stake: (*hexutil.Big)(stake)})
+	}
+	return stakes, state.Error()
+}
+
+// RPCProxy is the proxy an account nominated and the stake it locked for it.
+type RPCProxy struct {
+	Proxy common.Address `json:"proxy"`
+	Stake *hexutil.Big   `json:"stake"`
+}
+
+// GetProxy returns the proxy the account nominated, or nil if it has none.
+func (s *PublicBlockChainAPI) GetProxy(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*RPCProxy, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	proxy, units := state.GetProxy(address)
+	if proxy == (common.Address{}) {
+		return nil, state.Error()
+	}
+	stake := new(big.Int).Mul(new(big.Int).SetUint64(units), big.NewInt(params.Aoa))
+	return &RPCProxy{Proxy: proxy, Stake: (*hexutil.Big)(stake)}, state.Error()
+}
+
+// RPCProxiedStake is the stake nominators locked for a proxy and the part of it
+// counted for each candidate.
+type RPCProxiedStake struct {
+	Stake      *hexutil.Big     `json:"stake"`
+	Nominators []common.Address `json:"nominators"`
+	Shares     []RPCVoteStake   `json:"shares"`
+}
+
+// GetMultisig returns the threshold and owners of the multisig account, or nil
+// if it was not created.
+func (s *PublicBlockChainAPI) GetMultisig(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*types.Multisig, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	threshold, owners := state.GetMultisig(address)
+	if threshold == 0 {
+		return nil, state.Error()
+	}
+	return &types.Multisig{Threshold: threshold, Owners: owners}, state.Error()
+}
+
+// GetProxiedStake returns the stake proxied to the account.
+func (s *PublicBlockChainAPI) GetProxiedStake(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*RPCProxiedStake, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	aoa := big.NewInt(params.Aoa)
+	res := &RPCProxiedStake{
+		Stake:      (*hexutil.Big)(new(big.Int).Mul(new(big.Int).SetUint64(state.ProxiedStake(address)), aoa)),
+		Nominators: state.ProxyNominators(address),
+		Shares:     make([]RPCVoteStake, 0),
+	}
+	for _, candidate := range state.ProxyShares(address) {
+		share := new(big.Int).Mul(new(big.Int).SetUint64(state.GetProxyShare(address, candidate)), aoa)
+		res.Shares = append(res.Shares, RPCVoteStake{Candidate: candidate, Stake: (*hexutil.Big)(share)})
+	}
+	return res, state.Error()
+}
+
+// GetPendingRewards returns the block rewards the account has earned by voting
+// and can claim.
+func (s *PublicBlockChainAPI) GetPendingRewards(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	res := state.GetPendingRewards(address, state.GetVoteList(address))
+	return (*hexutil.Big)(res), state.Error()
+}
+
 func (s *PublicBlockChainAPI) GetVotesList(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) ([]common.Address, error) {
 	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
 	if state == nil || err != nil {
@@ -719,6 +953,209 @@ func (s *PublicBlockChainAPI) GetAssetInfo(ctx context.Context, asset common.Add
 
 }
 
+const maxListAssets = 100
+
+// RPCAsset is an asset as reported by the API: its address and its info.
+type RPCAsset struct {
+	Address common.Address `json:"address"`
+	*types.AssetInfo
+}
+
+// GetAssetBySymbol returns the asset registered with symbol, which is matched
+// regardless of case, in the latest state.
+func (s *PublicBlockChainAPI) GetAssetBySymbol(ctx context.Context, symbol string) (*RPCAsset, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(s.b.CurrentBlock().Number().Int64()))
+	if state == nil || err != nil {
+		return nil, err
+	}
+	asset, exist := state.GetAssetBySymbol(symbol)
+	if !exist {
+		return nil, fmt.Errorf("no asset registered with symbol %s", symbol)
+	}
+	ai, err := state.GetAssetInfo(asset)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCAsset{Address: asset, AssetInfo: ai}, nil
+}
+
+// ListAssets returns up to limit assets of the symbol registry in publishing
+// order, from the one at index start on. A limit of zero or above
+// maxListAssets returns maxListAssets entries.
+func (s *PublicBlockChainAPI) ListAssets(ctx context.Context, start uint64, limit uint64) ([]*RPCAsset, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(s.b.CurrentBlock().Number().Int64()))
+	if state == nil || err != nil {
+		return nil, err
+	}
+	if limit == 0 || limit > maxListAssets {
+		limit = maxListAssets
+	}
+	assets := state.ListAssets(start, limit)
+	result := make([]*RPCAsset, 0, len(assets))
+	for _, asset := range assets {
+		ai, err := state.GetAssetInfo(asset)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &RPCAsset{Address: asset, AssetInfo: ai})
+	}
+	return result, nil
+}
+
+const maxAssetHolders = 1000
+
+type RPCAssetHolders struct {
+	IndexedNumber hexutil.Uint64     `json:"indexedNumber"`
+	Holders       []core.AssetHolder `json:"holders"`
+}
+
+// GetAssetHolders returns the holders of the asset as of the last block covered by
+// the asset holder index, ordered by address. The index is updated with every
+// new head, indexedNumber is the block it reflects. A fast-synced node can not
+// resolve the holders of the state it synced, it returns an error instead.
+func (s *PublicBlockChainAPI) GetAssetHolders(ctx context.Context, asset common.Address, offset uint64, limit uint64) (*RPCAssetHolders, error) {
+	reader, err := core.NewAssetHolderReader(s.b.ChainDb(), s.b.AssetHolderIndexer())
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 || limit > maxAssetHolders {
+		limit = maxAssetHolders
+	}
+	holders, err := reader.Holders(asset, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCAssetHolders{IndexedNumber: hexutil.Uint64(reader.Number), Holders: holders}, nil
+}
+
+const maxStatsRounds = 1000
+
+// RPCDelegateStats is the production record of a delegate summed over a range of
+// rounds. Scheduled counts the slots the delegate had in those rounds.
+type RPCDelegateStats struct {
+	Address      common.Address `json:"address"`
+	FromRound    uint64         `json:"fromRound"`
+	ToRound      uint64         `json:"toRound"`
+	Scheduled    uint64         `json:"scheduled"`
+	Produced     uint64         `json:"produced"`
+	Missed       uint64         `json:"missed"`
+	LastProduced uint64         `json:"lastProduced"`
+}
+
+// GetDelegateStats returns the blocks the delegate produced and the slots it
+// missed in the rounds numbered fromRound to toRound, both included. A round is
+// numbered by the block its delegates were elected at, the ShuffleBlockNumber of
+// its blocks, so the numbers are those of blocks and not every one is a round.
+func (s *PublicBlockChainAPI) GetDelegateStats(ctx context.Context, address common.Address, fromRound uint64, toRound uint64) (*RPCDelegateStats, error) {
+	if toRound < fromRound {
+		return nil, errors.New("toRound is before fromRound")
+	}
+	db := s.b.ChainDb()
+	latest, ok := core.GetLatestRound(db)
+	if !ok {
+		return nil, errors.New("no round recorded yet")
+	}
+	if toRound > latest {
+		toRound = latest
+	}
+	stats := &RPCDelegateStats{Address: address, FromRound: fromRound, ToRound: toRound, LastProduced: core.GetDelegateLastProduced(db, address)}
+	// walk the rounds back from toRound, or from the latest one if toRound
+	// numbers no round
+	round := latest
+	if core.GetRoundStats(db, toRound) != nil {
+		round = toRound
+	}
+	for walked := 0; round >= fromRound; walked++ {
+		if walked == maxStatsRounds {
+			return nil, fmt.Errorf("at most %d rounds can be queried at once", maxStatsRounds)
+		}
+		rs := core.GetRoundStats(db, round)
+		if rs == nil {
+			break
+		}
+		if rs.Round <= toRound {
+			for _, slot := range rs.Slots {
+				if slot.Address == address {
+					stats.Scheduled++
+					stats.Produced += slot.Produced
+					stats.Missed += slot.Missed
+				}
+			}
+		}
+		if rs.Prev >= round {
+			break
+		}
+		round = rs.Prev
+	}
+	return stats, nil
+}
+
+// RPCRoundStats is the production record of a round with its totals.
+type RPCRoundStats struct {
+	*core.RoundStats
+	Produced uint64 `json:"produced"`
+	Missed   uint64 `json:"missed"`
+}
+
+// GetRoundStats returns the production record of the round, or of the latest
+// round if none is given.
+func (s *PublicBlockChainAPI) GetRoundStats(ctx context.Context, round *uint64) (*RPCRoundStats, error) {
+	db := s.b.ChainDb()
+	number, ok := core.GetLatestRound(db)
+	if !ok {
+		return nil, errors.New("no round recorded yet")
+	}
+	if round != nil {
+		number = *round
+	}
+	rs := core.GetRoundStats(db, number)
+	if rs == nil {
+		return nil, fmt.Errorf("round %d not found", number)
+	}
+	return &RPCRoundStats{RoundStats: rs, Produced: rs.Produced(), Missed: rs.Missed()}, nil
+}
+
+// GetAssetHolderCount returns the number of holders of the asset as of the last
+// block covered by the asset holder index, see GetAssetHolders.
+func (s *PublicBlockChainAPI) GetAssetHolderCount(ctx context.Context, asset common.Address) (uint64, error) {
+	reader, err := core.NewAssetHolderReader(s.b.ChainDb(), s.b.AssetHolderIndexer())
+	if err != nil {
+		return 0, err
+	}
+	return reader.HolderCount(asset)
+}
+
+// GetAssetSupply returns the supply figures of the asset: its total supply, the
+// balance of its issuer and the rest in circulation. Supplies are read from the
+// latest state, the holder count from the asset holder index. The holder count is
+// left out while the index can not answer, see GetAssetHolders.
+func (s *PublicBlockChainAPI) GetAssetSupply(ctx context.Context, asset common.Address) (map[string]interface{}, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(s.b.CurrentBlock().Number().Int64()))
+	if state == nil || err != nil {
+		return nil, err
+	}
+	ai, err := state.GetAssetInfo(asset)
+	if err != nil {
+		return nil, err
+	}
+	issuerBalance := new(big.Int)
+	if ai.Issuer != nil {
+		issuerBalance = state.GetAssetBalance(*ai.Issuer, asset)
+	}
+	result := map[string]interface{}{
+		"totalSupply":       (*hexutil.Big)(ai.Supply),
+		"issuerBalance":     (*hexutil.Big)(issuerBalance),
+		"circulatingSupply": (*hexutil.Big)(new(big.Int).Sub(ai.Supply, issuerBalance)),
+	}
+	if reader, err := core.NewAssetHolderReader(s.b.ChainDb(), s.b.AssetHolderIndexer()); err == nil {
+		if count, err := reader.HolderCount(asset); err == nil {
+			result["holderCount"] = count
+			result["indexedNumber"] = hexutil.Uint64(reader.Number)
+		}
+	}
+	return result, nil
+}
+
 type ExecutionResult struct {
 	Gas         uint64         `json:"gas"`
 	Failed      bool           `json:"failed"`
@@ -843,6 +1280,9 @@ type RPCTransaction struct {
 	AssetInfo        *SendTxAssetInfo `json:"assetInfo,omitempty"`
 	SubAddress       string           `json:"subAddress,omitempty"`
 	Abi              string           `json:"abi,omitempty"`
+	Window           *types.TxWindow  `json:"window,omitempty"`
+	FeePayer         *common.Address  `json:"feePayer,omitempty"`
+	Multisig         *types.Multisig  `json:"multisig,omitempty"`
 }
 
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
@@ -882,6 +1322,9 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 	result.Votes = votes
 
 	result.Asset = tx.Asset()
+	result.Window = tx.Window()
+	result.FeePayer = tx.Payer()
+	result.Multisig = tx.Multisig()
 	ai := tx.AssetInfo()
 	if nil != ai {
 		result.AssetInfo = &SendTxAssetInfo{Supply: (*hexutil.Big)(ai.Supply), Name: ai.Name, Symbol: ai.Symbol, Desc: ai.Desc}
@@ -1097,6 +1540,94 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 	return wallet.SignTx(account, tx, chainID)
 }
 
+// signFeePayer co-signs the sender-signed tx as its fee payer, whose account
+// must be unlocked on this node.
+func signFeePayer(am *accounts.Manager, chainID *big.Int, tx *types.Transaction) (*types.Transaction, error) {
+	if tx.Payer() == nil {
+		return nil, errors.New("transaction has no fee payer")
+	}
+	account := accounts.Account{Address: *tx.Payer()}
+
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("fee payer %s: %v", account.Address.Hex(), err)
+	}
+	signer := types.NewAuroraSigner(chainID)
+	sig, err := wallet.SignHash(account, types.PayerHash(signer, tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("fee payer %s: %v", account.Address.Hex(), err)
+	}
+	return tx.WithPayerSignature(signer, sig)
+}
+
+// signProducerProof signs the binding of producer to delegate with the producer
+// key, which has to be unlocked on this node when no signature is given.
+func signProducerProof(am *accounts.Manager, chainID *big.Int, producer, delegate common.Address) ([]byte, error) {
+	account := accounts.Account{Address: producer}
+
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("producer %s: %v", producer.Hex(), err)
+	}
+	sig, err := wallet.SignHash(account, types.ProducerProofHash(chainID, delegate).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("producer %s: %v", producer.Hex(), err)
+	}
+	return sig, nil
+}
+
+// multisigSignFn signs hash with the key of owner.
+type multisigSignFn func(wallet accounts.Wallet, owner accounts.Account, hash []byte) ([]byte, error)
+
+// addMultisigSignature adds the signature of owner to the transaction of a
+// multisig account.
+func addMultisigSignature(am *accounts.Manager, chainID *big.Int, tx *types.Transaction, owner common.Address, sign multisigSignFn) (*types.Transaction, error) {
+	account := accounts.Account{Address: owner}
+
+	wallet, err := am.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.NewAuroraSigner(chainID)
+	sig, err := sign(wallet, account, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithMultisigSignature(signer, sig)
+}
+
+// signMultisigUnlocked adds the signatures of the owners whose accounts are
+// unlocked on this node to the transaction of a multisig account, up to its
+// threshold. It returns the number of owners that signed it then.
+func signMultisigUnlocked(am *accounts.Manager, chainID *big.Int, tx *types.Transaction) (*types.Transaction, int, error) {
+	signers, err := types.MultisigSigners(types.NewAuroraSigner(chainID), tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	signed := make(map[common.Address]bool)
+	for _, owner := range signers {
+		signed[owner] = true
+	}
+	m := tx.Multisig()
+	for _, owner := range m.Owners {
+		if uint64(len(signed)) == m.Threshold {
+			break
+		}
+		if signed[owner] {
+			continue
+		}
+		next, err := addMultisigSignature(am, chainID, tx, owner, func(wallet accounts.Wallet, account accounts.Account, hash []byte) ([]byte, error) {
+			return wallet.SignHash(account, hash)
+		})
+		if err != nil {
+			// the owner is not held here or locked
+			continue
+		}
+		tx, signed[owner] = next, true
+	}
+	return tx, len(signed), nil
+}
+
 type SendTxArgs struct {
 	From     common.Address  `json:"from"`
 	To       string          `json:"to"`
@@ -1105,15 +1636,33 @@ type SendTxArgs struct {
 	Value    *hexutil.Big    `json:"value"`
 	Nonce    *hexutil.Uint64 `json:"nonce"`
 
-	Data       *hexutil.Bytes   `json:"data"`
-	Input      *hexutil.Bytes   `json:"input"`
-	Action     uint64           `json:"action"`
-	Vote       []types.Vote     `json:"vote"`
-	Nickname   string           `json:"nickname"`
-	Asset      *common.Address  `json:"asset"`
-	AssetInfo  *SendTxAssetInfo `json:"assetInfo,omitempty"`
-	SubAddress string           `json:"subAddress,omitempty"`
-	Abi        string           `json:"abi,omitempty"`
+	Data              *hexutil.Bytes            `json:"data"`
+	Input             *hexutil.Bytes            `json:"input"`
+	Action            uint64                    `json:"action"`
+	Vote              []types.Vote              `json:"vote"`
+	Nickname          string                    `json:"nickname"`
+	Asset             *common.Address           `json:"asset"`
+	AssetInfo         *SendTxAssetInfo          `json:"assetInfo,omitempty"`
+	SubAddress        string                    `json:"subAddress,omitempty"`
+	Abi               string                    `json:"abi,omitempty"`
+	Transfers         []SendTxTransfer          `json:"transfers,omitempty"`
+	Commission        *hexutil.Uint64           `json:"commission,omitempty"`
+	Evidence          *types.DoubleSignEvidence `json:"evidence,omitempty"`
+	Producer          *common.Address           `json:"producer,omitempty"`
+	ProducerSignature *hexutil.Bytes            `json:"producerSignature,omitempty"`
+	Proxy             *common.Address           `json:"proxy,omitempty"`
+	Window            *types.TxWindow           `json:"window,omitempty"`
+	FeePayer          *common.Address           `json:"feePayer,omitempty"`
+	Multisig          *types.Multisig           `json:"multisig,omitempty"`
+	Owners            []common.Address          `json:"owners,omitempty"`
+	Threshold         *hexutil.Uint64           `json:"threshold,omitempty"`
+	Voters            []common.Address          `json:"voters,omitempty"`
+}
+
+type SendTxTransfer struct {
+	To     common.Address  `json:"to"`
+	Asset  *common.Address `json:"asset"`
+	Amount *hexutil.Big    `json:"amount"`
 }
 
 type SendTxAssetInfo struct {
@@ -1126,40 +1675,281 @@ type SendTxAssetInfo struct {
 
 func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 
-	if args.Action > types.ActionCallContract {
+	if args.Action > types.ActionReleaseVotes {
 		return fmt.Errorf("Illegal action: %d", args.Action)
 	}
-
-	if args.To != "" {
-		match, err := regexp.MatchString("(?i:^AOA|0x)[0-9a-fA-F]{40}[0-9A-Za-z]{0,32}$", args.To)
-		if err != nil {
+	// the signatures of the owners are paid on top of the default gas
+	multisigGas := args.Multisig != nil && args.Gas == nil
+	if args.Multisig != nil {
+		if err := args.Multisig.Validate(); err != nil {
 			return err
 		}
-		if !match {
-			return errors.New("Invalid send address " + args.To)
+		if args.From == (common.Address{}) {
+			args.From = args.Multisig.Address()
+		} else if args.From != args.Multisig.Address() {
+			return errors.New("from is not the address of the multisig account")
 		}
-		if len(args.To) > strictAddresLength {
-			to := args.To
-			subAddress := args.To[:(len(args.To) - subAddressLength)] + strings.ToLower(args.To[(len(args.To) - subAddressLength):])
-			match, err := regexp.MatchString("(?i:^AOA|0x)[0-9a-fA-F]{40}[0-9A-Za-z]{0,32}$", subAddress)
+	}
+	if args.Action == types.ActionCreateMultisig {
+		if args.Threshold == nil {
+			return errors.New(`Action is "ActionCreateMultisig" but the threshold is nil.`)
+		}
+		m, err := types.NewMultisig(uint64(*args.Threshold), args.Owners)
+		if err != nil {
+			return err
+		}
+		if args.Gas == nil {
+			data, err := types.MultisigToBytes(m)
 			if err != nil {
 				return err
 			}
-			if !match {
-				return errors.New("Invalid send address " + args.To)
+			gas, err := core.IntrinsicGas(data, args.Action)
+			if err != nil {
+				return err
 			}
-			args.To = subAddress[:(len(args.To) - subAddressLength)]
-			args.SubAddress = common.HexToAddress(args.To).Hex() + strings.ToLower(to[(len(to) - subAddressLength):])
-		}
-		if args.SubAddress != "" {
-			args.SubAddress = common.HexToAddress(args.To).Hex() + strings.ToLower(args.SubAddress[(len(args.SubAddress) - subAddressLength):])
+			args.Gas = (*hexutil.Uint64)(&gas)
 		}
+		args.To = m.Address().Hex()
 	}
-
-	if args.Action == 0 {
-		if args.To == "" {
-			args.Action = types.ActionCreateContract
-			if nil == args.Data || (len(*args.Data) == 0 && len(*args.Input) == 0) {
+	if args.Action == types.ActionBatchTransfer {
+		if len(args.Transfers) == 0 {
+			return errors.New(`Action is "ActionBatchTransfer" but the transfer list is empty.`)
+		}
+		data, err := types.BatchTransfersToBytes(args.batchTransfers())
+		if err != nil {
+			return err
+		}
+		if args.Gas == nil {
+			gas, err := core.IntrinsicGas(data, args.Action)
+			if err != nil {
+				return err
+			}
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+		args.Data = (*hexutil.Bytes)(&data)
+		args.Input = nil
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionSetCommission {
+		if args.Commission == nil {
+			return errors.New(`Action is "ActionSetCommission" but the commission is nil.`)
+		}
+		if uint64(*args.Commission) > types.MaxCommission {
+			return fmt.Errorf("commission must not exceed %d basis points", types.MaxCommission)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionClaimRewards {
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		voteList := state.GetVoteList(args.From)
+		if state.GetPendingRewards(args.From, voteList).Sign() == 0 {
+			return core.ErrNoRewards
+		}
+		if args.Gas == nil {
+			gas := params.TxGas + uint64(len(voteList))*params.ClaimRewardGas
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionReleaseVotes {
+		data, err := types.VotersToBytes(args.Voters)
+		if err != nil {
+			return err
+		}
+		if _, err := types.BytesToVoters(data); err != nil {
+			return err
+		}
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		delegates, err := b.GetDelegatePoll(b.CurrentBlock())
+		if err != nil {
+			return err
+		}
+		gas := core.ReleaseVotesGas(state, args.Voters, *delegates)
+		if gas == 0 {
+			return core.ErrNoStaleVotes
+		}
+		if args.Gas == nil {
+			intrGas, err := core.IntrinsicGas(data, args.Action)
+			if err != nil {
+				return err
+			}
+			gas += intrGas
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionUnjail {
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		delegatedb, err := delegateStateAt(b, b.CurrentBlock())
+		if err != nil {
+			return err
+		}
+		until := delegatedb.GetJailedUntil(args.From)
+		if until == 0 {
+			return core.ErrNotJailed
+		}
+		if state.CurrentRound() < until {
+			return fmt.Errorf("%v until round %d", core.ErrJailed, until)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionDoubleSignEvidence {
+		if args.Evidence == nil {
+			return errors.New(`Action is "ActionDoubleSignEvidence" but the evidence is nil.`)
+		}
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		offender, err := args.Evidence.Offender(core.ProducerResolver(state))
+		if err != nil {
+			return err
+		}
+		if state.HasEvidence(offender, args.Evidence.Number()) {
+			return core.ErrDuplicateEvidence
+		}
+		if args.Gas == nil {
+			data, err := types.EvidenceToBytes(args.Evidence)
+			if err != nil {
+				return err
+			}
+			gas, err := core.IntrinsicGas(data, args.Action)
+			if err != nil {
+				return err
+			}
+			delegates, err := b.GetDelegatePoll(b.CurrentBlock())
+			if err != nil {
+				return err
+			}
+			// the voters of a delegate still registered are withdrawn as on
+			// unregistering
+			_, registered := (*delegates)[offender]
+			gas += core.EvidenceGas(state, offender, registered)
+			if registered {
+				gas += core.ProxyRecountGas(state, offender)
+			}
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionSetProducer {
+		if args.Producer == nil {
+			return errors.New(`Action is "ActionSetProducer" but the producer is nil.`)
+		}
+		if args.ProducerSignature == nil {
+			sig, err := signProducerProof(b.AccountManager(), b.ChainConfig().ChainId, *args.Producer, args.From)
+			if err != nil {
+				return err
+			}
+			args.ProducerSignature = (*hexutil.Bytes)(&sig)
+		}
+		proof := &types.ProducerProof{Producer: *args.Producer, Signature: *args.ProducerSignature}
+		if err := proof.Verify(b.ChainConfig().ChainId, args.From); err != nil {
+			return err
+		}
+		args.To = args.From.Hex()
+	}
+	if (args.Action == types.ActionAddVote || args.Action == types.ActionSubVote) && args.Gas == nil {
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		if state.ProxiedStake(args.From) > 0 {
+			// the stake proxied to the sender follows its new vote list
+			gas := params.TxGas + core.ProxySyncGas(state, args.From, len(args.Vote))
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+	}
+	if args.Action == types.ActionSetProxy || args.Action == types.ActionRevokeProxy {
+		state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+		if state == nil || err != nil {
+			return err
+		}
+		proxy, _ := state.GetProxy(args.From)
+		if args.Action == types.ActionSetProxy {
+			if args.Proxy == nil {
+				return errors.New(`Action is "ActionSetProxy" but the proxy is nil.`)
+			}
+			if args.Value == nil {
+				return errors.New(`Action is "ActionSetProxy" but the value is nil.`)
+			}
+			proxy = *args.Proxy
+			args.To = proxy.Hex()
+		} else {
+			if proxy == (common.Address{}) {
+				return core.ErrNoProxy
+			}
+			args.To = args.From.Hex()
+		}
+		if args.Gas == nil {
+			// the proxied stake is counted anew for every candidate of the proxy
+			gas := params.TxGas + core.ProxySyncGas(state, proxy, 0)
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+	}
+	if args.Action == types.ActionUnregister {
+		if args.Gas == nil {
+			state, _, err := b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(b.CurrentBlock().Number().Int64()))
+			if state == nil || err != nil {
+				return err
+			}
+			// the voters withdrawn along with the delegate
+			gas := params.TxGas + core.UnregisterGas(state, args.From) + core.ProxyRecountGas(state, args.From)
+			args.Gas = (*hexutil.Uint64)(&gas)
+		}
+		args.To = args.From.Hex()
+	}
+	if args.Action == types.ActionMintAsset || args.Action == types.ActionBurnAsset {
+		if args.Asset == nil {
+			return core.ErrAssetNil
+		}
+		if args.Value == nil || args.Value.ToInt().Sign() <= 0 {
+			return core.ErrAssetAmount
+		}
+		if args.Action == types.ActionMintAsset && args.To == "" {
+			args.To = args.From.Hex()
+		}
+	}
+
+	if args.To != "" {
+		match, err := regexp.MatchString("(?i:^AOA|0x)[0-9a-fA-F]{40}[0-9A-Za-z]{0,32}$", args.To)
+		if err != nil {
+			return err
+		}
+		if !match {
+			return errors.New("Invalid send address " + args.To)
+		}
+		if len(args.To) > strictAddresLength {
+			to := args.To
+			subAddress := args.To[:(len(args.To) - subAddressLength)] + strings.ToLower(args.To[(len(args.To) - subAddressLength):])
+			match, err := regexp.MatchString("(?i:^AOA|0x)[0-9a-fA-F]{40}[0-9A-Za-z]{0,32}$", subAddress)
+			if err != nil {
+				return err
+			}
+			if !match {
+				return errors.New("Invalid send address " + args.To)
+			}
+			args.To = subAddress[:(len(args.To) - subAddressLength)]
+			args.SubAddress = common.HexToAddress(args.To).Hex() + strings.ToLower(to[(len(to) - subAddressLength):])
+		}
+		if args.SubAddress != "" {
+			args.SubAddress = common.HexToAddress(args.To).Hex() + strings.ToLower(args.SubAddress[(len(args.SubAddress) - subAddressLength):])
+		}
+	}
+
+	if args.Action == 0 {
+		if args.To == "" {
+			args.Action = types.ActionCreateContract
+			if nil == args.Data || (len(*args.Data) == 0 && len(*args.Input) == 0) {
 				return errors.New("Create contract but data is nil")
 			}
 		} else if (nil != args.Input && len(*args.Input) > 0) || (nil != args.Data && len(*args.Data) > 0) {
@@ -1170,6 +1960,9 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		args.Gas = new(hexutil.Uint64)
 		*(*uint64)(args.Gas) = defaultGas(args.Action)
 	}
+	if multisigGas {
+		*(*uint64)(args.Gas) += types.MultisigGas(args.Multisig)
+	}
 
 	if (uint64)(*args.Gas) > params.MaxOneContractGasLimit {
 		return errors.New("Gas Over Limit!")
@@ -1210,6 +2003,11 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 			return core.ErrRegister
 		}
 	}
+	if args.Action == types.ActionUnregister || args.Action == types.ActionSetCommission || args.Action == types.ActionSetProducer {
+		if _, ok := delegateList[args.From]; !ok {
+			return core.ErrUnregister
+		}
+	}
 
 	if args.Action == types.ActionAddVote || args.Action == types.ActionSubVote {
 		if len(args.Vote) == 0 {
@@ -1219,20 +2017,27 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 		if state == nil || err != nil {
 			return err
 		}
-		voteList := state.GetVoteList(args.From)
-		amount, err := countVoteCost(voteList, args.Vote, delegateList)
-
-		if err != nil {
-			return err
-		}
-		if amount.Int64() > 0 {
-			args.Action = types.ActionAddVote
-			args.Value.ToInt().SetInt64(amount.Int64())
+		next := new(big.Int).Add(b.CurrentBlock().Number(), common.Big1)
+		if b.ChainConfig().IsAthena(next) {
+			if err := args.setStakeVoteDefaults(state, delegateList); err != nil {
+				return err
+			}
 		} else {
-			args.Action = types.ActionSubVote
-			args.Value.ToInt().SetInt64(-amount.Int64())
+			voteList := state.GetVoteList(args.From)
+			amount, err := countVoteCost(voteList, args.Vote, delegateList)
+
+			if err != nil {
+				return err
+			}
+			if amount.Int64() > 0 {
+				args.Action = types.ActionAddVote
+				args.Value.ToInt().SetInt64(amount.Int64())
+			} else {
+				args.Action = types.ActionSubVote
+				args.Value.ToInt().SetInt64(-amount.Int64())
+			}
+			args.Value.ToInt().Mul(args.Value.ToInt(), big.NewInt(params.Aoa))
 		}
-		args.Value.ToInt().Mul(args.Value.ToInt(), big.NewInt(params.Aoa))
 	}
 	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
 		return errors.New(`Both "data" and "input" are set and not equal. Please use "input" to pass transaction call data.`)
@@ -1253,12 +2058,39 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 	return nil
 }
 
+// setStakeVoteDefaults fills the stake of removed candidates that the caller
+// left out and derives the action and value of a stake weighted vote.
+func (args *SendTxArgs) setStakeVoteDefaults(statedb *state.StateDB, delegateList map[common.Address]types.Candidate) error {
+	for i, vote := range args.Vote {
+		if vote.Operation == 1 && vote.Amount == nil && vote.Candidate != nil {
+			if stake := statedb.GetVoteStake(args.From, *vote.Candidate); stake > 1 {
+				args.Vote[i].Amount = new(big.Int).Mul(new(big.Int).SetUint64(stake), big.NewInt(params.Aoa))
+			}
+		}
+	}
+	_, _, diff, err := core.ChangeStakeVoteList(statedb, args.From, args.Vote, delegateList)
+	if err != nil {
+		return err
+	}
+	if diff.Sign() > 0 {
+		args.Action = types.ActionAddVote
+	} else {
+		args.Action = types.ActionSubVote
+	}
+	args.Value.ToInt().Abs(diff)
+	return nil
+}
+
 func defaultGas(action uint64) uint64 {
 	switch action {
 	case types.ActionTrans:
 		return params.TxGas
 	case types.ActionPublishAsset:
 		return params.TxGasAssetPublish
+	case types.ActionMintAsset:
+		return params.TxGasAssetMint
+	case types.ActionBurnAsset:
+		return params.TxGasAssetBurn
 	default:
 		return 90000
 	}
@@ -1308,38 +2140,79 @@ func sliceContains(address common.Address, slice []common.Address) (int, bool) {
 
 func (args *SendTxArgs) toTransaction() (*types.Transaction, error) {
 	var input []byte
-	switch args.Action {
-	case types.ActionAddVote, types.ActionSubVote, types.ActionRegister, types.ActionPublishAsset:
-		args.To = args.From.Hex()
-	}
 	if args.Data != nil {
 		input = *args.Data
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	opts := types.TxOptions{Nonce: uint64(*args.Nonce), GasPrice: (*big.Int)(args.GasPrice), GasLimit: uint64(*args.Gas), Window: args.Window, Payer: args.FeePayer, Multisig: args.Multisig}
+	value := (*big.Int)(args.Value)
 
-	if args.Action == types.ActionCreateContract {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, args.Abi, args.Asset), nil
-	}
-	voteByte, err := types.VoteToBytes(args.Vote)
-	if err != nil {
-		return nil, err
-	}
-
-	var ai []byte
-	if args.Action == types.ActionPublishAsset {
-		assetInfoBytes, err := types.AssetInfoToBytes(*args.AssetInfo.assetinfo)
+	switch args.Action {
+	case types.ActionRegister:
+		// the registration cost is taken from the balance, not the value
+		if value != nil && value.Sign() != 0 {
+			return nil, errors.New("register must not carry a value")
+		}
+		return types.NewRegisterTx(opts, args.From, args.Nickname)
+	case types.ActionUnregister:
+		return types.NewUnregisterTx(opts, args.From)
+	case types.ActionSetCommission:
+		return types.NewSetCommissionTx(opts, args.From, uint64(*args.Commission))
+	case types.ActionClaimRewards:
+		return types.NewClaimRewardsTx(opts, args.From)
+	case types.ActionUnjail:
+		return types.NewUnjailTx(opts, args.From)
+	case types.ActionDoubleSignEvidence:
+		return types.NewEvidenceTx(opts, args.From, args.Evidence)
+	case types.ActionSetProducer:
+		return types.NewSetProducerTx(opts, args.From, &types.ProducerProof{Producer: *args.Producer, Signature: *args.ProducerSignature})
+	case types.ActionSetProxy:
+		return types.NewSetProxyTx(opts, *args.Proxy, value)
+	case types.ActionRevokeProxy:
+		return types.NewRevokeProxyTx(opts, args.From)
+	case types.ActionCreateMultisig:
+		m, err := types.NewMultisig(uint64(*args.Threshold), args.Owners)
 		if err != nil {
 			return nil, err
 		}
-		ai = assetInfoBytes
+		return types.NewCreateMultisigTx(opts, m)
+	case types.ActionReleaseVotes:
+		return types.NewReleaseVotesTx(opts, args.From, args.Voters)
+	case types.ActionAddVote, types.ActionSubVote:
+		return types.NewVoteTx(opts, args.From, args.Vote)
+	case types.ActionPublishAsset:
+		return types.NewPublishAssetTx(opts, args.From, args.AssetInfo.assetinfo)
+	case types.ActionBurnAsset:
+		return types.NewBurnAssetTx(opts, args.From, *args.Asset, value)
+	case types.ActionBatchTransfer:
+		return types.NewBatchTransferTx(opts, args.From, args.batchTransfers())
+	case types.ActionCreateContract:
+		return types.NewContractCreationTx(opts, input, args.Abi, args.Asset, value)
 	}
 
 	if !common.IsHexAddress(args.To) && !common.IsAOAAddress(args.To) {
 		return nil, errors.New("Invalid receiver address " + args.To + args.SubAddress)
 	}
+	to := common.HexToAddress(args.To)
+	switch args.Action {
+	case types.ActionMintAsset:
+		return types.NewMintAssetTx(opts, to, *args.Asset, value)
+	case types.ActionCallContract:
+		return types.NewContractCallTx(opts, to, input, args.Asset, value)
+	}
+	if args.Asset != nil && (*args.Asset != common.Address{}) {
+		return types.NewAssetTransferTx(opts, to, *args.Asset, value, args.SubAddress)
+	}
+	return types.NewTransferTx(opts, to, value, args.SubAddress)
+}
 
-	return types.NewTransaction(uint64(*args.Nonce), common.HexToAddress(args.To), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, args.Action, voteByte, []byte(args.Nickname), args.Asset, ai, args.SubAddress), nil
+func (args *SendTxArgs) batchTransfers() []types.BatchTransfer {
+	transfers := make([]types.BatchTransfer, 0, len(args.Transfers))
+	for _, t := range args.Transfers {
+		transfers = append(transfers, types.BatchTransfer{To: t.To, Asset: t.Asset, Amount: (*big.Int)(t.Amount)})
+	}
+	return transfers
 }
 
 func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
@@ -1363,6 +2236,9 @@ func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (c
 }
 
 func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error) {
+	if args.Multisig != nil {
+		return s.sendMultisigTransaction(ctx, args)
+	}
 
 	account := accounts.Account{Address: args.From}
 
@@ -1393,6 +2269,11 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if signed.Payer() != nil {
+		if signed, err = signFeePayer(s.b.AccountManager(), chainID, signed); err != nil {
+			return common.Hash{}, err
+		}
+	}
 	return submitTransaction(ctx, s.b, signed)
 }
 
@@ -1442,7 +2323,91 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return nil, err
 	}
-	signedTx, err := s.sign(args.From, tx)
+	var signedTx *types.Transaction
+	if args.Multisig != nil {
+		// the owners unlocked here sign, the others add their signatures later
+		signedTx, _, err = signMultisigUnlocked(s.b.AccountManager(), s.b.ChainConfig().ChainId, tx)
+	} else {
+		signedTx, err = s.sign(args.From, tx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signedTx}, nil
+}
+
+// sendMultisigTransaction signs a transaction of a multisig account with the
+// owners unlocked on this node and sends it if they reach its threshold.
+func (s *PublicTransactionPoolAPI) sendMultisigTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error) {
+	if args.Nonce == nil {
+		s.nonceLock.LockAddr(args.Multisig.Address())
+		defer s.nonceLock.UnlockAddr(args.Multisig.Address())
+	}
+	if err := args.setDefaults(ctx, s.b); err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := args.toTransaction()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	chainID := s.b.ChainConfig().ChainId
+	signed, count, err := signMultisigUnlocked(s.b.AccountManager(), chainID, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if uint64(count) < args.Multisig.Threshold {
+		return common.Hash{}, fmt.Errorf("%d of %d owners signed, collect the rest with aoa_signTransaction and aoa_signMultisig", count, args.Multisig.Threshold)
+	}
+	if signed.Payer() != nil {
+		if signed, err = signFeePayer(s.b.AccountManager(), chainID, signed); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return submitTransaction(ctx, s.b, signed)
+}
+
+// SignMultisig adds the signatures of the owners unlocked on this node to a
+// transaction of a multisig account, built by signTransaction.
+func (s *PublicTransactionPoolAPI) SignMultisig(ctx context.Context, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	signer := types.NewAuroraSigner(s.b.ChainConfig().ChainId)
+	signers, err := types.MultisigSigners(signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, count, err := signMultisigUnlocked(s.b.AccountManager(), s.b.ChainConfig().ChainId, tx)
+	if err != nil {
+		return nil, err
+	}
+	if count == len(signers) {
+		return nil, errors.New("no owner that has not signed yet is unlocked on this node")
+	}
+	data, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signedTx}, nil
+}
+
+// SignFeePayer co-signs a transaction signed by its sender as its fee payer.
+// The sender signs with signTransaction first, naming the payer in feePayer.
+func (s *PublicTransactionPoolAPI) SignFeePayer(ctx context.Context, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	signer := types.NewAuroraSigner(s.b.ChainConfig().ChainId)
+	if _, err := types.Sender(signer, tx); err != nil {
+		return nil, err
+	}
+	signedTx, err := signFeePayer(s.b.AccountManager(), s.b.ChainConfig().ChainId, tx)
 	if err != nil {
 		return nil, err
 	}
@@ -1508,6 +2473,11 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 			if err != nil {
 				return common.Hash{}, err
 			}
+			if signedTx.Payer() != nil {
+				if signedTx, err = signFeePayer(s.b.AccountManager(), s.b.ChainConfig().ChainId, signedTx); err != nil {
+					return common.Hash{}, err
+				}
+			}
 			if err = s.b.SendTx(ctx, signedTx); err != nil {
 				return common.Hash{}, err
 			}
@@ -1591,6 +2561,157 @@ func (api *PrivateDebugAPI) SetHead(number hexutil.Uint64) {
 	api.b.SetHead(uint64(number))
 }
 
+const defaultTraceTimeout = 5 * time.Second
+
+// TraceCallsConfig selects the tracer of TraceCalls, "callTracer" by default or
+// "structLogger", and configures the latter.
+type TraceCallsConfig struct {
+	*vm.LogConfig
+	Tracer  *string
+	Timeout *string
+}
+
+// CallTraceResult is the call tree of a transaction traced by callTracer.
+// LockDelta is the change of the locked balance of the sender over the whole
+// transaction, including the native actions running no call.
+type CallTraceResult struct {
+	Gas       uint64          `json:"gas"`
+	Failed    bool            `json:"failed"`
+	LockDelta *hexutil.Big    `json:"lockDelta"`
+	Calls     []*vm.CallFrame `json:"calls"`
+}
+
+// TraceCalls re-executes the transaction on the state its block was built on
+// and returns its trace. The call tracer returns the nested calls and creates
+// with the asset they carry, the asset transfers of contracts and batch
+// transfers, and the votes and registrations with their lock balance changes.
+func (api *PrivateDebugAPI) TraceCalls(ctx context.Context, hash common.Hash, config *TraceCallsConfig) (interface{}, error) {
+	tx, blockHash, _, index := core.GetTransaction(api.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %#x not found", hash)
+	}
+	block, err := api.b.GetBlock(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block %#x not found", blockHash)
+	}
+	statedb, _, err := api.b.StateAndHeaderByNumber(ctx, rpc.BlockNumber(block.NumberU64()-1))
+	if statedb == nil || err != nil {
+		return nil, fmt.Errorf("state of block #%d not found", block.NumberU64()-1)
+	}
+
+	var (
+		name    = "callTracer"
+		timeout = defaultTraceTimeout
+		logger  *vm.StructLogger
+		calls   *vm.CallTracer
+		tracer  vm.Tracer
+	)
+	if config != nil {
+		if config.Tracer != nil {
+			name = *config.Tracer
+		}
+		if config.Timeout != nil {
+			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+				return nil, err
+			}
+		}
+	}
+	switch name {
+	case "callTracer":
+		calls = vm.NewCallTracer()
+		tracer = calls
+	case "structLogger":
+		var cfg *vm.LogConfig
+		if config != nil {
+			cfg = config.LogConfig
+		}
+		logger = vm.NewStructLogger(cfg)
+		tracer = logger
+	default:
+		return nil, fmt.Errorf("unknown tracer %q", name)
+	}
+
+	// replay the transactions before it in the block
+	signer := types.MakeSigner(api.b.ChainConfig(), block.Number())
+	for i, prev := range block.Transactions()[:index] {
+		msg, err := prev.AsMessage(signer)
+		if err != nil {
+			return nil, err
+		}
+		statedb.Prepare(prev.Hash(), blockHash, i)
+		if _, _, _, err := api.applyMessage(ctx, msg, statedb, block.Header(), vm.Config{}); err != nil {
+			return nil, fmt.Errorf("transaction %#x failed: %v", prev.Hash(), err)
+		}
+	}
+
+	msg, err := tx.AsMessage(signer)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	lock := lockBalance(statedb, msg.From())
+	statedb.Prepare(hash, blockHash, int(index))
+	ret, gas, failed, err := api.applyMessage(ctx, msg, statedb, block.Header(), vm.Config{Debug: true, Tracer: tracer})
+	if err != nil {
+		return nil, err
+	}
+	if logger != nil {
+		return &ExecutionResult{
+			Gas:         gas,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", ret),
+			StructLogs:  FormatLogs(logger.StructLogs()),
+		}, nil
+	}
+	return &CallTraceResult{
+		Gas:       gas,
+		Failed:    failed,
+		LockDelta: (*hexutil.Big)(lock.Sub(lockBalance(statedb, msg.From()), lock)),
+		Calls:     calls.Frames(),
+	}, nil
+}
+
+// applyMessage applies msg to statedb the way the block processor does,
+// aborting the EVM once ctx is done.
+func (api *PrivateDebugAPI) applyMessage(ctx context.Context, msg core.Message, statedb *state.StateDB, header *types.Header, vmCfg vm.Config) ([]byte, uint64, bool, error) {
+	evm, vmError, err := api.b.GetEVM(ctx, msg, statedb, header, vmCfg)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	ret, gas, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(header.GasLimit))
+	if err := vmError(); err != nil {
+		return nil, 0, false, err
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if api.b.ChainConfig().IsAres(header.Number) {
+		statedb.Finalise(true)
+	} else {
+		statedb.IntermediateRoot(false)
+	}
+	return ret, gas, failed, nil
+}
+
+func lockBalance(statedb *state.StateDB, addr common.Address) *big.Int {
+	if lock := statedb.GetLockBalance(addr); lock != nil {
+		return new(big.Int).Set(lock)
+	}
+	return new(big.Int)
+}
+
 type PublicNetAPI struct {
 	net            *p2p.Server
 	networkVersion uint64
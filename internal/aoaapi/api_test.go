@@ -0,0 +1,82 @@
+package aoaapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora"
+	"github.com/Aurorachain/go-Aurora/aoaclient"
+	"github.com/Aurorachain/go-Aurora/aoadb"
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/state"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rpc"
+)
+
+// stateBackend serves the state and the delegates of every block from one
+// StateDB and one delegate list. The methods of Backend it does not override
+// are not used by the tests.
+type stateBackend struct {
+	Backend
+	statedb   *state.StateDB
+	delegates map[common.Address]types.Candidate
+}
+
+func (b *stateBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Block, error) {
+	return types.NewBlockWithHeader(&types.Header{Number: new(big.Int)}), nil
+}
+
+func (b *stateBackend) GetDelegatePoll(block *types.Block) (*map[common.Address]types.Candidate, error) {
+	return &b.delegates, nil
+}
+
+func (b *stateBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	return b.statedb, &types.Header{Number: new(big.Int)}, nil
+}
+
+// newTestClient serves the chain API over an in-process RPC server, so the
+// results go through the same encoding as on a node.
+func newTestClient(t *testing.T, backend *stateBackend) *aoaclient.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("aoa", NewPublicBlockChainAPI(backend)); err != nil {
+		t.Fatal(err)
+	}
+	return aoaclient.NewClient(rpc.DialInProc(server))
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	mem, _ := aoadb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(mem))
+	account, asset, delegate := common.Address{1}, common.Address{2}, common.Address{3}
+	// a balance beyond float64 precision, the node returns it as a JSON number
+	held := new(big.Int).Exp(big.NewInt(10), big.NewInt(30), nil)
+	held.Add(held, big.NewInt(1))
+	statedb.AddAssetBalance(account, asset, held)
+	statedb.AddLockBalance(account, new(big.Int).Mul(big.NewInt(5), big.NewInt(params.Aoa)))
+	// one voter staking 5 AOA and 2 votes cast before stake weighting
+	statedb.SetVoteStake(account, delegate, 5)
+	delegates := map[common.Address]types.Candidate{
+		delegate: {Address: delegate.Hex(), Vote: 7, Nickname: "node-1"},
+	}
+
+	client := newTestClient(t, &stateBackend{statedb: statedb, delegates: delegates})
+	ctx := context.Background()
+	balance, err := client.AssetBalanceAt(ctx, account, asset, nil)
+	if err != nil || balance.Cmp(held) != 0 {
+		t.Errorf("AssetBalanceAt mismatch: have %v (%v), want %v", balance, err, held)
+	}
+	votes, err := client.VotesNumberAt(ctx, account, nil)
+	if err != nil || votes.Cmp(big.NewInt(5)) != 0 {
+		t.Errorf("VotesNumberAt mismatch: have %v (%v), want 5", votes, err)
+	}
+	candidate, err := client.CandidateVotesAt(ctx, delegate, nil)
+	if err != nil || *candidate != (aurora.VotesNumber{Weight: 7, Voters: 3}) {
+		t.Errorf("CandidateVotesAt mismatch: have %+v (%v), want weight 7, 3 voters", candidate, err)
+	}
+	candidate, err = client.CandidateVotesAt(ctx, account, nil)
+	if err != nil || *candidate != (aurora.VotesNumber{}) {
+		t.Errorf("CandidateVotesAt of a non-candidate mismatch: have %+v (%v), want zero", candidate, err)
+	}
+}
@@ -54,6 +54,8 @@ type Backend interface {
 
 	IsWatchInnerTxEnable() bool
 	GetInnerTxDb() watch.InnerTxDb
+
+	AssetHolderIndexer() *core.ChainIndexer
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -328,6 +328,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, null]
 		}),
+		new web3._extend.Method({
+			name: 'traceCalls',
+			call: 'debug_traceCalls',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -401,6 +407,12 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
 			outputFormatter: web3._extend.utils.toDecimal
 		}),
+		new web3._extend.Method({
+			name: 'getCandidateVotes',
+			call: 'aoa_getCandidateVotes',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'getDelegate',
 			call: 'aoa_getDelegate',
@@ -419,6 +431,43 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getUnbonding',
+			call: 'aoa_getUnbonding',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getPendingRewards',
+			call: 'aoa_getPendingRewards',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
+		}),
+		new web3._extend.Method({
+			name: 'getVoteStakes',
+			call: 'aoa_getVoteStakes',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProxy',
+			call: 'aoa_getProxy',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getMultisig',
+			call: 'aoa_getMultisig',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProxiedStake',
+			call: 'aoa_getProxiedStake',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'resend',
 			call: 'aoa_resend',
@@ -431,6 +480,16 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'signFeePayer',
+			call: 'aoa_signFeePayer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signMultisig',
+			call: 'aoa_signMultisig',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'aoa_submitTransaction',
@@ -456,6 +515,46 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
 		}),
+		new web3._extend.Method({
+			name: 'getAssetBySymbol',
+			call: 'aoa_getAssetBySymbol',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'listAssets',
+			call: 'aoa_listAssets',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'getDelegateStats',
+			call: 'aoa_getDelegateStats',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getRoundStats',
+			call: 'aoa_getRoundStats',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getAssetHolders',
+			call: 'aoa_getAssetHolders',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null],
+		}),
+		new web3._extend.Method({
+			name: 'getAssetHolderCount',
+			call: 'aoa_getAssetHolderCount',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getAssetSupply',
+			call: 'aoa_getAssetSupply',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'getAbi',
 			call: 'aoa_getAbi',
@@ -469,6 +568,26 @@ web3._extend({
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
 			outputFormatter: web3._extend.formatters.outputBigNumberFormatter
 		}),
+		new web3._extend.Method({
+			name: 'mintAsset',
+			call: 'aoa_sendTransaction',
+			params: 1,
+			inputFormatter: [function(tx) {
+				tx.action = 7;
+				tx.asset = web3._extend.formatters.inputAddressFormatter(tx.asset);
+				return web3._extend.formatters.inputTransactionFormatter(tx);
+			}]
+		}),
+		new web3._extend.Method({
+			name: 'burnAsset',
+			call: 'aoa_sendTransaction',
+			params: 1,
+			inputFormatter: [function(tx) {
+				tx.action = 8;
+				tx.asset = web3._extend.formatters.inputAddressFormatter(tx.asset);
+				return web3._extend.formatters.inputTransactionFormatter(tx);
+			}]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -541,6 +660,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'signMultisig',
+			call: 'personal_signMultisig',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
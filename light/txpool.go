@@ -3,6 +3,7 @@ package light
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -40,6 +41,7 @@ type TxPool struct {
 	head         common.Hash
 	nonce        map[common.Address]uint64
 	pending      map[common.Hash]*types.Transaction
+	payerCosts   map[common.Address]*big.Int // what every fee payer buys for its transactions in pending
 	mined        map[common.Hash][]*types.Transaction
 	clearIdx     uint64
 
@@ -58,6 +60,7 @@ func NewTxPool(config *params.ChainConfig, chain *LightChain, relay TxRelayBacke
 		signer:      types.NewAuroraSigner(config.ChainId),
 		nonce:       make(map[common.Address]uint64),
 		pending:     make(map[common.Hash]*types.Transaction),
+		payerCosts:  make(map[common.Address]*big.Int),
 		mined:       make(map[common.Hash][]*types.Transaction),
 		quit:        make(chan bool),
 		chainHeadCh: make(chan core.ChainHeadEvent, chainHeadChanSize),
@@ -144,7 +147,7 @@ func (pool *TxPool) checkMinedTxs(ctx context.Context, hash common.Hash, number
 		}
 
 		for _, tx := range list {
-			delete(pool.pending, tx.Hash())
+			pool.removePending(tx.Hash())
 			txc.setState(tx.Hash(), true)
 		}
 		pool.mined[hash] = list
@@ -157,7 +160,7 @@ func (pool *TxPool) rollbackTxs(hash common.Hash, txc txStateChanges) {
 		for _, tx := range list {
 			txHash := tx.Hash()
 			core.DeleteTxLookupEntry(pool.chainDb, txHash)
-			pool.pending[txHash] = tx
+			pool.addPending(tx)
 			txc.setState(txHash, false)
 		}
 		delete(pool.mined, hash)
@@ -300,6 +303,20 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 	if b := currentState.GetBalance(from); b.Cmp(tx.AoaCost()) < 0 {
 		return core.ErrInsufficientFunds
 	}
+	if tx.Payer() != nil {
+		payer, err := types.PayerSender(pool.signer, tx)
+		if err != nil {
+			return core.ErrInvalidPayer
+		}
+		gasCost := core.PayerCharge(pool.signer, tx)
+		// the payer also buys its other transactions in the pool
+		if cost := pool.payerCosts[payer]; cost != nil {
+			gasCost.Add(gasCost, cost)
+		}
+		if currentState.GetBalance(payer).Cmp(gasCost) < 0 {
+			return core.ErrInsufficientPayerFunds
+		}
+	}
 
 	gas, err := core.IntrinsicGas(tx.Data(),tx.TxDataAction())
 	if err != nil {
@@ -311,6 +328,33 @@ func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error
 	return currentState.Error()
 }
 
+// addPending adds tx to pending and to the cost of its fee payer.
+func (pool *TxPool) addPending(tx *types.Transaction) {
+	pool.pending[tx.Hash()] = tx
+	if payer := tx.Payer(); payer != nil {
+		if pool.payerCosts[*payer] == nil {
+			pool.payerCosts[*payer] = new(big.Int)
+		}
+		pool.payerCosts[*payer].Add(pool.payerCosts[*payer], core.PayerCharge(pool.signer, tx))
+	}
+}
+
+// removePending removes the transaction with hash from pending and from the
+// cost of its fee payer.
+func (pool *TxPool) removePending(hash common.Hash) {
+	tx, ok := pool.pending[hash]
+	if !ok {
+		return
+	}
+	delete(pool.pending, hash)
+	if payer := tx.Payer(); payer != nil && pool.payerCosts[*payer] != nil {
+		cost := pool.payerCosts[*payer]
+		if cost.Sub(cost, core.PayerCharge(pool.signer, tx)).Sign() <= 0 {
+			delete(pool.payerCosts, *payer)
+		}
+	}
+}
+
 func (self *TxPool) add(ctx context.Context, tx *types.Transaction) error {
 	hash := tx.Hash()
 
@@ -323,7 +367,7 @@ func (self *TxPool) add(ctx context.Context, tx *types.Transaction) error {
 	}
 
 	if _, ok := self.pending[hash]; !ok {
-		self.pending[hash] = tx
+		self.addPending(tx)
 
 		nonce := tx.Nonce() + 1
 
@@ -415,7 +459,7 @@ func (self *TxPool) RemoveTransactions(txs types.Transactions) {
 	for _, tx := range txs {
 
 		hash := tx.Hash()
-		delete(self.pending, hash)
+		self.removePending(hash)
 		self.chainDb.Delete(hash[:])
 		hashes = append(hashes, hash)
 	}
@@ -426,7 +470,7 @@ func (pool *TxPool) RemoveTx(hash common.Hash) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	delete(pool.pending, hash)
+	pool.removePending(hash)
 	pool.chainDb.Delete(hash[:])
 	pool.relay.Discard([]common.Hash{hash})
 }
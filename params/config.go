@@ -45,6 +45,21 @@ var (
 		BlockInterval:        big.NewInt(10),
 		AresBlock:            big.NewInt(90),
 		EpiphronBlock:        big.NewInt(3750),
+		HermesBlock:          big.NewInt(3750),
+		ApolloBlock:          big.NewInt(3750),
+		AthenaBlock:          big.NewInt(3750),
+		ArtemisBlock:         big.NewInt(3750),
+		PoseidonBlock:        big.NewInt(3750),
+		HephaestusBlock:      big.NewInt(3750),
+		HeraBlock:            big.NewInt(3750),
+		DemeterBlock:         big.NewInt(3750),
+		DionysusBlock:        big.NewInt(3750),
+		HestiaBlock:          big.NewInt(3750),
+		UnbondingBlocks:      big.NewInt(8640),
+		JailMissedSlots:      big.NewInt(50),
+		JailRounds:           big.NewInt(100),
+		SlashShare:           big.NewInt(1000),
+		ReporterShare:        big.NewInt(1000),
 	}
 
 	TestChainConfig = &ChainConfig{
@@ -66,14 +81,46 @@ type ChainConfig struct {
 
 	AresBlock     *big.Int `json:"aresBlock,omitempty"`     
 	EpiphronBlock *big.Int `json:"epiphronBlock,omitempty"` 
+	HermesBlock     *big.Int `json:"hermesBlock,omitempty"`
+	ApolloBlock     *big.Int `json:"apolloBlock,omitempty"`
+	AthenaBlock     *big.Int `json:"athenaBlock,omitempty"`
+	ArtemisBlock    *big.Int `json:"artemisBlock,omitempty"`
+	PoseidonBlock   *big.Int `json:"poseidonBlock,omitempty"`
+	HephaestusBlock *big.Int `json:"hephaestusBlock,omitempty"`
+	HeraBlock       *big.Int `json:"heraBlock,omitempty"`
+	DemeterBlock    *big.Int `json:"demeterBlock,omitempty"`
+	DionysusBlock   *big.Int `json:"dionysusBlock,omitempty"`
+	HestiaBlock     *big.Int `json:"hestiaBlock,omitempty"`
+
+	// HermesAssets lists the assets published before the Hermes fork in the
+	// order the fork registers their symbols. It has to list every one of them,
+	// an asset left out keeps its symbol free to publish again; the hermesassets
+	// command prints the table from the state before the fork block.
+	HermesAssets []common.Address `json:"hermesAssets,omitempty"`
+
+	UnbondingBlocks *big.Int `json:"unbondingBlocks,omitempty"`
+	JailMissedSlots *big.Int `json:"jailMissedSlots,omitempty"`
+	JailRounds      *big.Int `json:"jailRounds,omitempty"`
+	SlashShare      *big.Int `json:"slashShare,omitempty"`    // basis points of the stake of a double signer
+	ReporterShare   *big.Int `json:"reporterShare,omitempty"` // basis points of the slashed stake paid to the reporter
 }
 
 func (c *ChainConfig) String() string {
-	return fmt.Sprintf("{ChainID: %v Byzantium: %v AresBlock: %v EpiphronBlock: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Byzantium: %v AresBlock: %v EpiphronBlock: %v HermesBlock: %v ApolloBlock: %v AthenaBlock: %v ArtemisBlock: %v PoseidonBlock: %v HephaestusBlock: %v HeraBlock: %v DemeterBlock: %v DionysusBlock: %v HestiaBlock: %v Engine: %v}",
 		c.ChainId,
 		c.ByzantiumBlock,
 		c.AresBlock,
 		c.EpiphronBlock,
+		c.HermesBlock,
+		c.ApolloBlock,
+		c.AthenaBlock,
+		c.ArtemisBlock,
+		c.PoseidonBlock,
+		c.HephaestusBlock,
+		c.HeraBlock,
+		c.DemeterBlock,
+		c.DionysusBlock,
+		c.HestiaBlock,
 		"DPOS-BFT",
 	)
 }
@@ -101,6 +148,54 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EpiphronBlock, newcfg.EpiphronBlock, head) {
 		return newCompatError("Epiphron fork block", c.EpiphronBlock, newcfg.EpiphronBlock)
 	}
+	if isForkIncompatible(c.HermesBlock, newcfg.HermesBlock, head) {
+		return newCompatError("Hermes fork block", c.HermesBlock, newcfg.HermesBlock)
+	}
+	if isForkIncompatible(c.ApolloBlock, newcfg.ApolloBlock, head) {
+		return newCompatError("Apollo fork block", c.ApolloBlock, newcfg.ApolloBlock)
+	}
+	if isForkIncompatible(c.AthenaBlock, newcfg.AthenaBlock, head) {
+		return newCompatError("Athena fork block", c.AthenaBlock, newcfg.AthenaBlock)
+	}
+	if isForkIncompatible(c.ArtemisBlock, newcfg.ArtemisBlock, head) {
+		return newCompatError("Artemis fork block", c.ArtemisBlock, newcfg.ArtemisBlock)
+	}
+	if isForkIncompatible(c.PoseidonBlock, newcfg.PoseidonBlock, head) {
+		return newCompatError("Poseidon fork block", c.PoseidonBlock, newcfg.PoseidonBlock)
+	}
+	if isForkIncompatible(c.HephaestusBlock, newcfg.HephaestusBlock, head) {
+		return newCompatError("Hephaestus fork block", c.HephaestusBlock, newcfg.HephaestusBlock)
+	}
+	if isForkIncompatible(c.HeraBlock, newcfg.HeraBlock, head) {
+		return newCompatError("Hera fork block", c.HeraBlock, newcfg.HeraBlock)
+	}
+	if isForkIncompatible(c.DemeterBlock, newcfg.DemeterBlock, head) {
+		return newCompatError("Demeter fork block", c.DemeterBlock, newcfg.DemeterBlock)
+	}
+	if isForkIncompatible(c.DionysusBlock, newcfg.DionysusBlock, head) {
+		return newCompatError("Dionysus fork block", c.DionysusBlock, newcfg.DionysusBlock)
+	}
+	if isForkIncompatible(c.HestiaBlock, newcfg.HestiaBlock, head) {
+		return newCompatError("Hestia fork block", c.HestiaBlock, newcfg.HestiaBlock)
+	}
+	if (isForked(c.HermesBlock, head) || isForked(newcfg.HermesBlock, head)) && !addressesEqual(c.HermesAssets, newcfg.HermesAssets) {
+		return newParamCompatError("Hermes assets", big.NewInt(int64(len(c.HermesAssets))), big.NewInt(int64(len(newcfg.HermesAssets))), c.HermesBlock, newcfg.HermesBlock)
+	}
+	if isParamIncompatible(c.AthenaBlock, newcfg.AthenaBlock, c.UnbondingBlocks, newcfg.UnbondingBlocks, head) {
+		return newParamCompatError("unbonding blocks", c.UnbondingBlocks, newcfg.UnbondingBlocks, c.AthenaBlock, newcfg.AthenaBlock)
+	}
+	if isParamIncompatible(c.ArtemisBlock, newcfg.ArtemisBlock, c.JailMissedSlots, newcfg.JailMissedSlots, head) {
+		return newParamCompatError("jail missed slots", c.JailMissedSlots, newcfg.JailMissedSlots, c.ArtemisBlock, newcfg.ArtemisBlock)
+	}
+	if isParamIncompatible(c.ArtemisBlock, newcfg.ArtemisBlock, c.JailRounds, newcfg.JailRounds, head) {
+		return newParamCompatError("jail rounds", c.JailRounds, newcfg.JailRounds, c.ArtemisBlock, newcfg.ArtemisBlock)
+	}
+	if isParamIncompatible(c.ArtemisBlock, newcfg.ArtemisBlock, c.SlashShare, newcfg.SlashShare, head) {
+		return newParamCompatError("slash share", c.SlashShare, newcfg.SlashShare, c.ArtemisBlock, newcfg.ArtemisBlock)
+	}
+	if isParamIncompatible(c.ArtemisBlock, newcfg.ArtemisBlock, c.ReporterShare, newcfg.ReporterShare, head) {
+		return newParamCompatError("reporter share", c.ReporterShare, newcfg.ReporterShare, c.ArtemisBlock, newcfg.ArtemisBlock)
+	}
 
 	return nil
 }
@@ -109,6 +204,12 @@ func isForkIncompatible(s1, s2, head *big.Int) bool {
 	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
 }
 
+// isParamIncompatible reports whether a parameter of a fork differs between
+// the configs once the fork is active at head in either of them.
+func isParamIncompatible(fork1, fork2, s1, s2, head *big.Int) bool {
+	return (isForked(fork1, head) || isForked(fork2, head)) && !configNumEqual(s1, s2)
+}
+
 func isForked(s, head *big.Int) bool {
 	if s == nil || head == nil {
 		return false
@@ -126,6 +227,18 @@ func configNumEqual(x, y *big.Int) bool {
 	return x.Cmp(y) == 0
 }
 
+func addressesEqual(x, y []common.Address) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
 type ConfigCompatError struct {
 	What string
 
@@ -151,6 +264,14 @@ func newCompatError(what string, storedblock, newblock *big.Int) *ConfigCompatEr
 	return err
 }
 
+// newParamCompatError reports a changed fork parameter, rewinding the chain to
+// before the fork that applies it.
+func newParamCompatError(what string, stored, new, storedfork, newfork *big.Int) *ConfigCompatError {
+	err := newCompatError(what, storedfork, newfork)
+	err.StoredConfig, err.NewConfig = stored, new
+	return err
+}
+
 func (err *ConfigCompatError) Error() string {
 	return fmt.Sprintf("mismatching %s in database (have %d, want %d, rewindto %d)", err.What, err.StoredConfig, err.NewConfig, err.RewindTo)
 }
@@ -167,6 +288,72 @@ func (c *ChainConfig) IsEpiphron(num *big.Int) bool {
 	return isForked(c.EpiphronBlock, num)
 }
 
+func (c *ChainConfig) IsHermes(num *big.Int) bool {
+	return isForked(c.HermesBlock, num)
+}
+
+func (c *ChainConfig) IsApollo(num *big.Int) bool {
+	return isForked(c.ApolloBlock, num)
+}
+
+func (c *ChainConfig) IsAthena(num *big.Int) bool {
+	return isForked(c.AthenaBlock, num)
+}
+
+func (c *ChainConfig) IsArtemis(num *big.Int) bool {
+	return isForked(c.ArtemisBlock, num)
+}
+
+func (c *ChainConfig) IsPoseidon(num *big.Int) bool {
+	return isForked(c.PoseidonBlock, num)
+}
+
+func (c *ChainConfig) IsHephaestus(num *big.Int) bool {
+	return isForked(c.HephaestusBlock, num)
+}
+
+func (c *ChainConfig) IsHera(num *big.Int) bool {
+	return isForked(c.HeraBlock, num)
+}
+
+func (c *ChainConfig) IsDemeter(num *big.Int) bool {
+	return isForked(c.DemeterBlock, num)
+}
+
+func (c *ChainConfig) IsDionysus(num *big.Int) bool {
+	return isForked(c.DionysusBlock, num)
+}
+
+func (c *ChainConfig) IsHestia(num *big.Int) bool {
+	return isForked(c.HestiaBlock, num)
+}
+
+// IsTxExt reports whether transactions may carry an extension, which they can
+// from the earliest of the forks enabling one of its parts.
+func (c *ChainConfig) IsTxExt(num *big.Int) bool {
+	return c.IsDemeter(num) || c.IsDionysus(num) || c.IsHestia(num)
+}
+
+// UnbondingPeriod returns the number of blocks withdrawn vote stake stays
+// unspendable. Stake is released immediately before the Athena fork.
+func (c *ChainConfig) UnbondingPeriod(num *big.Int) uint64 {
+	if !c.IsAthena(num) || c.UnbondingBlocks == nil {
+		return 0
+	}
+	return c.UnbondingBlocks.Uint64()
+}
+
+// BlockReward returns the reward minted for the producer of block num.
+func (c *ChainConfig) BlockReward(num *big.Int) *big.Int {
+	if c.IsByzantium(num) && c.ByzantiumBlockReward != nil {
+		return c.ByzantiumBlockReward
+	}
+	if c.FrontierBlockReward == nil {
+		return new(big.Int)
+	}
+	return c.FrontierBlockReward
+}
+
 func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	if num == nil {
 		return GasTable{}
@@ -182,6 +369,7 @@ func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 type Rules struct {
 	ChainId     *big.Int
 	IsByzantium bool
+	IsApollo    bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -189,5 +377,5 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainId == nil {
 		chainId = new(big.Int)
 	}
-	return Rules{ChainId: new(big.Int).Set(chainId), IsByzantium: c.IsByzantium(num)}
+	return Rules{ChainId: new(big.Int).Set(chainId), IsByzantium: c.IsByzantium(num), IsApollo: c.IsApollo(num)}
 }
@@ -4,6 +4,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -55,6 +57,45 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     0,
 			},
 		},
+		{
+			stored:  &ChainConfig{AthenaBlock: big.NewInt(100), UnbondingBlocks: big.NewInt(8640)},
+			new:     &ChainConfig{AthenaBlock: big.NewInt(100), UnbondingBlocks: big.NewInt(100)},
+			head:    99,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{AthenaBlock: big.NewInt(100), UnbondingBlocks: big.NewInt(8640)},
+			new:    &ChainConfig{AthenaBlock: big.NewInt(100), UnbondingBlocks: big.NewInt(100)},
+			head:   150,
+			wantErr: &ConfigCompatError{
+				What:         "unbonding blocks",
+				StoredConfig: big.NewInt(8640),
+				NewConfig:    big.NewInt(100),
+				RewindTo:     99,
+			},
+		},
+		{
+			stored: &ChainConfig{ArtemisBlock: big.NewInt(200), SlashShare: big.NewInt(1000)},
+			new:    &ChainConfig{ArtemisBlock: big.NewInt(200)},
+			head:   300,
+			wantErr: &ConfigCompatError{
+				What:         "slash share",
+				StoredConfig: big.NewInt(1000),
+				NewConfig:    nil,
+				RewindTo:     199,
+			},
+		},
+		{
+			stored: &ChainConfig{HermesBlock: big.NewInt(50), HermesAssets: []common.Address{{1}, {2}}},
+			new:    &ChainConfig{HermesBlock: big.NewInt(50), HermesAssets: []common.Address{{2}, {1}}},
+			head:   60,
+			wantErr: &ConfigCompatError{
+				What:         "Hermes assets",
+				StoredConfig: big.NewInt(2),
+				NewConfig:    big.NewInt(2),
+				RewindTo:     49,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -23,6 +23,18 @@ const (
 	LogDataGas             uint64 = 1                           
 	CallStipend            uint64 = 1000                        
 	TxGasAssetPublish      uint64 = 100000                      
+	TxGasAssetMint         uint64 = 50000
+	TxGasAssetBurn         uint64 = 50000
+	TxGasBatchTransferLeg  uint64 = 20000
+	UnregisterVoterGas     uint64 = 6000
+	MaxUnregisterVoters    uint64 = 256
+	ClaimRewardGas         uint64 = 2000
+	TxGasEvidence          uint64 = 50000
+	SlashUnbondingGas      uint64 = 2000
+	ProxyShareGas          uint64 = 3000
+	MultisigSigGas         uint64 = 3000
+	SeedRevealLength              = 16
+	SeedChainLength        uint64 = 4096
 	MaxContractGasLimit    uint64 = 60000000                     
 	MaxOneContractGasLimit uint64 = 1000000                      
 
@@ -65,4 +77,6 @@ const (
 	Bn256ScalarMulGas       uint64 = 2500 
 	Bn256PairingBaseGas     uint64 = 6250 
 	Bn256PairingPerPointGas uint64 = 5000 
+	AssetInfoBaseGas        uint64 = 200
+	AssetInfoPerWordGas     uint64 = 3
 )
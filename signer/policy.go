@@ -0,0 +1,127 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+)
+
+var ErrSignedHeight = errors.New("height already signed")
+
+// Watermark is the highest block a key signed.
+type Watermark struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Policy refuses to sign a block lower than the highest one signed with the same
+// key, or a different block at the same height. Watermarks are kept per signing
+// key, not per coinbase, since a producer key may sign for another delegate and
+// the coinbase is chosen by whoever asks for the signature. Signing the same block
+// again is allowed so that a producer can retry. The watermarks are written to
+// a file before the signature is released, if a path is given.
+type Policy struct {
+	mu    sync.Mutex
+	path  string
+	marks map[common.Address]Watermark
+}
+
+// NewPolicy loads the watermarks stored at path. An empty path keeps them in
+// memory only.
+func NewPolicy(path string) (*Policy, error) {
+	p := &Policy{path: path, marks: make(map[common.Address]Watermark)}
+	if path == "" {
+		return p, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &p.marks); err != nil {
+		return nil, fmt.Errorf("invalid watermark file %s: %v", path, err)
+	}
+	return p, nil
+}
+
+func (p *Policy) Watermark(key common.Address) (Watermark, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	mark, ok := p.marks[key]
+	return mark, ok
+}
+
+// Allow checks header against the watermark of the signing key and raises the
+// watermark to header.
+func (p *Policy) Allow(key common.Address, header *types.Header) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	number, hash := header.Number.Uint64(), header.Hash()
+	prev, existed := p.marks[key]
+	if existed {
+		if number < prev.Number || (number == prev.Number && hash != prev.Hash) {
+			return fmt.Errorf("%v: key %x signed block %d %x", ErrSignedHeight, key, prev.Number, prev.Hash)
+		}
+		if number == prev.Number {
+			return nil
+		}
+	}
+	p.marks[key] = Watermark{Number: number, Hash: hash}
+	if err := p.save(); err != nil {
+		if existed {
+			p.marks[key] = prev
+		} else {
+			delete(p.marks, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (p *Policy) save() error {
+	if p.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(p.marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write a sibling file and rename it so a crash never leaves a torn file,
+	// both are synced before the signature is released
+	dir := filepath.Dir(p.path)
+	tmp := filepath.Join(dir, "."+filepath.Base(p.path)+".tmp")
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p.path); err != nil {
+		return err
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,318 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/prometheus/prometheus/util/flock"
+)
+
+// InterchangeVersion is the version of the slashing protection interchange
+// format written by Export.
+const InterchangeVersion = "1"
+
+var (
+	ErrConflictingBlock = errors.New("conflicts with a signed block")
+	errProtectionClosed = errors.New("slashing protection database is closed")
+)
+
+// SignedBlock is a block a delegate signed. WorkTime is the slot the block was
+// produced for, the header time.
+type SignedBlock struct {
+	Delegate common.Address `json:"delegate"`
+	Number   uint64         `json:"number,string"`
+	Hash     common.Hash    `json:"hash"`
+	WorkTime uint64         `json:"work_time,string"`
+}
+
+func NewSignedBlock(header *types.Header) SignedBlock {
+	return SignedBlock{
+		Delegate: header.Coinbase,
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		WorkTime: header.Time.Uint64(),
+	}
+}
+
+type signedHistory struct {
+	blocks   map[SignedBlock]struct{}
+	byNumber map[uint64]common.Hash
+	byTime   map[uint64]common.Hash
+	lastTime uint64
+}
+
+// ProtectionDB is an append-only record of the blocks signed by delegates. It
+// refuses a block at a height or slot already signed with a different hash, or
+// for a slot older than the last one signed. Each record is a JSON line synced
+// to disk before it is acknowledged. Nothing is signed once it is closed.
+type ProtectionDB struct {
+	mu      sync.Mutex
+	file    *os.File
+	lock    flock.Releaser
+	closed  bool
+	history map[common.Address]*signedHistory
+}
+
+// OpenProtectionDB loads the record at path and opens it for appending. The
+// record is locked until it is closed, a second node or an import can not open
+// it meanwhile. An empty path keeps the record in memory only.
+func OpenProtectionDB(path string) (*ProtectionDB, error) {
+	db := &ProtectionDB{history: make(map[common.Address]*signedHistory)}
+	if path == "" {
+		return db, nil
+	}
+	lock, _, err := flock.New(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("slashing protection database %s is in use: %v", path, err)
+	}
+	if err := db.load(path); err != nil {
+		lock.Release()
+		return nil, err
+	}
+	db.lock = lock
+	return db, nil
+}
+
+func (db *ProtectionDB) load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// a crash can only tear the record being appended, cut it off
+	if end := bytes.LastIndexByte(data, '\n') + 1; end < len(data) {
+		log.Warn("Dropping torn slashing protection record", "path", path, "record", string(data[end:]))
+		if err := os.Truncate(path, int64(end)); err != nil {
+			return err
+		}
+		data = data[:end]
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var block SignedBlock
+		if err := json.Unmarshal(line, &block); err != nil {
+			return fmt.Errorf("invalid slashing protection record %s:%d: %v", path, i+1, err)
+		}
+		db.add(block)
+	}
+	db.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	return err
+}
+
+func (db *ProtectionDB) add(block SignedBlock) {
+	h, ok := db.history[block.Delegate]
+	if !ok {
+		h = &signedHistory{
+			blocks:   make(map[SignedBlock]struct{}),
+			byNumber: make(map[uint64]common.Hash),
+			byTime:   make(map[uint64]common.Hash),
+		}
+		db.history[block.Delegate] = h
+	}
+	h.blocks[block] = struct{}{}
+	if _, ok := h.byNumber[block.Number]; !ok {
+		h.byNumber[block.Number] = block.Hash
+	}
+	if _, ok := h.byTime[block.WorkTime]; !ok {
+		h.byTime[block.WorkTime] = block.Hash
+	}
+	if block.WorkTime > h.lastTime {
+		h.lastTime = block.WorkTime
+	}
+}
+
+// known reports whether block is recorded already.
+func (db *ProtectionDB) known(block SignedBlock) bool {
+	h, ok := db.history[block.Delegate]
+	if !ok {
+		return false
+	}
+	_, ok = h.blocks[block]
+	return ok
+}
+
+func (db *ProtectionDB) check(block SignedBlock) error {
+	if db.known(block) {
+		return nil
+	}
+	h, ok := db.history[block.Delegate]
+	if !ok {
+		return nil
+	}
+	if hash, ok := h.byNumber[block.Number]; ok && hash != block.Hash {
+		return fmt.Errorf("%v: delegate %x signed block %d %x", ErrConflictingBlock, block.Delegate, block.Number, hash)
+	}
+	if hash, ok := h.byTime[block.WorkTime]; ok && hash != block.Hash {
+		return fmt.Errorf("%v: delegate %x signed slot %d %x", ErrConflictingBlock, block.Delegate, block.WorkTime, hash)
+	}
+	if block.WorkTime < h.lastTime {
+		return fmt.Errorf("%v: delegate %x signed the later slot %d", ErrConflictingBlock, block.Delegate, h.lastTime)
+	}
+	return nil
+}
+
+func (db *ProtectionDB) append(block SignedBlock) error {
+	if db.closed {
+		return errProtectionClosed
+	}
+	if db.file != nil {
+		line, err := json.Marshal(block)
+		if err != nil {
+			return err
+		}
+		if _, err := db.file.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		if err := db.file.Sync(); err != nil {
+			return err
+		}
+	}
+	db.add(block)
+	return nil
+}
+
+// Check returns an error if signing block would conflict with the record.
+func (db *ProtectionDB) Check(block SignedBlock) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.check(block)
+}
+
+// Record checks block and appends it to the record. Recording a block twice is
+// allowed so that a failed signing can be retried.
+func (db *ProtectionDB) Record(block SignedBlock) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return errProtectionClosed
+	}
+	if err := db.check(block); err != nil {
+		return err
+	}
+	if db.known(block) {
+		return nil
+	}
+	return db.append(block)
+}
+
+// Blocks returns the recorded blocks ordered by delegate and height.
+func (db *ProtectionDB) Blocks() []SignedBlock {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var blocks []SignedBlock
+	for _, h := range db.history {
+		for block := range h.blocks {
+			blocks = append(blocks, block)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if c := bytes.Compare(blocks[i].Delegate[:], blocks[j].Delegate[:]); c != 0 {
+			return c < 0
+		}
+		if blocks[i].Number != blocks[j].Number {
+			return blocks[i].Number < blocks[j].Number
+		}
+		return blocks[i].WorkTime < blocks[j].WorkTime
+	})
+	return blocks
+}
+
+// Close closes the record and releases its lock. Recording a block afterwards
+// fails.
+func (db *ProtectionDB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return nil
+	}
+	db.closed = true
+	var err error
+	if db.file != nil {
+		err = db.file.Close()
+		db.file = nil
+	}
+	if db.lock != nil {
+		if lerr := db.lock.Release(); err == nil {
+			err = lerr
+		}
+		db.lock = nil
+	}
+	return err
+}
+
+// Interchange is the JSON document used to move a slashing protection record
+// between machines.
+type Interchange struct {
+	Metadata struct {
+		Version string `json:"interchange_format_version"`
+	} `json:"metadata"`
+	Data []InterchangeDelegate `json:"data"`
+}
+
+type InterchangeDelegate struct {
+	Delegate     common.Address     `json:"delegate"`
+	SignedBlocks []InterchangeBlock `json:"signed_blocks"`
+}
+
+type InterchangeBlock struct {
+	Number   uint64      `json:"number,string"`
+	Hash     common.Hash `json:"hash"`
+	WorkTime uint64      `json:"work_time,string"`
+}
+
+// Export writes the record as an interchange document.
+func (db *ProtectionDB) Export(w io.Writer) error {
+	var doc Interchange
+	doc.Metadata.Version = InterchangeVersion
+	doc.Data = []InterchangeDelegate{}
+	for _, block := range db.Blocks() {
+		if n := len(doc.Data); n == 0 || doc.Data[n-1].Delegate != block.Delegate {
+			doc.Data = append(doc.Data, InterchangeDelegate{Delegate: block.Delegate})
+		}
+		last := &doc.Data[len(doc.Data)-1]
+		last.SignedBlocks = append(last.SignedBlocks, InterchangeBlock{Number: block.Number, Hash: block.Hash, WorkTime: block.WorkTime})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&doc)
+}
+
+// Import merges an interchange document into the record and returns the number
+// of blocks added. Imported blocks are added even if they conflict with the
+// record, they were signed and have to be protected against either way.
+func (db *ProtectionDB) Import(r io.Reader) (int, error) {
+	var doc Interchange
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, err
+	}
+	if doc.Metadata.Version != InterchangeVersion {
+		return 0, fmt.Errorf("unsupported interchange format version %q", doc.Metadata.Version)
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	added := 0
+	for _, delegate := range doc.Data {
+		for _, b := range delegate.SignedBlocks {
+			block := SignedBlock{Delegate: delegate.Delegate, Number: b.Number, Hash: b.Hash, WorkTime: b.WorkTime}
+			if db.known(block) {
+				continue
+			}
+			if err := db.append(block); err != nil {
+				return added, err
+			}
+			added++
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,156 @@
+package signer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+)
+
+func TestProtectionDB(t *testing.T) {
+	db, _ := OpenProtectionDB("")
+	delegate := common.Address{1}
+	block := SignedBlock{Delegate: delegate, Number: 10, Hash: common.Hash{1}, WorkTime: 100}
+	if err := db.Record(block); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Record(block); err != nil {
+		t.Errorf("recording the same block again failed: %v", err)
+	}
+
+	tests := []struct {
+		block SignedBlock
+		ok    bool
+	}{
+		{SignedBlock{Delegate: delegate, Number: 10, Hash: common.Hash{2}, WorkTime: 110}, false},
+		{SignedBlock{Delegate: delegate, Number: 11, Hash: common.Hash{2}, WorkTime: 100}, false},
+		{SignedBlock{Delegate: delegate, Number: 9, Hash: common.Hash{2}, WorkTime: 90}, false},
+		{SignedBlock{Delegate: common.Address{2}, Number: 10, Hash: common.Hash{2}, WorkTime: 100}, true},
+		{SignedBlock{Delegate: delegate, Number: 11, Hash: common.Hash{2}, WorkTime: 110}, true},
+	}
+	for i, tt := range tests {
+		if err := db.Check(tt.block); (err == nil) != tt.ok {
+			t.Errorf("test %d: allowed %v, want %v (%v)", i, err == nil, tt.ok, err)
+		}
+	}
+}
+
+func TestProtectionDBPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aoa-protection-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "protection.jsonl")
+
+	db, err := OpenProtectionDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := SignedBlock{Delegate: common.Address{1}, Number: 10, Hash: common.Hash{1}, WorkTime: 100}
+	if err := db.Record(block); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// a crash while appending leaves a torn last record
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	f.Write([]byte(`{"delegate":"0x01`))
+	f.Close()
+
+	db, err = OpenProtectionDB(path)
+	if err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	conflict := SignedBlock{Delegate: common.Address{1}, Number: 10, Hash: common.Hash{2}, WorkTime: 110}
+	if err := db.Check(conflict); err == nil {
+		t.Errorf("reopened record allowed a conflicting block")
+	}
+	next := SignedBlock{Delegate: common.Address{1}, Number: 11, Hash: common.Hash{2}, WorkTime: 110}
+	if err := db.Record(next); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = OpenProtectionDB(path)
+	if err != nil {
+		t.Fatalf("failed to reopen after torn record: %v", err)
+	}
+	defer db.Close()
+	if blocks := db.Blocks(); len(blocks) != 2 || blocks[0] != block || blocks[1] != next {
+		t.Errorf("blocks mismatch: have %+v", blocks)
+	}
+}
+
+func TestProtectionDBLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aoa-protection-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "protection.jsonl")
+
+	db, err := OpenProtectionDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, err := OpenProtectionDB(path); err == nil {
+		second.Close()
+		t.Fatalf("opened a record that is in use")
+	}
+	db.Close()
+	if err := db.Record(SignedBlock{Delegate: common.Address{1}, Number: 1, Hash: common.Hash{1}, WorkTime: 10}); err == nil {
+		t.Errorf("recorded a block after close")
+	}
+	db, err = OpenProtectionDB(path)
+	if err != nil {
+		t.Fatalf("failed to reopen after close: %v", err)
+	}
+	db.Close()
+}
+
+func TestProtectionInterchange(t *testing.T) {
+	src, _ := OpenProtectionDB("")
+	blocks := []SignedBlock{
+		{Delegate: common.Address{1}, Number: 10, Hash: common.Hash{1}, WorkTime: 100},
+		{Delegate: common.Address{1}, Number: 11, Hash: common.Hash{2}, WorkTime: 110},
+		{Delegate: common.Address{2}, Number: 12, Hash: common.Hash{3}, WorkTime: 120},
+	}
+	for _, block := range blocks {
+		if err := src.Record(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := src.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dst, _ := OpenProtectionDB("")
+	dst.Record(blocks[0])
+	added, err := dst.Import(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2 {
+		t.Errorf("added %d blocks, want 2", added)
+	}
+	have := dst.Blocks()
+	if len(have) != len(blocks) {
+		t.Fatalf("imported %d blocks, want %d", len(have), len(blocks))
+	}
+	for i := range blocks {
+		if have[i] != blocks[i] {
+			t.Errorf("block %d: have %+v, want %+v", i, have[i], blocks[i])
+		}
+	}
+	if err := dst.Check(SignedBlock{Delegate: common.Address{2}, Number: 12, Hash: common.Hash{4}, WorkTime: 130}); err == nil {
+		t.Errorf("imported record allowed a conflicting block")
+	}
+	if _, err := dst.Import(bytes.NewReader([]byte(`{"metadata":{"interchange_format_version":"0"},"data":[]}`))); err == nil {
+		t.Errorf("imported an unsupported version")
+	}
+}
@@ -0,0 +1,93 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rlp"
+	"github.com/Aurorachain/go-Aurora/rpc"
+)
+
+// signTimeout bounds a signing round trip, a block has to be out within its
+// slot.
+const signTimeout = 2 * time.Second
+
+var (
+	errForeignSignature = errors.New("remote signature is not from the requested key")
+	errSeedReveal       = errors.New("remote seed reveal has the wrong length")
+)
+
+// RemoteSigner is a BlockSigner that asks an external signer process, see
+// Service, over JSON-RPC.
+type RemoteSigner struct {
+	client *rpc.Client
+}
+
+// DialRemoteSigner connects to the signer at the IPC path endpoint. Signers are
+// not reachable over the network, their API has no authentication.
+func DialRemoteSigner(endpoint string) (*RemoteSigner, error) {
+	client, err := rpc.DialIPC(context.Background(), endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSigner(client), nil
+}
+
+func NewRemoteSigner(client *rpc.Client) *RemoteSigner {
+	return &RemoteSigner{client: client}
+}
+
+func (s *RemoteSigner) Accounts() ([]common.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+	defer cancel()
+	var accounts []common.Address
+	err := s.client.CallContext(ctx, &accounts, Namespace+"_accounts")
+	return accounts, err
+}
+
+// SignHeader asks the signer for the signature of header with key and checks
+// that it is from key.
+func (s *RemoteSigner) SignHeader(key common.Address, header *types.Header) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+	defer cancel()
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	var sig hexutil.Bytes
+	if err := s.client.CallContext(ctx, &sig, Namespace+"_signHeader", key, hexutil.Bytes(enc)); err != nil {
+		return nil, err
+	}
+	pub, err := crypto.SigToPub(header.Hash().Bytes(), sig)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.PubkeyToAddress(*pub) != key {
+		return nil, errForeignSignature
+	}
+	return sig, nil
+}
+
+// SeedReveal asks the signer for the seed reveal of key at index of epoch.
+func (s *RemoteSigner) SeedReveal(key common.Address, epoch, index uint64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), signTimeout)
+	defer cancel()
+	var reveal hexutil.Bytes
+	if err := s.client.CallContext(ctx, &reveal, Namespace+"_seedReveal", key, hexutil.Uint64(epoch), hexutil.Uint64(index)); err != nil {
+		return nil, err
+	}
+	if len(reveal) != params.SeedRevealLength {
+		return nil, errSeedReveal
+	}
+	return reveal, nil
+}
+
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
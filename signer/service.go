@@ -0,0 +1,63 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/rlp"
+)
+
+// Namespace is the JSON-RPC namespace of the signer API.
+const Namespace = "signer"
+
+var errInvalidHeader = errors.New("invalid header")
+
+// Service is the JSON-RPC API of a signer process:
+//
+//	signer_accounts   -> the keys the signer holds
+//	signer_signHeader -> the signature of a header with a key, if the policy
+//	                     allows it
+//	signer_seedReveal -> the seed reveal of a key at an index of an epoch
+type Service struct {
+	keys   *KeySigner
+	policy *Policy
+}
+
+func NewService(keys *KeySigner, policy *Policy) *Service {
+	return &Service{keys: keys, policy: policy}
+}
+
+func (s *Service) Accounts() []common.Address {
+	return s.keys.Accounts()
+}
+
+// SignHeader signs the RLP encoded header. The encoding is sent instead of the
+// JSON form so that the signer hashes exactly what the producer built.
+func (s *Service) SignHeader(key common.Address, enc hexutil.Bytes) (hexutil.Bytes, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(enc, header); err != nil || header.Number == nil {
+		return nil, errInvalidHeader
+	}
+	if !s.keys.Has(key) {
+		return nil, ErrUnknownDelegate
+	}
+	if err := s.policy.Allow(key, header); err != nil {
+		log.Warn("Refused to sign block", "delegate", header.Coinbase, "number", header.Number, "hash", header.Hash(), "err", err)
+		return nil, err
+	}
+	sig, err := s.keys.SignHeader(key, header)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Signed block", "delegate", header.Coinbase, "key", key, "number", header.Number, "hash", header.Hash())
+	return sig, nil
+}
+
+// SeedReveal returns the seed reveal of key at index of the hash chain of epoch.
+// Reveals are not slashable, the policy does not apply.
+func (s *Service) SeedReveal(key common.Address, epoch, index hexutil.Uint64) (hexutil.Bytes, error) {
+	return s.keys.SeedReveal(key, uint64(epoch), uint64(index))
+}
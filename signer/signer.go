@@ -0,0 +1,109 @@
+// Package signer signs the blocks produced by delegates, either with keys held
+// in process memory or through an external signer process.
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/util"
+)
+
+var (
+	ErrUnknownDelegate = errors.New("no key for delegate")
+	errSeedIndex       = errors.New("seed reveal index out of range")
+)
+
+// BlockSigner signs block headers. The signature is the one a block carries:
+// crypto.Sign over the header hash with key, the producer key of the header
+// coinbase. SeedReveal returns the seed reveal at index of the hash chain key
+// draws for epoch, see util.SeedReveal.
+type BlockSigner interface {
+	SignHeader(key common.Address, header *types.Header) ([]byte, error)
+	SeedReveal(key common.Address, epoch, index uint64) ([]byte, error)
+}
+
+// KeySigner is a BlockSigner holding unlocked keys in memory.
+type KeySigner struct {
+	mu   sync.RWMutex
+	keys map[common.Address]*ecdsa.PrivateKey
+}
+
+func NewKeySigner() *KeySigner {
+	return &KeySigner{keys: make(map[common.Address]*ecdsa.PrivateKey)}
+}
+
+// Add keeps key for signing and returns its address.
+func (s *KeySigner) Add(key *ecdsa.PrivateKey) common.Address {
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	s.mu.Lock()
+	s.keys[address] = key
+	s.mu.Unlock()
+	return address
+}
+
+func (s *KeySigner) Has(address common.Address) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.keys[address]
+	return ok
+}
+
+// Keys returns a copy of the held keys.
+func (s *KeySigner) Keys() map[common.Address]*ecdsa.PrivateKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make(map[common.Address]*ecdsa.PrivateKey, len(s.keys))
+	for address, key := range s.keys {
+		keys[address] = key
+	}
+	return keys
+}
+
+// Accounts returns the addresses of the held keys in ascending order.
+func (s *KeySigner) Accounts() []common.Address {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	accounts := make([]common.Address, 0, len(s.keys))
+	for address := range s.keys {
+		accounts = append(accounts, address)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return bytes.Compare(accounts[i][:], accounts[j][:]) < 0 })
+	return accounts
+}
+
+func (s *KeySigner) SignHeader(key common.Address, header *types.Header) ([]byte, error) {
+	s.mu.RLock()
+	priv, ok := s.keys[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownDelegate
+	}
+	return crypto.Sign(header.Hash().Bytes(), priv)
+}
+
+// SeedReveal draws the hash chain of epoch from a secret derived from the key,
+// so that it survives restarts and only the key holder knows it.
+func (s *KeySigner) SeedReveal(key common.Address, epoch, index uint64) ([]byte, error) {
+	s.mu.RLock()
+	priv, ok := s.keys[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownDelegate
+	}
+	if index >= params.SeedChainLength {
+		return nil, errSeedIndex
+	}
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], epoch)
+	secret := crypto.Keccak256([]byte("seed"), crypto.FromECDSA(priv), enc[:])
+	return util.SeedReveal(secret, params.SeedChainLength, index), nil
+}
@@ -0,0 +1,167 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/common/hexutil"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
+	"github.com/Aurorachain/go-Aurora/rpc"
+	"github.com/Aurorachain/go-Aurora/util"
+)
+
+func testHeader(coinbase common.Address, number int64, root byte) *types.Header {
+	return &types.Header{
+		Coinbase:           coinbase,
+		Root:               common.Hash{root},
+		Number:             big.NewInt(number),
+		Time:               big.NewInt(10 * number),
+		ShuffleBlockNumber: big.NewInt(0),
+	}
+}
+
+// startStub serves a signer over an in-process connection.
+func startStub(t *testing.T, service interface{}) *RemoteSigner {
+	server := rpc.NewServer()
+	if err := server.RegisterName(Namespace, service); err != nil {
+		t.Fatal(err)
+	}
+	return NewRemoteSigner(rpc.DialInProc(server))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keys := NewKeySigner()
+	delegate := keys.Add(key)
+	policy, _ := NewPolicy("")
+	remote := startStub(t, NewService(keys, policy))
+	defer remote.Close()
+
+	accounts, err := remote.Accounts()
+	if err != nil || len(accounts) != 1 || accounts[0] != delegate {
+		t.Fatalf("accounts mismatch: have %x (%v), want %x", accounts, err, delegate)
+	}
+
+	header := testHeader(delegate, 10, 1)
+	sig, err := remote.SignHeader(delegate, header)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	pub, err := crypto.SigToPub(header.Hash().Bytes(), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != delegate {
+		t.Errorf("signature is not from the delegate")
+	}
+	if _, err := remote.SignHeader(delegate, header); err != nil {
+		t.Errorf("signing the same block again failed: %v", err)
+	}
+	if _, err := remote.SignHeader(delegate, testHeader(delegate, 10, 2)); err == nil {
+		t.Errorf("signed a second block at the same height")
+	}
+	if _, err := remote.SignHeader(delegate, testHeader(delegate, 9, 1)); err == nil {
+		t.Errorf("signed a block below the watermark")
+	}
+	if _, err := remote.SignHeader(delegate, testHeader(delegate, 11, 1)); err != nil {
+		t.Errorf("failed to sign the next block: %v", err)
+	}
+	if _, err := remote.SignHeader(common.Address{1}, testHeader(common.Address{1}, 12, 1)); err == nil {
+		t.Errorf("signed with an unknown key")
+	}
+	// the key is the producer key of another delegate
+	if _, err := remote.SignHeader(delegate, testHeader(common.Address{1}, 12, 1)); err != nil {
+		t.Errorf("failed to sign with a producer key: %v", err)
+	}
+	// the watermark is the key's, another coinbase does not reset it
+	if _, err := remote.SignHeader(delegate, testHeader(common.Address{2}, 12, 2)); err == nil {
+		t.Errorf("signed a second block at the same height for another coinbase")
+	}
+}
+
+func TestRemoteSeedReveal(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keys := NewKeySigner()
+	delegate := keys.Add(key)
+	policy, _ := NewPolicy("")
+	remote := startStub(t, NewService(keys, policy))
+	defer remote.Close()
+
+	first, err := remote.SeedReveal(delegate, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to reveal: %v", err)
+	}
+	second, err := remote.SeedReveal(delegate, 0, 1)
+	if err != nil {
+		t.Fatalf("failed to reveal: %v", err)
+	}
+	if local, _ := keys.SeedReveal(delegate, 0, 1); !bytes.Equal(local, second) {
+		t.Errorf("remote reveal mismatch: have %x, want %x", second, local)
+	}
+	if !util.VerifySeedReveal(first, second) {
+		t.Errorf("reveal 1 does not follow reveal 0")
+	}
+	if next, _ := remote.SeedReveal(delegate, 1, 1); bytes.Equal(next, second) {
+		t.Errorf("epochs share their hash chain")
+	}
+	if _, err := remote.SeedReveal(delegate, 0, params.SeedChainLength); err == nil {
+		t.Errorf("revealed past the end of the hash chain")
+	}
+	if _, err := remote.SeedReveal(common.Address{1}, 0, 0); err == nil {
+		t.Errorf("revealed for an unknown key")
+	}
+}
+
+type ForeignStub struct {
+	key *ecdsa.PrivateKey
+}
+
+// SignHeader signs with a key that is not the requested one.
+func (s *ForeignStub) SignHeader(key common.Address, enc hexutil.Bytes) (hexutil.Bytes, error) {
+	return crypto.Sign(crypto.Keccak256(enc), s.key)
+}
+
+func TestRemoteSignerForeignSignature(t *testing.T) {
+	other, _ := crypto.GenerateKey()
+	remote := startStub(t, &ForeignStub{key: other})
+	defer remote.Close()
+
+	if _, err := remote.SignHeader(common.Address{1}, testHeader(common.Address{1}, 1, 1)); err != errForeignSignature {
+		t.Errorf("foreign signature: have %v, want %v", err, errForeignSignature)
+	}
+}
+
+func TestPolicyPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aoa-signer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "watermarks.json")
+	delegate := common.Address{1}
+
+	policy, err := NewPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := testHeader(delegate, 5, 1)
+	if err := policy.Allow(delegate, header); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mark, ok := reloaded.Watermark(delegate); !ok || mark.Number != 5 || mark.Hash != header.Hash() {
+		t.Errorf("watermark mismatch: have %+v", mark)
+	}
+	if err := reloaded.Allow(delegate, testHeader(delegate, 4, 1)); err == nil {
+		t.Errorf("reloaded policy allowed a lower height")
+	}
+}
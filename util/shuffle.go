@@ -3,9 +3,13 @@ package util
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
+	"github.com/Aurorachain/go-Aurora/common"
 	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
 	"github.com/Aurorachain/go-Aurora/log"
+	"github.com/Aurorachain/go-Aurora/params"
 	"math"
 	"strconv"
 	"time"
@@ -49,18 +53,69 @@ func ShuffleNewRound(beginTime int64, maxElectDelegate int, currentDposList []ty
 		maxElectDelegate = len(currentDposList)
 	}
 	log.Info("shuffle", "beginTime", beginTime, "delegateNumber", len(currentDposList),"delegateNumber",maxElectDelegate)
-	var newRoundList []types.ShuffleDel
 	truncDelegateList := Shuffle(beginTime+1, maxElectDelegate)
 	log.Debug("shuffle", "beginTime", time.Unix(beginTime, 0), "trunc", truncDelegateList)
+	return newRoundList(beginTime, truncDelegateList, currentDposList, blockInterval)
+}
 
-	for index := int64(0); index < int64(maxElectDelegate); index++ {
-		delegateIndex := truncDelegateList[index]
+// ShuffleNewRoundWithSeed is ShuffleNewRound with the order drawn from seed, the
+// seed mix of the state the round is elected in, instead of from the begin time
+// alone.
+func ShuffleNewRoundWithSeed(beginTime int64, maxElectDelegate int, currentDposList []types.Candidate, blockInterval int64, seed common.Hash) []types.ShuffleDel {
+	if len(currentDposList) < maxElectDelegate {
+		maxElectDelegate = len(currentDposList)
+	}
+	order := ShuffleWithSeed(seed, beginTime, maxElectDelegate)
+	log.Debug("shuffle", "beginTime", time.Unix(beginTime, 0), "seed", seed, "order", order)
+	return newRoundList(beginTime, order, currentDposList, blockInterval)
+}
+
+func newRoundList(beginTime int64, order []int, currentDposList []types.Candidate, blockInterval int64) []types.ShuffleDel {
+	var newRoundList []types.ShuffleDel
+	for index := int64(0); index < int64(len(order)); index++ {
+		delegateIndex := order[index]
 		workTime := beginTime + index*blockInterval
 		newRoundList = append(newRoundList, types.ShuffleDel{WorkTime: uint64(workTime), Address: currentDposList[delegateIndex].Address, Vote: currentDposList[delegateIndex].Vote, Nickname: currentDposList[delegateIndex].Nickname})
 	}
 	return newRoundList
 }
 
+// ShuffleWithSeed returns a permutation of the first delegateNumber delegates,
+// drawn by a Fisher-Yates shuffle from keccak256(seed, beginTime, i).
+func ShuffleWithSeed(seed common.Hash, beginTime int64, delegateNumber int) []int {
+	order := make([]int, delegateNumber)
+	for i := range order {
+		order[i] = i
+	}
+	var enc [16]byte
+	binary.BigEndian.PutUint64(enc[:8], uint64(beginTime))
+	for i := delegateNumber - 1; i > 0; i-- {
+		binary.BigEndian.PutUint64(enc[8:], uint64(i))
+		draw := binary.BigEndian.Uint64(crypto.Keccak256(seed[:], enc[:])[:8])
+		j := int(draw % uint64(i+1))
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
+
+// SeedReveal returns the reveal at index of the seed hash chain of length
+// reveals drawn from secret: secret hashed length-index times, each hash cut to
+// params.SeedRevealLength bytes. Every reveal hashes to the one before it, so
+// once the first is known the next one is fixed.
+func SeedReveal(secret []byte, length, index uint64) []byte {
+	reveal := secret
+	for i := index; i < length; i++ {
+		reveal = crypto.Keccak256(reveal)[:params.SeedRevealLength]
+	}
+	return reveal
+}
+
+// VerifySeedReveal reports whether reveal is the successor of prev in a seed
+// hash chain.
+func VerifySeedReveal(prev, reveal []byte) bool {
+	return len(reveal) == params.SeedRevealLength && bytes.Equal(crypto.Keccak256(reveal)[:params.SeedRevealLength], prev)
+}
+
 func CalShuffleTimeByHeaderTime(nextRoundBeginTime, blockTime,blockInterval,maxElectDelegate int64) int64 {
 	var count int64
 	nowUnix := time.Now().Unix()
@@ -2,9 +2,16 @@ package util
 
 import (
 	"fmt"
-	"github.com/Aurorachain/go-Aurora/core/types"
+	"math/big"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/Aurorachain/go-Aurora/common"
+	"github.com/Aurorachain/go-Aurora/core/types"
+	"github.com/Aurorachain/go-Aurora/crypto"
+	"github.com/Aurorachain/go-Aurora/params"
 )
 
 func TestShuffle(t *testing.T) {
@@ -32,7 +39,7 @@ func TestShuffleNewRound(t *testing.T) {
 	}
 
 	lastBlockTime := time.Now().Unix()
-	newRound := ShuffleNewRound(lastBlockTime, 10, initDelegate)
+	newRound := ShuffleNewRound(lastBlockTime, 10, initDelegate, 10)
 	for _, v := range newRound {
 		fmt.Println(v)
 	}
@@ -41,7 +48,113 @@ func TestShuffleNewRound(t *testing.T) {
 
 func TestCalShuffleTimeByHeaderTime(t *testing.T) {
 
-	shuffleTime := CalShuffleTimeByHeaderTime(3030, 2040)
-	fmt.Println(shuffleTime)
+	shuffleTime := CalShuffleTimeByHeaderTime(3030, 2040, 10, 10)
+	if shuffleTime != 2030 {
+		t.Errorf("shuffle time mismatch: have %d, want 2030", shuffleTime)
+	}
+
+}
+
+func TestShuffleWithSeedPermutation(t *testing.T) {
+	for n := 0; n <= 64; n++ {
+		order := ShuffleWithSeed(common.Hash{byte(n)}, 1530000000, n)
+		sorted := append([]int{}, order...)
+		sort.Ints(sorted)
+		for i, v := range sorted {
+			if v != i {
+				t.Fatalf("order of %d delegates is not a permutation: %v", n, order)
+			}
+		}
+	}
+}
+
+func TestShuffleWithSeedDeterministic(t *testing.T) {
+	seed := common.HexToHash("0x5eed")
+	if a, b := ShuffleWithSeed(seed, 100, 21), ShuffleWithSeed(seed, 100, 21); !reflect.DeepEqual(a, b) {
+		t.Errorf("same seed gave different orders: %v, %v", a, b)
+	}
+	base := ShuffleWithSeed(seed, 100, 21)
+	if reflect.DeepEqual(base, ShuffleWithSeed(common.HexToHash("0x5eee"), 100, 21)) {
+		t.Errorf("order does not depend on the seed")
+	}
+	if reflect.DeepEqual(base, ShuffleWithSeed(seed, 110, 21)) {
+		t.Errorf("order does not depend on the begin time")
+	}
+}
+
+// Every delegate should get every slot about equally often over many seeds.
+func TestShuffleWithSeedUniform(t *testing.T) {
+	const (
+		delegates = 5
+		rounds    = 20000
+	)
+	var counts [delegates][delegates]int
+	for r := 0; r < rounds; r++ {
+		seed := common.BigToHash(big.NewInt(int64(r)))
+		for slot, delegate := range ShuffleWithSeed(seed, 0, delegates) {
+			counts[delegate][slot]++
+		}
+	}
+	want := rounds / delegates
+	for delegate := range counts {
+		for slot, have := range counts[delegate] {
+			if have < want*9/10 || have > want*11/10 {
+				t.Errorf("delegate %d got slot %d %d times, want about %d", delegate, slot, have, want)
+			}
+		}
+	}
+}
+
+func TestSeedReveal(t *testing.T) {
+	secret := []byte("secret")
+	const length = 8
 
+	prev := SeedReveal(secret, length, 0)
+	if len(prev) != params.SeedRevealLength {
+		t.Fatalf("reveal length mismatch: have %d, want %d", len(prev), params.SeedRevealLength)
+	}
+	for index := uint64(1); index < length; index++ {
+		reveal := SeedReveal(secret, length, index)
+		if !VerifySeedReveal(prev, reveal) {
+			t.Fatalf("reveal %d does not follow reveal %d", index, index-1)
+		}
+		if VerifySeedReveal(reveal, prev) {
+			t.Errorf("reveal %d follows reveal %d", index-1, index)
+		}
+		prev = reveal
+	}
+	if other := SeedReveal([]byte("other"), length, 1); VerifySeedReveal(SeedReveal(secret, length, 0), other) {
+		t.Errorf("reveal of another secret accepted")
+	}
+	if VerifySeedReveal(SeedReveal(secret, length, 0), SeedReveal(secret, length, 2)) {
+		t.Errorf("skipped reveal accepted")
+	}
+	if VerifySeedReveal(crypto.Keccak256(secret)[:params.SeedRevealLength], secret) {
+		t.Errorf("reveal of the wrong length accepted")
+	}
+}
+
+func TestShuffleNewRoundWithSeed(t *testing.T) {
+	delegates := make([]types.Candidate, 7)
+	for i := range delegates {
+		delegates[i] = types.Candidate{Address: common.BigToAddress(big.NewInt(int64(i + 1))).Hex(), Vote: uint64(i)}
+	}
+	seed := common.HexToHash("0x5eed")
+
+	list := ShuffleNewRoundWithSeed(1000, 5, delegates, 10, seed)
+	if len(list) != 5 {
+		t.Fatalf("round length mismatch: have %d, want 5", len(list))
+	}
+	order := ShuffleWithSeed(seed, 1000, 5)
+	for i, del := range list {
+		if del.WorkTime != uint64(1000+10*i) {
+			t.Errorf("slot %d work time mismatch: have %d", i, del.WorkTime)
+		}
+		if del.Address != delegates[order[i]].Address || del.Vote != delegates[order[i]].Vote {
+			t.Errorf("slot %d delegate mismatch: have %s", i, del.Address)
+		}
+	}
+	if short := ShuffleNewRoundWithSeed(1000, 21, delegates, 10, seed); len(short) != len(delegates) {
+		t.Errorf("round of %d delegates has %d slots", len(delegates), len(short))
+	}
 }